@@ -2,33 +2,124 @@
 package bst
 
 import (
+	"cmp"
 	"fmt"
+	"iter"
+	"slices"
+	"strings"
 	"sync"
+	"unsafe"
 
 	"github.com/davidpogosian/ds/comparators"
+	"github.com/davidpogosian/ds/internal/format"
+	"github.com/davidpogosian/ds/set"
 )
 
 // Node struct represents a single item in the BST.
 // It has fields for a key and a value. The key is used
 // to determine where in the BST this node belongs.
-// It also have pointers to the left and right nodes.
+// It also have pointers to the left and right nodes, the
+// number of nodes in the subtree rooted at this node, the
+// generation of the BST that may modify it in place, and, in a BST
+// created by NewAggregated, the summary of its subtree and whether it is up to date.
 type Node[K any, V any] struct {
 	key K
 	val V
 	left *Node[K, V]
 	right *Node[K ,V]
+	size int
+	owner *generation
+	summary any
+	summarized bool
+}
+
+// generation identifies which BST owns a node. Nodes shared with a snapshot
+// are owned by neither BST and are copied before being modified.
+// It is not zero-sized, so every generation has a distinct address.
+type generation struct {
+	_ byte
+}
+
+// subtreeSize returns the number of nodes in the subtree rooted at n.
+// It returns 0 if n is nil.
+func subtreeSize[K, V any](n *Node[K, V]) int {
+	if n == nil {
+		return 0
+	}
+	return n.size
+}
+
+// Entry struct represents a key and value pair of the BST.
+type Entry[K, V any] struct {
+	Key K
+	Value V
 }
 
 // BST struct represents a binary search tree.
 // It has a pointer to the root node, a comparator function for comparing keys,
-// a field to keep track of its size, and a mutex for thread-safety.
+// an optional validator for rejecting keys the comparator cannot order,
+// a field to keep track of its size, the generation of the nodes it may modify
+// in place (nil until a Snapshot is taken), the summarizer of a BST created by
+// NewAggregated, and a mutex for thread-safety.
 type BST[K any, V any] struct {
 	root *Node[K, V]
 	comparator comparators.Comparator[K]
+	validator comparators.Validator[K]
 	size int
+	owner *generation
+	summarizer summarizer[K, V]
+	stringLimit int
 	mu sync.Mutex
 }
 
+// own returns a node that the BST may modify in place: n itself if the BST owns it,
+// otherwise a copy of n owned by the BST. The caller must link the result in place of n.
+// Every modification goes through own for the changed nodes and all their ancestors,
+// so it also marks the summary of the returned node as out of date.
+// It must be called with the mutex held.
+func (bst *BST[K, V]) own(n *Node[K, V]) *Node[K, V] {
+	if n.owner == bst.owner {
+		n.summarized = false
+		return n
+	}
+	c := *n
+	c.owner = bst.owner
+	c.summarized = false
+	return &c
+}
+
+// ownAll replaces every node in nodes with one the BST may modify in place.
+// It must be called with the mutex held.
+func (bst *BST[K, V]) ownAll(nodes []*Node[K, V]) {
+	for i, n := range nodes {
+		nodes[i] = bst.own(n)
+	}
+}
+
+// ownPath copies the path from the root down to the first node with the provided key,
+// so that every node on it may be modified in place, and returns that node.
+// It returns nil, without copying anything, if no node has the provided key.
+// It must be called with the mutex held.
+func (bst *BST[K, V]) ownPath(key K) *Node[K, V] {
+	if bst.find(key) == nil {
+		return nil
+	}
+	bst.root = bst.own(bst.root)
+	cursor := bst.root
+	for {
+		comparison := bst.comparator(key, cursor.key)
+		if comparison == -1 {
+			cursor.left = bst.own(cursor.left)
+			cursor = cursor.left
+		} else if comparison == 0 {
+			return cursor
+		} else {
+			cursor.right = bst.own(cursor.right)
+			cursor = cursor.right
+		}
+	}
+}
+
 // NewEmpty returns a pointer to a new empty BST.
 // NewEmpty requires a comparator function to compare elements.
 // For built-in types, the comparators package provides ready-made comparators
@@ -38,48 +129,130 @@ func NewEmpty[K, V any](comparator comparators.Comparator[K]) *BST[K, V] {
 	return &BST[K, V]{comparator: comparator}
 }
 
+// NewFromSortedSlice returns a pointer to a new, perfectly balanced BST holding the entries.
+// The entries must be in non-decreasing key order per the comparator, which lets the
+// BST be built in O(n) without any comparisons beyond the order check.
+// If the entries are not sorted, an error is returned.
+func NewFromSortedSlice[K, V any](entries []Entry[K, V], comparator comparators.Comparator[K]) (*BST[K, V], error) {
+	nodes := make([]*Node[K, V], len(entries))
+	for i, entry := range entries {
+		if i > 0 && comparator(entries[i - 1].Key, entry.Key) > 0 {
+			return nil, fmt.Errorf("Cannot build a BST from unsorted entries: key '%v' at index %d comes after '%v'.", entry.Key, i, entries[i - 1].Key)
+		}
+		nodes[i] = &Node[K, V]{key: entry.Key, val: entry.Value}
+	}
+	return &BST[K, V]{
+		root: buildBalanced(nodes),
+		size: len(nodes),
+		comparator: comparator,
+	}, nil
+}
+
+// buildBalanced links the nodes, which must be in in-order, into a perfectly
+// balanced tree and returns its root. It also sets the subtree sizes.
+func buildBalanced[K, V any](nodes []*Node[K, V]) *Node[K, V] {
+	if len(nodes) == 0 {
+		return nil
+	}
+	mid := len(nodes) / 2
+	n := nodes[mid]
+	n.left = buildBalanced(nodes[:mid])
+	n.right = buildBalanced(nodes[mid + 1:])
+	n.size = len(nodes)
+	return n
+}
+
+// Collect returns a pointer to a new BST holding the key and value pairs of seq.
+// The BST uses comparators.ComparatorOrdered to compare keys.
+func Collect[K cmp.Ordered, V any](seq iter.Seq2[K, V]) *BST[K, V] {
+	bst := NewEmpty[K, V](comparators.ComparatorOrdered[K])
+	for key, value := range seq {
+		bst.Insert(key, value)
+	}
+	return bst
+}
+
+// SetKeyValidator sets a validator that Insert calls on every key.
+// Keys rejected by the validator are not inserted. Passing nil removes the validator.
+// For built-in float types, the comparators package provides ready-made validators
+// (e.g., comparators.ValidatorFloat64 for float64).
+func (bst *BST[K, V]) SetKeyValidator(validator comparators.Validator[K]) {
+	bst.mu.Lock()
+	defer bst.mu.Unlock()
+	bst.validator = validator
+}
+
 // Insert inserts a new node into the BST with the provided key and value.
 // Duplicate keys are ok.
-func (bst *BST[K, V]) Insert(key K, value V) {
+// If a key validator is set and rejects the key, its error is returned.
+func (bst *BST[K, V]) Insert(key K, value V) (err error) {
+	defer comparators.Recover(&err)
 	bst.mu.Lock()
 	defer bst.mu.Unlock()
+	return bst.insert(key, value)
+}
+
+// insert inserts a new node into the BST. It must be called with the mutex held.
+func (bst *BST[K, V]) insert(key K, value V) error {
+	if bst.validator != nil {
+		if err := bst.validator(key); err != nil {
+			return fmt.Errorf("Cannot insert key '%v' into the BST: %w", key, err)
+		}
+	}
 	n := &Node[K, V]{
 		key: key,
 		val: value,
+		size: 1,
+		owner: bst.owner,
 	}
 	if bst.size == 0 {
 		bst.root = n
-	} else {
-		cursor := bst.root
-		for {
-			comparison := bst.comparator(n.key, cursor.key)
-			if comparison == -1 {
-				// go left
-				if cursor.left == nil {
-					cursor.left = n
-					break
-				} else {
-					cursor = cursor.left
-				}
+		bst.size++
+		return nil
+	}
+	// Find the insertion point with comparisons only, so that a panicking comparator
+	// leaves the BST unchanged, and only then copy the path and update the sizes.
+	var left []bool
+	cursor := bst.root
+	for cursor != nil {
+		goLeft := bst.comparator(n.key, cursor.key) == -1
+		left = append(left, goLeft)
+		if goLeft {
+			cursor = cursor.left
+		} else {
+			cursor = cursor.right
+		}
+	}
+	bst.root = bst.own(bst.root)
+	cursor = bst.root
+	for i, goLeft := range left {
+		cursor.size++
+		last := i == len(left) - 1
+		if goLeft {
+			// go left
+			if last {
+				cursor.left = n
+			} else {
+				cursor.left = bst.own(cursor.left)
+				cursor = cursor.left
+			}
+		} else {
+			// go right
+			if last {
+				cursor.right = n
 			} else {
-				// go right
-				if cursor.right == nil {
-					cursor.right = n
-					break
-				} else {
-					cursor = cursor.right
-				}
+				cursor.right = bst.own(cursor.right)
+				cursor = cursor.right
 			}
 		}
 	}
 	bst.size++
+	return nil
 }
 
-// Search returns the value of the first node with the provided key.
-// If no item with the provided key exists, an error is returned.
-func (bst *BST[K, V]) Search(key K) (V, error) {
-	bst.mu.Lock()
-	defer bst.mu.Unlock()
+// find returns the first node with the provided key, or nil if there is none.
+// It must be called with the mutex held.
+func (bst *BST[K, V]) find(key K) *Node[K, V] {
 	cursor := bst.root
 	for cursor != nil{
 		comparison := bst.comparator(key, cursor.key)
@@ -87,18 +260,90 @@ func (bst *BST[K, V]) Search(key K) (V, error) {
 			// go left
 			cursor = cursor.left
 		} else if comparison == 0 {
-			return cursor.val, nil
+			return cursor
 		} else {
 			// go right
 			cursor = cursor.right
 		}
 	}
+	return nil
+}
+
+// Search returns the value of the first node with the provided key.
+// If no item with the provided key exists, an error is returned.
+func (bst *BST[K, V]) Search(key K) (_ V, err error) {
+	defer comparators.Recover(&err)
+	value, ok := bst.TrySearch(key)
+	if !ok {
+		return value, fmt.Errorf("Key '%v' is not in the BST.", key)
+	}
+	return value, nil
+}
+
+// TrySearch returns the value of the first node with the provided key and true,
+// or returns false if no node has the provided key.
+// Unlike Search, it never allocates an error, so it does not recover panics
+// of a comparator wrapped with comparators.Recovering either.
+func (bst *BST[K, V]) TrySearch(key K) (V, bool) {
+	bst.mu.Lock()
+	defer bst.mu.Unlock()
+	if n := bst.find(key); n != nil {
+		return n.val, true
+	}
 	var zeroValue V
-	return zeroValue, fmt.Errorf("Key '%v' is not in the BST.", key)
+	return zeroValue, false
+}
+
+// Update replaces the value of the first node with the provided key.
+// If no node has the provided key, an error is returned.
+func (bst *BST[K, V]) Update(key K, value V) (err error) {
+	defer comparators.Recover(&err)
+	bst.mu.Lock()
+	defer bst.mu.Unlock()
+	n := bst.ownPath(key)
+	if n == nil {
+		return fmt.Errorf("Cannot update key '%v', it is not in the BST.", key)
+	}
+	n.val = value
+	return nil
+}
+
+// Upsert replaces the value of the first node with the provided key,
+// or inserts a new node if no node has that key. It returns true if a value was replaced.
+// Unlike Insert, Upsert never creates a duplicate key.
+// If a key validator is set and rejects the key, nothing is inserted and its error is returned.
+func (bst *BST[K, V]) Upsert(key K, value V) (replaced bool, err error) {
+	defer comparators.Recover(&err)
+	bst.mu.Lock()
+	defer bst.mu.Unlock()
+	if n := bst.ownPath(key); n != nil {
+		n.val = value
+		return true, nil
+	}
+	return false, bst.insert(key, value)
+}
+
+// GetOrInsert returns the value of the first node with the provided key.
+// If no node has that key, it inserts one with the value returned by valueFactory
+// and returns that value. valueFactory is called at most once, with the lock held,
+// so it must not call methods on the BST.
+// If a key validator is set and rejects the key, the new value is returned but not
+// inserted, along with the validator's error.
+func (bst *BST[K, V]) GetOrInsert(key K, valueFactory func() V) (_ V, err error) {
+	defer comparators.Recover(&err)
+	bst.mu.Lock()
+	defer bst.mu.Unlock()
+	if n := bst.find(key); n != nil {
+		return n.val, nil
+	}
+	value := valueFactory()
+	return value, bst.insert(key, value)
 }
 
 // removeHelper removes a given node and returns a pointer to
 // a node that will serve as its replacement.
+// n must be owned by the BST, and the caller is responsible for
+// the subtree sizes of the ancestors of n.
 func (bst *BST[K, V]) removeHelper(n *Node[K, V]) *Node[K, V] {
 	bst.size--
 	if n.left == nil && n.right == nil {
@@ -109,61 +354,87 @@ func (bst *BST[K, V]) removeHelper(n *Node[K, V]) *Node[K, V] {
 		return n.left
 	}
 	// get greatest node from the left subtree
+	n.left = bst.own(n.left)
 	replacementParent := n
 	replacement := n.left
 	for replacement.right != nil {
+		if replacementParent != n {
+			replacementParent.size--
+		}
+		replacement.right = bst.own(replacement.right)
 		replacementParent = replacement
 		replacement = replacement.right
 	}
-	replacement.right = n.right
-	if n.left != replacement {
+	if replacementParent != n {
+		replacementParent.size--
+		replacementParent.right = replacement.left
 		replacement.left = n.left
 	}
-	replacementParent.right = nil
+	replacement.right = n.right
+	replacement.size = n.size - 1
 	return replacement
 }
 
 // Remove removes the first node with the provided key and returns its value.
 // If no node has the provided key, an error is returned.
-func (bst *BST[K, V]) Remove(key K) (V, error) {
+func (bst *BST[K, V]) Remove(key K) (_ V, err error) {
+	defer comparators.Recover(&err)
+	bst.mu.Lock()
+	defer bst.mu.Unlock()
+	if val, ok := bst.remove(key); ok {
+		return val, nil
+	}
+	var zeroValue V
+	return zeroValue, fmt.Errorf("Key '%v' is not in the BST.", key)
+}
+
+// RemoveAll removes every node with the provided key and returns how many were removed.
+func (bst *BST[K, V]) RemoveAll(key K) int {
 	bst.mu.Lock()
 	defer bst.mu.Unlock()
+	removed := 0
+	for {
+		if _, ok := bst.remove(key); !ok {
+			return removed
+		}
+		removed++
+	}
+}
+
+// remove removes the first node with the provided key and returns its value.
+// It returns false if no node has the provided key. It must be called with the mutex held.
+func (bst *BST[K, V]) remove(key K) (V, bool) {
+	if bst.find(key) == nil {
+		var zeroValue V
+		return zeroValue, false
+	}
+	var parent *Node[K, V]
+	bst.root = bst.own(bst.root)
 	cursor := bst.root
-	for cursor != nil {
+	for {
 		comparison := bst.comparator(key, cursor.key)
+		if comparison == 0 {
+			break
+		}
+		cursor.size--
+		parent = cursor
 		if comparison == -1 {
-			if cursor.left == nil {
-				break
-			} else {
-				if bst.comparator(key, cursor.left.key) == 0 {
-					removeNode := cursor.left
-					cursor.left = bst.removeHelper(removeNode)
-					return removeNode.val, nil
-				} else {
-					cursor = cursor.left
-				}
-			}
-		} else if  comparison == 0 {
-			// Only reachable if removing root.
-			val := cursor.val
-			bst.root = bst.removeHelper(bst.root)
-			return val, nil
+			cursor.left = bst.own(cursor.left)
+			cursor = cursor.left
 		} else {
-			if cursor.right == nil {
-				break
-			} else {
-				if bst.comparator(key, cursor.right.key) == 0 {
-					removeNode := cursor.right
-					cursor.right = bst.removeHelper(removeNode)
-					return removeNode.val, nil
-				} else {
-					cursor = cursor.right
-				}
-			}
+			cursor.right = bst.own(cursor.right)
+			cursor = cursor.right
 		}
 	}
-	var zeroValue V
-	return zeroValue, fmt.Errorf("Key '%v' is not in the BST.", key)
+	replacement := bst.removeHelper(cursor)
+	if parent == nil {
+		bst.root = replacement
+	} else if parent.left == cursor {
+		parent.left = replacement
+	} else {
+		parent.right = replacement
+	}
+	return cursor.val, true
 }
 
 // Size returns the number of nodes in the BST.
@@ -189,6 +460,52 @@ func (bst *BST[K, V]) FindMin() (K, error) {
 	return cursor.key, nil
 }
 
+// PeekMin returns the minimum key in the BST and its value, i.e. those of the first
+// node DeleteMin would remove. If the BST is empty, an error is returned.
+func (bst *BST[K, V]) PeekMin() (K, V, error) {
+	bst.mu.Lock()
+	defer bst.mu.Unlock()
+	if bst.size == 0 {
+		var zeroKey K
+		var zeroValue V
+		return zeroKey, zeroValue, fmt.Errorf("Cannot peek min in an empty BST.")
+	}
+	cursor := bst.root
+	for cursor.left != nil {
+		cursor = cursor.left
+	}
+	return cursor.key, cursor.val, nil
+}
+
+// DeleteMin removes the node with the minimum key in O(height) and returns its key and value.
+// Among nodes with equal keys, the one inserted first is removed first.
+// If the BST is empty, an error is returned.
+func (bst *BST[K, V]) DeleteMin() (K, V, error) {
+	bst.mu.Lock()
+	defer bst.mu.Unlock()
+	if bst.size == 0 {
+		var zeroKey K
+		var zeroValue V
+		return zeroKey, zeroValue, fmt.Errorf("Cannot delete min from an empty BST.")
+	}
+	var parent *Node[K, V]
+	bst.root = bst.own(bst.root)
+	cursor := bst.root
+	for cursor.left != nil {
+		cursor.size--
+		parent = cursor
+		cursor.left = bst.own(cursor.left)
+		cursor = cursor.left
+	}
+	if parent == nil {
+		bst.root = cursor.right
+	} else {
+		parent.left = cursor.right
+	}
+	bst.size--
+	return cursor.key, cursor.val, nil
+}
+
 // FindMax returns the maximum key in the BST.
 // If the BST is empty, an error is returned.
 func (bst *BST[K, V]) FindMax() (K, error) {
@@ -205,11 +522,276 @@ func (bst *BST[K, V]) FindMax() (K, error) {
 	return cursor.key, nil
 }
 
-// InOrderTraversal returns a slice of the keys from the BST using in-order traversal.
-func (bst *BST[K, V]) InOrderTraversal() []K {
+// Rank returns the number of keys in the BST that are strictly less than the provided key.
+// The provided key does not need to be in the BST. It runs in O(height).
+func (bst *BST[K, V]) Rank(key K) int {
+	bst.mu.Lock()
+	defer bst.mu.Unlock()
+	return bst.rank(key, false)
+}
+
+// Count returns the number of nodes with the provided key. It runs in O(height).
+func (bst *BST[K, V]) Count(key K) int {
+	bst.mu.Lock()
+	defer bst.mu.Unlock()
+	return bst.rank(key, true) - bst.rank(key, false)
+}
+
+// CountBetween returns the number of nodes whose keys are between lo and hi, both inclusive.
+// It runs in O(height) without visiting the nodes in between, using the subtree sizes.
+// It returns 0 if lo is greater than hi.
+func (bst *BST[K, V]) CountBetween(lo K, hi K) int {
+	bst.mu.Lock()
+	defer bst.mu.Unlock()
+	return max(bst.rank(hi, true) - bst.rank(lo, false), 0)
+}
+
+// Aggregate describes a summary of the nodes of a BST, e.g. the total score of scored events,
+// that a BST created by NewAggregated keeps for every subtree, so that SumBetween runs in O(height).
+// Of returns the summary of a single node, and Combine joins the summaries of two consecutive
+// runs of nodes. Combine must be associative, with Zero as its identity, but need not be commutative.
+type Aggregate[K, V, A any] struct {
+	Of func(K, V) A
+	Combine func(A, A) A
+	Zero A
+}
+
+// summarizer computes the summary of a node from its own key and value and the
+// summaries of its children, which must be up to date.
+type summarizer[K, V any] interface {
+	summarize(n *Node[K, V]) any
+}
+
+// aggregator is the summarizer of a BST created by NewAggregated with an Aggregate of type A.
+type aggregator[K, V, A any] struct {
+	Aggregate[K, V, A]
+}
+
+// of returns the summary of the subtree rooted at n, which must be up to date.
+func (a *aggregator[K, V, A]) of(n *Node[K, V]) A {
+	if n == nil {
+		return a.Zero
+	}
+	return n.summary.(A)
+}
+
+// summarize returns the summary of the subtree rooted at n.
+func (a *aggregator[K, V, A]) summarize(n *Node[K, V]) any {
+	return a.Combine(a.Combine(a.of(n.left), a.Of(n.key, n.val)), a.of(n.right))
+}
+
+// NewAggregated returns a pointer to a new empty BST that keeps the given Aggregate
+// for every subtree, for use with SumBetween. Keeping it up to date costs O(1) extra
+// calls of Of and Combine per node changed by a modification, paid by the next SumBetween.
+func NewAggregated[K, V, A any](comparator comparators.Comparator[K], aggregate Aggregate[K, V, A]) *BST[K, V] {
+	return &BST[K, V]{comparator: comparator, summarizer: &aggregator[K, V, A]{aggregate}}
+}
+
+// summarize brings the summaries of the subtree rooted at n up to date. A node whose
+// summary is up to date has an up-to-date subtree, so only the nodes changed since the
+// last call are visited. It must be called with the mutex held.
+func (bst *BST[K, V]) summarize(n *Node[K, V]) {
+	if n == nil || n.summarized {
+		return
+	}
+	bst.summarize(n.left)
+	bst.summarize(n.right)
+	n.summary = bst.summarizer.summarize(n)
+	n.summarized = true
+}
+
+// SumBetween returns the Aggregate of the nodes of bst whose keys are between lo and hi,
+// both inclusive, combined in increasing key order, e.g. the total score of the events
+// in a time window:
+//
+//	events := bst.NewAggregated(comparators.ComparatorInt64, bst.Aggregate[int64, int, int]{
+//		Of: func(at int64, score int) int { return score },
+//		Combine: func(a, b int) int { return a + b },
+//	})
+//	total, err := bst.SumBetween[int64, int, int](events, from, to)
+//
+// Like CountBetween, it runs in O(height) without visiting the nodes in between, once the
+// summaries of the nodes changed since the last call have been brought up to date.
+// It returns Zero if lo is greater than hi. An error is returned if bst was not created by
+// NewAggregated with an Aggregate of type A.
+func SumBetween[K, V, A any](bst *BST[K, V], lo K, hi K) (A, error) {
+	bst.mu.Lock()
+	defer bst.mu.Unlock()
+	a, ok := bst.summarizer.(*aggregator[K, V, A])
+	if !ok {
+		var zeroValue A
+		return zeroValue, fmt.Errorf("Cannot sum a BST that does not keep an Aggregate of type %T.", &zeroValue)
+	}
+	bst.summarize(bst.root)
+	// Find the highest node in range; the rest of the range is in its two subtrees.
+	split := bst.root
+	for split != nil {
+		if bst.comparator(split.key, lo) < 0 {
+			split = split.right
+		} else if bst.comparator(split.key, hi) > 0 {
+			split = split.left
+		} else {
+			break
+		}
+	}
+	if split == nil {
+		return a.Zero, nil
+	}
+	// In the left subtree, every node at or above lo comes with its whole right subtree.
+	left := a.Zero
+	for n := split.left; n != nil; {
+		if bst.comparator(n.key, lo) >= 0 {
+			left = a.Combine(a.Combine(a.Of(n.key, n.val), a.of(n.right)), left)
+			n = n.left
+		} else {
+			n = n.right
+		}
+	}
+	// In the right subtree, every node at or below hi comes with its whole left subtree.
+	right := a.Zero
+	for n := split.right; n != nil; {
+		if bst.comparator(n.key, hi) <= 0 {
+			right = a.Combine(right, a.Combine(a.of(n.left), a.Of(n.key, n.val)))
+			n = n.right
+		} else {
+			n = n.left
+		}
+	}
+	return a.Combine(a.Combine(left, a.Of(split.key, split.val)), right), nil
+}
+
+// rank returns the number of keys less than the provided key, or less than
+// or equal to it if inclusive is true. It must be called with the mutex held.
+func (bst *BST[K, V]) rank(key K, inclusive bool) int {
+	rank := 0
+	cursor := bst.root
+	for cursor != nil {
+		comparison := bst.comparator(key, cursor.key)
+		if comparison < 0 || (!inclusive && comparison == 0) {
+			cursor = cursor.left
+		} else {
+			rank += subtreeSize(cursor.left) + 1
+			cursor = cursor.right
+		}
+	}
+	return rank
+}
+
+// Select returns the key and value of the k-th smallest node in the BST, counting from 0.
+// Together with Rank it answers order statistics such as medians and percentiles in O(height).
+// If k is out of bounds, an error is returned.
+func (bst *BST[K, V]) Select(k int) (K, V, error) {
+	bst.mu.Lock()
+	defer bst.mu.Unlock()
+	if k < 0 || k >= bst.size {
+		var zeroKey K
+		var zeroValue V
+		return zeroKey, zeroValue, fmt.Errorf("Cannot select index %d from a BST of size %d.", k, bst.size)
+	}
+	cursor := bst.root
+	for {
+		leftSize := subtreeSize(cursor.left)
+		if k < leftSize {
+			cursor = cursor.left
+		} else if k == leftSize {
+			return cursor.key, cursor.val, nil
+		} else {
+			k -= leftSize + 1
+			cursor = cursor.right
+		}
+	}
+}
+
+// nearest returns the node whose key is closest to the provided key on one side of it.
+// If below is true it looks for keys less than the provided key, otherwise for keys greater
+// than it. If inclusive is true, a key equal to the provided key also qualifies.
+// It returns nil if no key qualifies. It must be called with the mutex held.
+func (bst *BST[K, V]) nearest(key K, below bool, inclusive bool) *Node[K, V] {
+	var candidate *Node[K, V]
+	cursor := bst.root
+	for cursor != nil {
+		comparison := bst.comparator(cursor.key, key)
+		if below {
+			if comparison < 0 || (inclusive && comparison == 0) {
+				candidate = cursor
+				cursor = cursor.right
+			} else {
+				cursor = cursor.left
+			}
+		} else {
+			if comparison > 0 || (inclusive && comparison == 0) {
+				candidate = cursor
+				cursor = cursor.left
+			} else {
+				cursor = cursor.right
+			}
+		}
+	}
+	return candidate
+}
+
+// Floor returns the greatest key in the BST that is less than or equal to the provided key.
+// If there is no such key, an error is returned.
+func (bst *BST[K, V]) Floor(key K) (_ K, err error) {
+	defer comparators.Recover(&err)
+	bst.mu.Lock()
+	defer bst.mu.Unlock()
+	n := bst.nearest(key, true, true)
+	if n == nil {
+		var zeroValue K
+		return zeroValue, fmt.Errorf("No key in the BST is less than or equal to '%v'.", key)
+	}
+	return n.key, nil
+}
+
+// Ceiling returns the least key in the BST that is greater than or equal to the provided key.
+// If there is no such key, an error is returned.
+func (bst *BST[K, V]) Ceiling(key K) (_ K, err error) {
+	defer comparators.Recover(&err)
+	bst.mu.Lock()
+	defer bst.mu.Unlock()
+	n := bst.nearest(key, false, true)
+	if n == nil {
+		var zeroValue K
+		return zeroValue, fmt.Errorf("No key in the BST is greater than or equal to '%v'.", key)
+	}
+	return n.key, nil
+}
+
+// Successor returns the least key in the BST that is strictly greater than the provided key.
+// The provided key does not need to be in the BST.
+// If there is no such key, an error is returned.
+func (bst *BST[K, V]) Successor(key K) (_ K, err error) {
+	defer comparators.Recover(&err)
+	bst.mu.Lock()
+	defer bst.mu.Unlock()
+	n := bst.nearest(key, false, false)
+	if n == nil {
+		var zeroValue K
+		return zeroValue, fmt.Errorf("No key in the BST is greater than '%v'.", key)
+	}
+	return n.key, nil
+}
+
+// Predecessor returns the greatest key in the BST that is strictly less than the provided key.
+// The provided key does not need to be in the BST.
+// If there is no such key, an error is returned.
+func (bst *BST[K, V]) Predecessor(key K) (_ K, err error) {
+	defer comparators.Recover(&err)
 	bst.mu.Lock()
 	defer bst.mu.Unlock()
-	var slice []K
+	n := bst.nearest(key, true, false)
+	if n == nil {
+		var zeroValue K
+		return zeroValue, fmt.Errorf("No key in the BST is less than '%v'.", key)
+	}
+	return n.key, nil
+}
+
+// inOrder returns the nodes of the BST using in-order traversal.
+// It must be called with the mutex held.
+func (bst *BST[K, V]) inOrder() []*Node[K, V] {
+	var nodes []*Node[K, V]
 	stack := []*Node[K, V]{}
 	current := bst.root
 	for current != nil || len(stack) > 0 {
@@ -219,25 +801,24 @@ func (bst *BST[K, V]) InOrderTraversal() []K {
 		}
 		current = stack[len(stack)-1]
 		stack = stack[:len(stack)-1]
-		slice = append(slice, current.key)
+		nodes = append(nodes, current)
 		current = current.right
 	}
-	return slice
+	return nodes
 }
 
-// PreOrderTraversal returns a slice of the keys from the BST using pre-order traversal.
-func (bst *BST[K, V]) PreOrderTraversal() []K {
-	bst.mu.Lock()
-	defer bst.mu.Unlock()
-	var slice []K
+// preOrder returns the nodes of the BST using pre-order traversal.
+// It must be called with the mutex held.
+func (bst *BST[K, V]) preOrder() []*Node[K, V] {
+	var nodes []*Node[K, V]
 	if bst.root == nil {
-		return slice
+		return nodes
 	}
 	stack := []*Node[K, V]{bst.root}
 	for len(stack) > 0 {
 		node := stack[len(stack)-1]
 		stack = stack[:len(stack)-1]
-		slice = append(slice, node.key)
+		nodes = append(nodes, node)
 		if node.right != nil {
 			stack = append(stack, node.right)
 		}
@@ -245,16 +826,15 @@ func (bst *BST[K, V]) PreOrderTraversal() []K {
 			stack = append(stack, node.left)
 		}
 	}
-	return slice
+	return nodes
 }
 
-// PostOrderTraversal returns a slice of the keys from the BST using post-order traversal.
-func (bst *BST[K, V]) PostOrderTraversal() []K {
-	bst.mu.Lock()
-	defer bst.mu.Unlock()
-	var slice []K
+// postOrder returns the nodes of the BST using post-order traversal.
+// It must be called with the mutex held.
+func (bst *BST[K, V]) postOrder() []*Node[K, V] {
+	var nodes []*Node[K, V]
 	if bst.root == nil {
-		return slice
+		return nodes
 	}
 	s1 := []*Node[K, V]{bst.root}
 	s2 := []*Node[K, V]{}
@@ -272,15 +852,304 @@ func (bst *BST[K, V]) PostOrderTraversal() []K {
 	for len(s2) > 0 {
 		node := s2[len(s2)-1]
 		s2 = s2[:len(s2)-1]
-		slice = append(slice, node.key)
+		nodes = append(nodes, node)
 	}
-	return slice
+	return nodes
 }
 
-// nodeLevel represents a node and its level in the BST during BFS traversal.
-type nodeLevel[K, V any] struct {
-	node  *Node[K, V]
-	level int
+// levelOrder returns the nodes of the BST level by level, from left to right.
+// It must be called with the mutex held.
+func (bst *BST[K, V]) levelOrder() []*Node[K, V] {
+	var nodes []*Node[K, V]
+	if bst.root == nil {
+		return nodes
+	}
+	nodes = append(nodes, bst.root)
+	// nodes doubles as the BFS queue.
+	for i := 0; i < len(nodes); i++ {
+		if nodes[i].left != nil {
+			nodes = append(nodes, nodes[i].left)
+		}
+		if nodes[i].right != nil {
+			nodes = append(nodes, nodes[i].right)
+		}
+	}
+	return nodes
+}
+
+// keysOf returns the keys of the nodes. It returns nil if there are no nodes.
+func keysOf[K, V any](nodes []*Node[K, V]) []K {
+	if nodes == nil {
+		return nil
+	}
+	keys := make([]K, len(nodes))
+	for i, n := range nodes {
+		keys[i] = n.key
+	}
+	return keys
+}
+
+// valuesOf returns the values of the nodes. It returns nil if there are no nodes.
+func valuesOf[K, V any](nodes []*Node[K, V]) []V {
+	if nodes == nil {
+		return nil
+	}
+	values := make([]V, len(nodes))
+	for i, n := range nodes {
+		values[i] = n.val
+	}
+	return values
+}
+
+// entriesOf returns the key and value pairs of the nodes. It returns nil if there are no nodes.
+func entriesOf[K, V any](nodes []*Node[K, V]) []Entry[K, V] {
+	if nodes == nil {
+		return nil
+	}
+	entries := make([]Entry[K, V], len(nodes))
+	for i, n := range nodes {
+		entries[i] = Entry[K, V]{Key: n.key, Value: n.val}
+	}
+	return entries
+}
+
+// InOrderTraversal returns a slice of the keys from the BST using in-order traversal.
+func (bst *BST[K, V]) InOrderTraversal() []K {
+	bst.mu.Lock()
+	defer bst.mu.Unlock()
+	return keysOf(bst.inOrder())
+}
+
+// InOrderValues returns a slice of the values from the BST using in-order traversal.
+func (bst *BST[K, V]) InOrderValues() []V {
+	bst.mu.Lock()
+	defer bst.mu.Unlock()
+	return valuesOf(bst.inOrder())
+}
+
+// InOrderEntries returns a slice of the key and value pairs from the BST using in-order traversal.
+func (bst *BST[K, V]) InOrderEntries() []Entry[K, V] {
+	bst.mu.Lock()
+	defer bst.mu.Unlock()
+	return entriesOf(bst.inOrder())
+}
+
+// PreOrderTraversal returns a slice of the keys from the BST using pre-order traversal.
+func (bst *BST[K, V]) PreOrderTraversal() []K {
+	bst.mu.Lock()
+	defer bst.mu.Unlock()
+	return keysOf(bst.preOrder())
+}
+
+// PreOrderValues returns a slice of the values from the BST using pre-order traversal.
+func (bst *BST[K, V]) PreOrderValues() []V {
+	bst.mu.Lock()
+	defer bst.mu.Unlock()
+	return valuesOf(bst.preOrder())
+}
+
+// PreOrderEntries returns a slice of the key and value pairs from the BST using pre-order traversal.
+func (bst *BST[K, V]) PreOrderEntries() []Entry[K, V] {
+	bst.mu.Lock()
+	defer bst.mu.Unlock()
+	return entriesOf(bst.preOrder())
+}
+
+// PostOrderTraversal returns a slice of the keys from the BST using post-order traversal.
+func (bst *BST[K, V]) PostOrderTraversal() []K {
+	bst.mu.Lock()
+	defer bst.mu.Unlock()
+	return keysOf(bst.postOrder())
+}
+
+// PostOrderValues returns a slice of the values from the BST using post-order traversal.
+func (bst *BST[K, V]) PostOrderValues() []V {
+	bst.mu.Lock()
+	defer bst.mu.Unlock()
+	return valuesOf(bst.postOrder())
+}
+
+// PostOrderEntries returns a slice of the key and value pairs from the BST using post-order traversal.
+func (bst *BST[K, V]) PostOrderEntries() []Entry[K, V] {
+	bst.mu.Lock()
+	defer bst.mu.Unlock()
+	return entriesOf(bst.postOrder())
+}
+
+// LevelOrderTraversal returns a slice of the keys from the BST using level-order
+// (breadth-first) traversal, visiting each level from left to right.
+func (bst *BST[K, V]) LevelOrderTraversal() []K {
+	bst.mu.Lock()
+	defer bst.mu.Unlock()
+	return keysOf(bst.levelOrder())
+}
+
+// LevelOrderValues returns a slice of the values from the BST using level-order traversal.
+func (bst *BST[K, V]) LevelOrderValues() []V {
+	bst.mu.Lock()
+	defer bst.mu.Unlock()
+	return valuesOf(bst.levelOrder())
+}
+
+// LevelOrderEntries returns a slice of the key and value pairs from the BST using level-order traversal.
+func (bst *BST[K, V]) LevelOrderEntries() []Entry[K, V] {
+	bst.mu.Lock()
+	defer bst.mu.Unlock()
+	return entriesOf(bst.levelOrder())
+}
+
+// Order represents the order in which Traverse visits the nodes of a tree.
+type Order int
+
+const (
+	// InOrder visits the left subtree, then the node, then the right subtree,
+	// which yields the keys in increasing order.
+	InOrder Order = iota
+	// PreOrder visits the node, then the left subtree, then the right subtree.
+	PreOrder
+	// PostOrder visits the left subtree, then the right subtree, then the node.
+	PostOrder
+	// LevelOrder visits the nodes level by level (breadth-first), each level from left to right.
+	LevelOrder
+)
+
+// String returns the name of the Order.
+func (order Order) String() string {
+	switch order {
+	case InOrder:
+		return "InOrder"
+	case PreOrder:
+		return "PreOrder"
+	case PostOrder:
+		return "PostOrder"
+	case LevelOrder:
+		return "LevelOrder"
+	}
+	return fmt.Sprintf("Order(%d)", int(order))
+}
+
+// Traverse calls fn with the key and value of every node of the BST in the given order,
+// stopping early if fn returns false. It visits a snapshot taken before the first call,
+// so fn may call methods on the BST (including ones that modify it).
+// An error is returned, and fn is never called, if order is not a known Order.
+func (bst *BST[K, V]) Traverse(order Order, fn func(K, V) bool) error {
+	entries, err := bst.entriesIn(order)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if !fn(entry.Key, entry.Value) {
+			break
+		}
+	}
+	return nil
+}
+
+// entriesIn returns the key and value pairs of the BST in the given order.
+func (bst *BST[K, V]) entriesIn(order Order) ([]Entry[K, V], error) {
+	bst.mu.Lock()
+	defer bst.mu.Unlock()
+	switch order {
+	case InOrder:
+		return entriesOf(bst.inOrder()), nil
+	case PreOrder:
+		return entriesOf(bst.preOrder()), nil
+	case PostOrder:
+		return entriesOf(bst.postOrder()), nil
+	case LevelOrder:
+		return entriesOf(bst.levelOrder()), nil
+	}
+	return nil, fmt.Errorf("Cannot traverse a BST in unknown order %v.", order)
+}
+
+// All returns an iterator over the key and value pairs of the BST in increasing key order.
+//
+// The iterator is lazy: it walks the BST one node at a time and the lock is only held
+// while stepping from one node to the next, never while the loop body runs.
+// The loop body may therefore call methods on the BST (including ones that modify it)
+// without deadlocking. Nodes inserted or removed concurrently may or may not be observed.
+// For a consistent view, iterate over Copy() instead.
+func (bst *BST[K, V]) All() iter.Seq2[K, V] {
+	return bst.walk(nil, true)
+}
+
+// Ascend returns an iterator over the key and value pairs of the BST whose keys are
+// greater than or equal to from, in increasing key order. It has the same lazy semantics as All.
+func (bst *BST[K, V]) Ascend(from K) iter.Seq2[K, V] {
+	return bst.walk(&from, true)
+}
+
+// Descend returns an iterator over the key and value pairs of the BST whose keys are
+// less than or equal to from, in decreasing key order. It has the same lazy semantics as All.
+func (bst *BST[K, V]) Descend(from K) iter.Seq2[K, V] {
+	return bst.walk(&from, false)
+}
+
+// walk returns an iterator over the BST in increasing (ascending) or decreasing key order,
+// starting at from if it is not nil.
+func (bst *BST[K, V]) walk(from *K, ascending bool) iter.Seq2[K, V] {
+	// inRange reports whether a node may be yielded, i.e. it is not before from.
+	inRange := func(n *Node[K, V]) bool {
+		if from == nil {
+			return true
+		}
+		comparison := bst.comparator(n.key, *from)
+		return (ascending && comparison >= 0) || (!ascending && comparison <= 0)
+	}
+	// toward returns the child of n that comes first in the walk, and away the other one.
+	toward := func(n *Node[K, V]) *Node[K, V] {
+		if ascending {
+			return n.left
+		}
+		return n.right
+	}
+	away := func(n *Node[K, V]) *Node[K, V] {
+		if ascending {
+			return n.right
+		}
+		return n.left
+	}
+	// start returns the path to the first node of the walk. It holds the lock with defer,
+	// since inRange calls the comparator, which may panic.
+	start := func() []*Node[K, V] {
+		bst.mu.Lock()
+		defer bst.mu.Unlock()
+		stack := []*Node[K, V]{}
+		cursor := bst.root
+		for cursor != nil {
+			if inRange(cursor) {
+				stack = append(stack, cursor)
+				cursor = toward(cursor)
+			} else {
+				cursor = away(cursor)
+			}
+		}
+		return stack
+	}
+	return func(yield func(K, V) bool) {
+		stack := start()
+		bst.mu.Lock()
+		for len(stack) > 0 {
+			n := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			for cursor := away(n); cursor != nil; cursor = toward(cursor) {
+				stack = append(stack, cursor)
+			}
+			key, val := n.key, n.val
+			bst.mu.Unlock()
+			if !yield(key, val) {
+				return
+			}
+			bst.mu.Lock()
+		}
+		bst.mu.Unlock()
+	}
+}
+
+// nodeLevel represents a node and its level in the BST during BFS traversal.
+type nodeLevel[K, V any] struct {
+	node  *Node[K, V]
+	level int
 }
 
 // Height returns the height of the BST.
@@ -314,6 +1183,331 @@ func (bst *BST[K, V]) Height() int {
 	return height
 }
 
+// Rebalance rebuilds the BST into a perfectly balanced tree in O(n), keeping its nodes.
+// This undoes the degeneration caused by inserting keys in sorted order.
+func (bst *BST[K, V]) Rebalance() {
+	bst.mu.Lock()
+	defer bst.mu.Unlock()
+	nodes := bst.inOrder()
+	bst.ownAll(nodes)
+	bst.root = buildBalanced(nodes)
+}
+
+// Split moves the nodes of the BST into two new balanced BSTs, the first holding the keys
+// less than the provided key and the second the keys greater than or equal to it.
+// The BST is left empty. Both new BSTs keep its comparator and key validator.
+// Split runs in O(n).
+func (bst *BST[K, V]) Split(key K) (*BST[K, V], *BST[K, V]) {
+	bst.mu.Lock()
+	defer bst.mu.Unlock()
+	nodes := bst.inOrder()
+	bst.ownAll(nodes)
+	i, _ := slices.BinarySearchFunc(nodes, key, func(n *Node[K, V], key K) int {
+		return bst.comparator(n.key, key)
+	})
+	less := &BST[K, V]{
+		root: buildBalanced(nodes[:i]),
+		size: i,
+		comparator: bst.comparator,
+		validator: bst.validator,
+		owner: bst.owner,
+		summarizer: bst.summarizer,
+		stringLimit: bst.stringLimit,
+	}
+	greaterOrEqual := &BST[K, V]{
+		root: buildBalanced(nodes[i:]),
+		size: len(nodes) - i,
+		comparator: bst.comparator,
+		validator: bst.validator,
+		owner: bst.owner,
+		summarizer: bst.summarizer,
+		stringLimit: bst.stringLimit,
+	}
+	bst.root = nil
+	bst.size = 0
+	return less, greaterOrEqual
+}
+
+// Merge inserts copies of all nodes of other into the BST and rebuilds it
+// into a balanced tree in O(n+m). Nodes of the BST come before nodes of other
+// with an equal key. Keys are compared with the comparator of the BST, and the
+// key validator is not applied. other is not modified.
+// other is snapshotted under its own lock first, so the two BSTs are never locked at once.
+func (bst *BST[K, V]) Merge(other *BST[K, V]) {
+	other.mu.Lock()
+	otherNodes := other.inOrderNodes()
+	other.mu.Unlock()
+	bst.mu.Lock()
+	defer bst.mu.Unlock()
+	nodes := bst.inOrder()
+	bst.ownAll(nodes)
+	merged := make([]*Node[K, V], 0, len(nodes) + len(otherNodes))
+	i, j := 0, 0
+	for i < len(nodes) || j < len(otherNodes) {
+		if j == len(otherNodes) || (i < len(nodes) && bst.comparator(nodes[i].key, otherNodes[j].key) <= 0) {
+			merged = append(merged, nodes[i])
+			i++
+		} else {
+			otherNodes[j].owner = bst.owner
+			merged = append(merged, &otherNodes[j])
+			j++
+		}
+	}
+	bst.root = buildBalanced(merged)
+	bst.size = len(merged)
+}
+
+// MergeWith inserts copies of the nodes of other whose keys are not in the BST, and replaces
+// the value of every key present in both with resolve(value in the BST, value in other),
+// using synchronized in-order walks in O(n+m). The BST is then rebuilt into a balanced tree.
+// Duplicate keys are paired up in in-order. Keys are compared with the comparator of the BST,
+// and the key validator is not applied. other is not modified.
+// other is snapshotted under its own lock first, so the two BSTs are never locked at once.
+func (bst *BST[K, V]) MergeWith(other *BST[K, V], resolve func(V, V) V) {
+	other.mu.Lock()
+	otherNodes := other.inOrderNodes()
+	other.mu.Unlock()
+	bst.mu.Lock()
+	defer bst.mu.Unlock()
+	nodes := bst.inOrder()
+	bst.ownAll(nodes)
+	merged := make([]*Node[K, V], 0, len(nodes) + len(otherNodes))
+	i, j := 0, 0
+	for i < len(nodes) || j < len(otherNodes) {
+		comparison := -1
+		if i == len(nodes) {
+			comparison = 1
+		} else if j < len(otherNodes) {
+			comparison = bst.comparator(nodes[i].key, otherNodes[j].key)
+		}
+		if comparison < 0 {
+			merged = append(merged, nodes[i])
+			i++
+		} else if comparison > 0 {
+			otherNodes[j].owner = bst.owner
+			merged = append(merged, &otherNodes[j])
+			j++
+		} else {
+			nodes[i].val = resolve(nodes[i].val, otherNodes[j].val)
+			merged = append(merged, nodes[i])
+			i++
+			j++
+		}
+	}
+	bst.root = buildBalanced(merged)
+	bst.size = len(merged)
+}
+
+// IntersectKeys returns the keys present in both the BST and other in increasing order,
+// using synchronized in-order walks in O(n+m). Duplicate keys are paired up in in-order,
+// so a key appears as many times as it does in the BST holding fewer copies of it.
+// Keys are compared with the comparator of the BST.
+// Each BST is locked only while its contents are read, never both at once.
+func (bst *BST[K, V]) IntersectKeys(other *BST[K, V]) []K {
+	other.mu.Lock()
+	otherNodes := other.inOrderNodes()
+	other.mu.Unlock()
+	bst.mu.Lock()
+	defer bst.mu.Unlock()
+	nodes := bst.inOrder()
+	keys := []K{}
+	i, j := 0, 0
+	for i < len(nodes) && j < len(otherNodes) {
+		comparison := bst.comparator(nodes[i].key, otherNodes[j].key)
+		if comparison < 0 {
+			i++
+		} else if comparison > 0 {
+			j++
+		} else {
+			keys = append(keys, nodes[i].key)
+			i++
+			j++
+		}
+	}
+	return keys
+}
+
+// Validate checks the invariants of the BST: that an in-order traversal yields keys in
+// non-decreasing order per the comparator, that every subtree size is correct, and that
+// the number of nodes matches Size. It returns an error describing the first violation found.
+// A comparator that is not a consistent total order typically shows up here.
+func (bst *BST[K, V]) Validate() (err error) {
+	defer comparators.Recover(&err)
+	bst.mu.Lock()
+	defer bst.mu.Unlock()
+	var previous *Node[K, V]
+	visited := 0
+	stack := []*Node[K, V]{}
+	current := bst.root
+	for current != nil || len(stack) > 0 {
+		for current != nil {
+			if len(stack) > bst.size {
+				return fmt.Errorf("The BST is deeper than its size of %d, it contains a cycle.", bst.size)
+			}
+			stack = append(stack, current)
+			current = current.left
+		}
+		current = stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		visited++
+		if visited > bst.size {
+			return fmt.Errorf("The BST has more nodes than its size of %d.", bst.size)
+		}
+		if previous != nil && bst.comparator(previous.key, current.key) > 0 {
+			return fmt.Errorf("Key '%v' comes before key '%v' in-order, but compares greater.", previous.key, current.key)
+		}
+		if current.size != subtreeSize(current.left) + subtreeSize(current.right) + 1 {
+			return fmt.Errorf("Node with key '%v' records a subtree size of %d, but has %d nodes below it.", current.key, current.size, subtreeSize(current.left) + subtreeSize(current.right))
+		}
+		previous = current
+		current = current.right
+	}
+	if visited != bst.size {
+		return fmt.Errorf("The BST has %d nodes, but its size is %d.", visited, bst.size)
+	}
+	return nil
+}
+
+// Stats struct represents the shape of a BST.
+// Height is -1 for an empty BST. BalanceFactors maps a balance factor, the height of
+// the left subtree minus the height of the right subtree, to the number of nodes with it.
+// In a well-balanced BST almost all balance factors are -1, 0 or 1.
+type Stats struct {
+	Size int
+	Height int
+	Leaves int
+	BalanceFactors map[int]int
+}
+
+// Stats returns the size, height, leaf count and balance factor distribution of the BST.
+func (bst *BST[K, V]) Stats() Stats {
+	bst.mu.Lock()
+	defer bst.mu.Unlock()
+	stats := Stats{Size: bst.size, BalanceFactors: make(map[int]int)}
+	heights := make(map[*Node[K, V]]int, bst.size)
+	height := func(n *Node[K, V]) int {
+		if n == nil {
+			return -1
+		}
+		return heights[n]
+	}
+	for _, n := range bst.postOrder() {
+		left, right := height(n.left), height(n.right)
+		heights[n] = max(left, right) + 1
+		stats.BalanceFactors[left - right]++
+		if n.left == nil && n.right == nil {
+			stats.Leaves++
+		}
+	}
+	stats.Height = height(bst.root)
+	return stats
+}
+
+// String returns an indented tree view of the keys of the BST, one node per line,
+// with the left child listed before the right child. When a node has only one child,
+// the missing side is shown as "(nil)". An empty BST is shown as "(empty)".
+// If a limit was set with SetStringLimit, only that many nodes are printed.
+func (bst *BST[K, V]) String() string {
+	bst.mu.Lock()
+	defer bst.mu.Unlock()
+	if bst.stringLimit > 0 {
+		return bst.stringN(bst.stringLimit)
+	}
+	return bst.stringN(-1)
+}
+
+// StringN returns the tree view of the first max nodes of the BST, in the order String
+// lists them, followed by a "… (+N more)" line if nodes were left out.
+// If max is negative, all nodes are printed.
+func (bst *BST[K, V]) StringN(max int) string {
+	bst.mu.Lock()
+	defer bst.mu.Unlock()
+	return bst.stringN(max)
+}
+
+// stringN returns the tree view of the first max nodes of the BST.
+func (bst *BST[K, V]) stringN(max int) string {
+	if bst.root == nil {
+		return "(empty)"
+	}
+	limit := format.Limit(bst.size, max)
+	var sb strings.Builder
+	if limit > 0 {
+		fmt.Fprintf(&sb, "%v\n", bst.root.key)
+		budget := limit - 1
+		writeChildren(&sb, bst.root, "", &budget)
+	}
+	if limit < bst.size {
+		fmt.Fprintf(&sb, "%s\n", format.More(bst.size - limit))
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// SetStringLimit limits String to printing the first max nodes of the BST.
+// A max of 0 or less removes the limit.
+func (bst *BST[K, V]) SetStringLimit(max int) {
+	bst.mu.Lock()
+	defer bst.mu.Unlock()
+	bst.stringLimit = max
+}
+
+// writeChildren writes the subtrees of n to sb, each line starting with prefix,
+// stopping once budget more nodes have been written.
+func writeChildren[K, V any](sb *strings.Builder, n *Node[K, V], prefix string, budget *int) {
+	if n.left == nil && n.right == nil {
+		return
+	}
+	children := []*Node[K, V]{n.left, n.right}
+	for i, child := range children {
+		if *budget == 0 {
+			return
+		}
+		branch, indent := "├── ", "│   "
+		if i == len(children) - 1 {
+			branch, indent = "└── ", "    "
+		}
+		if child == nil {
+			fmt.Fprintf(sb, "%s%s(nil)\n", prefix, branch)
+			continue
+		}
+		fmt.Fprintf(sb, "%s%s%v\n", prefix, branch, child.key)
+		*budget--
+		writeChildren(sb, child, prefix + indent, budget)
+	}
+}
+
+// ToDOT returns the BST in the Graphviz DOT language, labelling every node with its key.
+// When a node has only one child, an invisible node takes the place of the missing one
+// so that left and right children are drawn on the correct side.
+func (bst *BST[K, V]) ToDOT() string {
+	bst.mu.Lock()
+	defer bst.mu.Unlock()
+	var sb strings.Builder
+	sb.WriteString("digraph BST {\n")
+	ids := make(map[*Node[K, V]]int, bst.size)
+	nodes := bst.preOrder()
+	for i, n := range nodes {
+		ids[n] = i
+		fmt.Fprintf(&sb, "\tn%d [label=%q];\n", i, fmt.Sprint(n.key))
+	}
+	invisible := 0
+	for _, n := range nodes {
+		if n.left == nil && n.right == nil {
+			continue
+		}
+		for _, child := range []*Node[K, V]{n.left, n.right} {
+			if child == nil {
+				fmt.Fprintf(&sb, "\tnil%d [style=invis];\n\tn%d -> nil%d [style=invis];\n", invisible, ids[n], invisible)
+				invisible++
+			} else {
+				fmt.Fprintf(&sb, "\tn%d -> n%d;\n", ids[n], ids[child])
+			}
+		}
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
 // Clear removes all nodes from the BST.
 func (bst *BST[K, V]) Clear() {
     bst.mu.Lock()
@@ -336,6 +1530,9 @@ func (bst *BST[K, V]) Copy() *BST[K, V] {
 	if bst.root == nil {
 		return &BST[K, V]{
 			comparator: bst.comparator,
+			validator:  bst.validator,
+			summarizer: bst.summarizer,
+			stringLimit: bst.stringLimit,
 		}
 	}
 	copyNode := func(node *Node[K, V]) *Node[K, V] {
@@ -347,6 +1544,7 @@ func (bst *BST[K, V]) Copy() *BST[K, V] {
 			val:   node.val,
 			left:  nil,
 			right: nil,
+			size:  node.size,
 		}
 	}
 	copiedRoot := copyNode(bst.root)
@@ -380,5 +1578,152 @@ func (bst *BST[K, V]) Copy() *BST[K, V] {
 		root:       copiedRoot,
 		size:       bst.size,
 		comparator: bst.comparator,
+		validator:  bst.validator,
+		summarizer: bst.summarizer,
+		stringLimit: bst.stringLimit,
+	}
+}
+
+// Snapshot returns a pointer to a BST holding the same nodes as this BST, in O(1).
+// The two BSTs share their nodes until either one is modified; a modification copies
+// only the nodes on the path it changes (path copying), so neither BST ever observes
+// the other's writes. This lets readers iterate a consistent view, e.g. with All,
+// without holding up writers to this BST for the whole traversal.
+func (bst *BST[K, V]) Snapshot() *BST[K, V] {
+	bst.mu.Lock()
+	defer bst.mu.Unlock()
+	if bst.summarizer != nil {
+		// Shared nodes are never modified, so their summaries must be up to date before sharing.
+		bst.summarize(bst.root)
+	}
+	// Neither BST owns the shared nodes from now on.
+	bst.owner = &generation{}
+	return &BST[K, V]{
+		root: bst.root,
+		size: bst.size,
+		comparator: bst.comparator,
+		validator: bst.validator,
+		owner: &generation{},
+		summarizer: bst.summarizer,
+		stringLimit: bst.stringLimit,
+	}
+}
+
+// inOrderNodes returns copies of the nodes of the BST in in-order,
+// with their child pointers cleared. It must be called with the mutex held.
+func (bst *BST[K, V]) inOrderNodes() []Node[K, V] {
+	nodes := make([]Node[K, V], 0, bst.size)
+	stack := []*Node[K, V]{}
+	current := bst.root
+	for current != nil || len(stack) > 0 {
+		for current != nil {
+			stack = append(stack, current)
+			current = current.left
+		}
+		current = stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		nodes = append(nodes, Node[K, V]{key: current.key, val: current.val})
+		current = current.right
+	}
+	return nodes
+}
+
+// ToMap returns the key and value pairs of the BST as a map.
+// For duplicate keys, the value of the first one in in-order is kept.
+// Use InOrderEntries to keep every pair.
+func ToMap[K comparable, V any](bst *BST[K, V]) map[K]V {
+	bst.mu.Lock()
+	defer bst.mu.Unlock()
+	m := make(map[K]V, bst.size)
+	for _, n := range bst.inOrder() {
+		if _, exists := m[n.key]; !exists {
+			m[n.key] = n.val
+		}
+	}
+	return m
+}
+
+// KeySet returns a pointer to a new Set holding the distinct keys of the BST.
+func KeySet[K comparable, V any](bst *BST[K, V]) *set.Set[K] {
+	bst.mu.Lock()
+	keys := keysOf(bst.inOrder())
+	bst.mu.Unlock()
+	return set.NewFromSlice(keys)
+}
+
+// DiffResult struct represents the differences between two BSTs.
+// OnlyInA and OnlyInB hold the keys present in only one of the BSTs,
+// and Changed holds the keys present in both but with differing values.
+// All three slices are in increasing key order.
+type DiffResult[K any] struct {
+	OnlyInA []K
+	OnlyInB []K
+	Changed []K
+}
+
+// Diff compares two BSTs using synchronized in-order walks in O(n+m).
+// Keys are compared with the comparator of a, and values with the provided
+// value comparator. Duplicate keys are paired up in in-order.
+// Each BST is locked only while its contents are read, never both at once.
+func Diff[K, V any](a, b *BST[K, V], valueComparator comparators.Comparator[V]) DiffResult[K] {
+	a.mu.Lock()
+	nodesA := a.inOrderNodes()
+	comparator := a.comparator
+	a.mu.Unlock()
+	b.mu.Lock()
+	nodesB := b.inOrderNodes()
+	b.mu.Unlock()
+	var result DiffResult[K]
+	i, j := 0, 0
+	for i < len(nodesA) && j < len(nodesB) {
+		comparison := comparator(nodesA[i].key, nodesB[j].key)
+		if comparison < 0 {
+			result.OnlyInA = append(result.OnlyInA, nodesA[i].key)
+			i++
+		} else if comparison > 0 {
+			result.OnlyInB = append(result.OnlyInB, nodesB[j].key)
+			j++
+		} else {
+			if valueComparator(nodesA[i].val, nodesB[j].val) != 0 {
+				result.Changed = append(result.Changed, nodesA[i].key)
+			}
+			i++
+			j++
+		}
+	}
+	for ; i < len(nodesA); i++ {
+		result.OnlyInA = append(result.OnlyInA, nodesA[i].key)
+	}
+	for ; j < len(nodesB); j++ {
+		result.OnlyInB = append(result.OnlyInB, nodesB[j].key)
+	}
+	return result
+}
+
+// MemUsage returns an approximation of the number of bytes used by the BST,
+// including its nodes. Memory referenced by the keys and values themselves
+// (e.g. the contents of strings) is not counted; use MemUsageFunc for that.
+func (bst *BST[K, V]) MemUsage() int64 {
+	return bst.MemUsageFunc(nil, nil)
+}
+
+// MemUsageFunc is like MemUsage, but also adds the result of calling keySize on every key
+// and valueSize on every value. They should return the bytes referenced beyond the fixed size.
+// Either function may be nil.
+func (bst *BST[K, V]) MemUsageFunc(keySize func(K) int64, valueSize func(V) int64) int64 {
+	bst.mu.Lock()
+	defer bst.mu.Unlock()
+	var n Node[K, V]
+	total := int64(unsafe.Sizeof(*bst)) + int64(bst.size) * int64(unsafe.Sizeof(n))
+	if keySize != nil || valueSize != nil {
+		for _, node := range bst.inOrderNodes() {
+			if keySize != nil {
+				total += keySize(node.key)
+			}
+			if valueSize != nil {
+				total += valueSize(node.val)
+			}
+		}
 	}
+	return total
 }
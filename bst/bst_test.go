@@ -1,6 +1,14 @@
 package bst
 
 import (
+	"errors"
+	"fmt"
+	"iter"
+	"maps"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"strings"
 	"testing"
 
 	"github.com/davidpogosian/ds/comparators"
@@ -270,3 +278,1046 @@ func TestCopy(t *testing.T) {
 		testutils.AssertSlices(t, copy.PreOrderTraversal(), []int{10, 8, 6, 7, 12, 11, 13})
 	})
 }
+
+func TestDiff(t *testing.T) {
+	t.Run("Empty", func(t *testing.T) {
+		a := NewEmpty[int, string](comparators.ComparatorInt)
+		b := NewEmpty[int, string](comparators.ComparatorInt)
+		diff := Diff(a, b, comparators.ComparatorString)
+		testutils.Assert(t, "len(diff.OnlyInA)", 0, len(diff.OnlyInA))
+		testutils.Assert(t, "len(diff.OnlyInB)", 0, len(diff.OnlyInB))
+		testutils.Assert(t, "len(diff.Changed)", 0, len(diff.Changed))
+	})
+
+	t.Run("NotEmpty", func(t *testing.T) {
+		a := NewEmpty[int, string](comparators.ComparatorInt)
+		b := NewEmpty[int, string](comparators.ComparatorInt)
+		for _, key := range []int{4, 2, 6, 1, 5} {
+			a.Insert(key, "a")
+		}
+		for _, key := range []int{4, 3, 6, 7} {
+			b.Insert(key, "a")
+		}
+		b.Insert(5, "b")
+		diff := Diff(a, b, comparators.ComparatorString)
+		testutils.AssertSlices(t, []int{1, 2}, diff.OnlyInA)
+		testutils.AssertSlices(t, []int{3, 7}, diff.OnlyInB)
+		testutils.AssertSlices(t, []int{5}, diff.Changed)
+	})
+
+	t.Run("Self", func(t *testing.T) {
+		a := NewEmpty[int, string](comparators.ComparatorInt)
+		a.Insert(1, "one")
+		diff := Diff(a, a, comparators.ComparatorString)
+		testutils.Assert(t, "len(diff.Changed)", 0, len(diff.Changed))
+	})
+}
+
+func TestMemUsage(t *testing.T) {
+	bst := NewEmpty[int, string](comparators.ComparatorInt)
+	empty := bst.MemUsage()
+	bst.Insert(1, "one")
+	bst.Insert(2, "two")
+	if bst.MemUsage() <= empty {
+		t.Fatal("Expected MemUsage to grow after inserting.")
+	}
+	extra := bst.MemUsageFunc(nil, func(v string) int64 { return int64(len(v)) }) - bst.MemUsage()
+	testutils.Assert(t, "extra", int64(6), extra)
+}
+
+func TestSetKeyValidator(t *testing.T) {
+	bst := NewEmpty[float64, string](comparators.ComparatorFloat64)
+	if err := bst.Insert(math.NaN(), "nan"); err != nil {
+		t.Fatal("Rejected a key without a validator.")
+	}
+	bst.SetKeyValidator(comparators.ValidatorFloat64)
+	err := bst.Insert(math.NaN(), "nan")
+	if !errors.Is(err, comparators.ErrIncomparable) {
+		t.Fatalf("Expected ErrIncomparable, instead got: %v", err)
+	}
+	testutils.Assert(t, "bst.Size()", 1, bst.Size())
+	if err := bst.Insert(1.5, "one and a half"); err != nil {
+		t.Fatal(err)
+	}
+	if err := bst.Copy().Insert(math.NaN(), "nan"); err == nil {
+		t.Fatal("Copy did not keep the key validator.")
+	}
+	bst.SetKeyValidator(nil)
+	if err := bst.Insert(math.NaN(), "nan"); err != nil {
+		t.Fatal("Rejected a key after removing the validator.")
+	}
+}
+
+func TestNearestKeys(t *testing.T) {
+	bst := NewEmpty[int, string](comparators.ComparatorInt)
+	for _, key := range []int{50, 30, 70, 20, 40, 60, 80} {
+		bst.Insert(key, "")
+	}
+	expectKey := func(t *testing.T, name string, expected int) func(int, error) {
+		return func(got int, err error) {
+			if err != nil {
+				t.Fatal(err)
+			}
+			testutils.Assert(t, name, expected, got)
+		}
+	}
+	expectError := func(t *testing.T, name string) func(int, error) {
+		return func(_ int, err error) {
+			if err == nil {
+				t.Fatalf("Expected an error from %s.", name)
+			}
+		}
+	}
+
+	t.Run("Floor", func(t *testing.T) {
+		expectKey(t, "bst.Floor(45)", 40)(bst.Floor(45))
+		expectKey(t, "bst.Floor(40)", 40)(bst.Floor(40))
+		expectKey(t, "bst.Floor(100)", 80)(bst.Floor(100))
+		expectError(t, "bst.Floor(10)")(bst.Floor(10))
+	})
+
+	t.Run("Ceiling", func(t *testing.T) {
+		expectKey(t, "bst.Ceiling(45)", 50)(bst.Ceiling(45))
+		expectKey(t, "bst.Ceiling(60)", 60)(bst.Ceiling(60))
+		expectKey(t, "bst.Ceiling(0)", 20)(bst.Ceiling(0))
+		expectError(t, "bst.Ceiling(81)")(bst.Ceiling(81))
+	})
+
+	t.Run("Successor", func(t *testing.T) {
+		expectKey(t, "bst.Successor(40)", 50)(bst.Successor(40))
+		expectKey(t, "bst.Successor(45)", 50)(bst.Successor(45))
+		expectKey(t, "bst.Successor(50)", 60)(bst.Successor(50))
+		expectError(t, "bst.Successor(80)")(bst.Successor(80))
+	})
+
+	t.Run("Predecessor", func(t *testing.T) {
+		expectKey(t, "bst.Predecessor(60)", 50)(bst.Predecessor(60))
+		expectKey(t, "bst.Predecessor(50)", 40)(bst.Predecessor(50))
+		expectKey(t, "bst.Predecessor(21)", 20)(bst.Predecessor(21))
+		expectError(t, "bst.Predecessor(20)")(bst.Predecessor(20))
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		empty := NewEmpty[int, string](comparators.ComparatorInt)
+		expectError(t, "empty.Floor(1)")(empty.Floor(1))
+		expectError(t, "empty.Successor(1)")(empty.Successor(1))
+	})
+}
+
+func TestRankSelect(t *testing.T) {
+	t.Run("Sequential", func(t *testing.T) {
+		bst := NewEmpty[int, string](comparators.ComparatorInt)
+		for _, key := range []int{50, 30, 70, 20, 40, 60, 80, 40} {
+			bst.Insert(key, "")
+		}
+		testutils.Assert(t, "bst.Rank(10)", 0, bst.Rank(10))
+		testutils.Assert(t, "bst.Rank(40)", 2, bst.Rank(40))
+		testutils.Assert(t, "bst.Rank(45)", 4, bst.Rank(45))
+		testutils.Assert(t, "bst.Rank(100)", 8, bst.Rank(100))
+		for i, expected := range []int{20, 30, 40, 40, 50, 60, 70, 80} {
+			key, _, err := bst.Select(i)
+			if err != nil {
+				t.Fatal(err)
+			}
+			testutils.Assert(t, "key", expected, key)
+		}
+		if _, _, err := bst.Select(8); err == nil {
+			t.Fatal("Selected an index out of bounds.")
+		}
+	})
+
+	t.Run("AfterRemove", func(t *testing.T) {
+		bst := NewEmpty[int, string](comparators.ComparatorInt)
+		keys := []int{50, 30, 70, 20, 40, 60, 80, 35, 45, 33, 37, 36}
+		for _, key := range keys {
+			bst.Insert(key, "")
+		}
+		for _, key := range []int{50, 40, 30} {
+			if _, err := bst.Remove(key); err != nil {
+				t.Fatal(err)
+			}
+		}
+		expected := []int{20, 33, 35, 36, 37, 45, 60, 70, 80}
+		testutils.AssertSlices(t, expected, bst.InOrderTraversal())
+		for i, key := range expected {
+			testutils.Assert(t, "bst.Rank(key)", i, bst.Rank(key))
+			got, _, err := bst.Select(i)
+			if err != nil {
+				t.Fatal(err)
+			}
+			testutils.Assert(t, "got", key, got)
+		}
+	})
+}
+
+func TestCollect(t *testing.T) {
+	bst := Collect(maps.All(map[string]int{"b": 2, "a": 1, "c": 3}))
+	testutils.Assert(t, "bst.Size()", 3, bst.Size())
+	testutils.AssertSlices(t, []string{"a", "b", "c"}, bst.InOrderTraversal())
+	two, err := bst.Search("b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutils.Assert(t, "two", 2, two)
+}
+
+func TestUpdate(t *testing.T) {
+	bst := NewEmpty[int, string](comparators.ComparatorInt)
+	bst.Insert(1, "one")
+	if err := bst.Update(1, "uno"); err != nil {
+		t.Fatal(err)
+	}
+	one, _ := bst.Search(1)
+	testutils.Assert(t, "one", "uno", one)
+	if err := bst.Update(2, "dos"); err == nil {
+		t.Fatal("Updated a key that is not in the BST.")
+	}
+	testutils.Assert(t, "bst.Size()", 1, bst.Size())
+}
+
+func TestUpsert(t *testing.T) {
+	t.Run("Sequential", func(t *testing.T) {
+		bst := NewEmpty[int, string](comparators.ComparatorInt)
+		replaced, err := bst.Upsert(1, "one")
+		if err != nil {
+			t.Fatal(err)
+		}
+		testutils.Assert(t, "replaced", false, replaced)
+		replaced, err = bst.Upsert(1, "uno")
+		if err != nil {
+			t.Fatal(err)
+		}
+		testutils.Assert(t, "replaced", true, replaced)
+		testutils.Assert(t, "bst.Size()", 1, bst.Size())
+		one, _ := bst.Search(1)
+		testutils.Assert(t, "one", "uno", one)
+	})
+
+	t.Run("Validator", func(t *testing.T) {
+		bst := NewEmpty[float64, string](comparators.ComparatorFloat64)
+		bst.SetKeyValidator(comparators.ValidatorFloat64)
+		replaced, err := bst.Upsert(math.NaN(), "nan")
+		if !errors.Is(err, comparators.ErrIncomparable) {
+			t.Fatalf("Expected ErrIncomparable, got %v.", err)
+		}
+		testutils.Assert(t, "replaced", false, replaced)
+		testutils.Assert(t, "bst.Size()", 0, bst.Size())
+	})
+
+	t.Run("Concurrent", func(t *testing.T) {
+		bst := NewEmpty[int, string](comparators.ComparatorInt)
+		testutils.ConcurrentOperations(t, 10, 100, func() error {
+			bst.Upsert(1, "one")
+			return nil
+		})
+		testutils.Assert(t, "bst.Size()", 1, bst.Size())
+	})
+}
+
+func TestTrySearch(t *testing.T) {
+	bst := NewEmpty[int, string](comparators.ComparatorInt)
+	if _, ok := bst.TrySearch(1); ok {
+		t.Fatal("Found a key in an empty BST.")
+	}
+	bst.Insert(1, "one")
+	value, ok := bst.TrySearch(1)
+	testutils.Assert(t, "ok", true, ok)
+	testutils.Assert(t, "value", "one", value)
+	if _, ok := bst.TrySearch(2); ok {
+		t.Fatal("Found a key that is not in the BST.")
+	}
+}
+
+func TestGetOrInsert(t *testing.T) {
+	bst := NewEmpty[int, string](comparators.ComparatorInt)
+	calls := 0
+	factory := func() string {
+		calls++
+		return "one"
+	}
+	for i := 0; i < 2; i++ {
+		value, err := bst.GetOrInsert(1, factory)
+		if err != nil {
+			t.Fatal(err)
+		}
+		testutils.Assert(t, "value", "one", value)
+	}
+	testutils.Assert(t, "calls", 1, calls)
+	testutils.Assert(t, "bst.Size()", 1, bst.Size())
+
+	floats := NewEmpty[float64, string](comparators.ComparatorFloat64)
+	floats.SetKeyValidator(comparators.ValidatorFloat64)
+	value, err := floats.GetOrInsert(math.NaN(), factory)
+	if !errors.Is(err, comparators.ErrIncomparable) {
+		t.Fatalf("Expected ErrIncomparable, got %v.", err)
+	}
+	testutils.Assert(t, "value", "one", value)
+	testutils.Assert(t, "floats.Size()", 0, floats.Size())
+}
+
+func TestCountBetween(t *testing.T) {
+	bst := NewEmpty[int, string](comparators.ComparatorInt)
+	testutils.Assert(t, "bst.CountBetween(0, 10)", 0, bst.CountBetween(0, 10))
+	for _, key := range []int{5, 3, 5, 8, 5, 1, 9, 2} {
+		bst.Insert(key, "")
+	}
+	testutils.Assert(t, "bst.CountBetween(2, 5)", 5, bst.CountBetween(2, 5))
+	testutils.Assert(t, "bst.CountBetween(5, 5)", 3, bst.CountBetween(5, 5))
+	testutils.Assert(t, "bst.CountBetween(6, 7)", 0, bst.CountBetween(6, 7))
+	testutils.Assert(t, "bst.CountBetween(0, 100)", 8, bst.CountBetween(0, 100))
+	testutils.Assert(t, "bst.CountBetween(8, 2)", 0, bst.CountBetween(8, 2))
+}
+
+func TestSumBetween(t *testing.T) {
+	sum := Aggregate[int, int, int]{
+		Of: func(key int, value int) int { return value },
+		Combine: func(a, b int) int { return a + b },
+	}
+	t.Run("Sequential", func(t *testing.T) {
+		bst := NewAggregated(comparators.ComparatorInt, sum)
+		total, err := SumBetween[int, int, int](bst, 0, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		testutils.Assert(t, "total", 0, total)
+		for _, key := range []int{5, 3, 5, 8, 5, 1, 9, 2} {
+			bst.Insert(key, key * 10)
+		}
+		for _, test := range []struct{ lo, hi, expected int }{
+			{2, 5, 200}, {6, 7, 0}, {0, 100, 380}, {8, 2, 0}, {5, 5, 150}, {9, 9, 90},
+		} {
+			total, _ := SumBetween[int, int, int](bst, test.lo, test.hi)
+			testutils.Assert(t, fmt.Sprintf("SumBetween(bst, %d, %d)", test.lo, test.hi), test.expected, total)
+		}
+	})
+
+	t.Run("Order", func(t *testing.T) {
+		// Concatenation is associative but not commutative, so it checks the order of the nodes.
+		keys := NewAggregated(comparators.ComparatorInt, Aggregate[int, string, []int]{
+			Of: func(key int, value string) []int { return []int{key} },
+			Combine: func(a, b []int) []int { return append(slices.Clip(a), b...) },
+		})
+		for _, key := range []int{5, 3, 5, 8, 5, 1, 9, 2} {
+			keys.Insert(key, "")
+		}
+		inRange, _ := SumBetween[int, string, []int](keys, 3, 8)
+		testutils.AssertSlices(t, []int{3, 5, 5, 5, 8}, inRange)
+	})
+
+	t.Run("Modifications", func(t *testing.T) {
+		bst := NewAggregated(comparators.ComparatorInt, sum)
+		values := map[int]int{}
+		check := func(tree *BST[int, int], values map[int]int) {
+			t.Helper()
+			for _, r := range [][2]int{{0, 200}, {20, 80}, {33, 34}, {50, 50}, {99, 150}} {
+				expected := 0
+				for key, value := range values {
+					if key >= r[0] && key <= r[1] {
+						expected += value
+					}
+				}
+				total, err := SumBetween[int, int, int](tree, r[0], r[1])
+				if err != nil {
+					t.Fatal(err)
+				}
+				testutils.Assert(t, fmt.Sprintf("SumBetween(%d, %d)", r[0], r[1]), expected, total)
+			}
+		}
+		for _, key := range rand.New(rand.NewPCG(1, 2)).Perm(100) {
+			bst.Insert(key, key)
+			values[key] = key
+		}
+		check(bst, values)
+		snapshot := bst.Snapshot()
+		snapshotValues := maps.Clone(values)
+		for key := 0; key < 100; key += 3 {
+			bst.Remove(key)
+			delete(values, key)
+		}
+		bst.Update(50, 500)
+		values[50] = 500
+		bst.Upsert(150, 1)
+		values[150] = 1
+		bst.DeleteMin()
+		delete(values, 1)
+		check(bst, values)
+		check(snapshot, snapshotValues)
+		bst.Rebalance()
+		check(bst, values)
+		less, greaterOrEqual := bst.Split(50)
+		check(less, maps.Collect(func(yield func(int, int) bool) {
+			for key, value := range values {
+				if key < 50 && !yield(key, value) {
+					return
+				}
+			}
+		}))
+		less.Merge(greaterOrEqual)
+		check(less, values)
+		check(less.Copy(), values)
+	})
+
+	t.Run("Height", func(t *testing.T) {
+		calls := 0
+		counted := Aggregate[int, int, int]{
+			Of: func(key int, value int) int { calls++; return value },
+			Combine: func(a, b int) int { calls++; return a + b },
+		}
+		bst := NewAggregated(comparators.ComparatorInt, counted)
+		for i := 0; i < 1 << 12; i++ {
+			bst.Insert(i, 1)
+		}
+		bst.Rebalance()
+		SumBetween[int, int, int](bst, 0, 0)
+		calls = 0
+		total, _ := SumBetween[int, int, int](bst, 10, 4000)
+		testutils.Assert(t, "total", 3991, total)
+		// Without scanning the range: a few calls per level of the tree, not one per node.
+		if calls > 6 * bst.Height() {
+			t.Fatalf("Expected O(height) calls, instead got %d for a height of %d.", calls, bst.Height())
+		}
+	})
+
+	t.Run("NotAggregated", func(t *testing.T) {
+		if _, err := SumBetween[int, int, int](NewEmpty[int, int](comparators.ComparatorInt), 0, 1); err == nil {
+			t.Fatal("Summed a BST without an Aggregate.")
+		}
+		bst := NewAggregated(comparators.ComparatorInt, sum)
+		if _, err := SumBetween[int, int, float64](bst, 0, 1); err == nil {
+			t.Fatal("Summed a BST with an Aggregate of another type.")
+		}
+	})
+
+	t.Run("Concurrent", func(t *testing.T) {
+		bst := NewAggregated(comparators.ComparatorInt, sum)
+		testutils.ConcurrentOperations(t, 10, 100, func() error {
+			bst.Insert(1, 1)
+			snapshot := bst.Snapshot()
+			if _, err := SumBetween[int, int, int](snapshot, 0, 10); err != nil {
+				return err
+			}
+			_, err := SumBetween[int, int, int](bst, 0, 10)
+			return err
+		})
+		total, _ := SumBetween[int, int, int](bst, 0, 10)
+		testutils.Assert(t, "total", 1000, total)
+	})
+}
+
+func TestCount(t *testing.T) {
+	bst := NewEmpty[int, string](comparators.ComparatorInt)
+	for _, key := range []int{5, 3, 5, 8, 5, 1} {
+		bst.Insert(key, "")
+	}
+	testutils.Assert(t, "bst.Count(5)", 3, bst.Count(5))
+	testutils.Assert(t, "bst.Count(3)", 1, bst.Count(3))
+	testutils.Assert(t, "bst.Count(4)", 0, bst.Count(4))
+}
+
+func TestRemoveAll(t *testing.T) {
+	t.Run("Sequential", func(t *testing.T) {
+		bst := NewEmpty[int, string](comparators.ComparatorInt)
+		for _, key := range []int{5, 3, 5, 8, 5, 1, 4} {
+			bst.Insert(key, "")
+		}
+		testutils.Assert(t, "bst.RemoveAll(5)", 3, bst.RemoveAll(5))
+		testutils.Assert(t, "bst.RemoveAll(5)", 0, bst.RemoveAll(5))
+		testutils.Assert(t, "bst.Size()", 4, bst.Size())
+		testutils.AssertSlices(t, []int{1, 3, 4, 8}, bst.InOrderTraversal())
+		testutils.Assert(t, "bst.Rank(8)", 3, bst.Rank(8))
+	})
+
+	t.Run("Concurrent", func(t *testing.T) {
+		bst := NewEmpty[int, string](comparators.ComparatorInt)
+		testutils.ConcurrentOperations(t, 10, 100, func() error {
+			bst.Insert(1, "")
+			bst.RemoveAll(1)
+			return nil
+		})
+		testutils.Assert(t, "bst.Size()", 0, bst.Size())
+	})
+}
+
+func TestAll(t *testing.T) {
+	t.Run("Sequential", func(t *testing.T) {
+		bst := NewEmpty[int, string](comparators.ComparatorInt)
+		for _, key := range []int{50, 30, 70, 20, 40, 60, 80} {
+			bst.Insert(key, fmt.Sprint(key))
+		}
+		var keys []int
+		for key, value := range bst.All() {
+			testutils.Assert(t, "value", fmt.Sprint(key), value)
+			keys = append(keys, key)
+		}
+		testutils.AssertSlices(t, []int{20, 30, 40, 50, 60, 70, 80}, keys)
+	})
+
+	t.Run("Break", func(t *testing.T) {
+		bst := NewEmpty[int, string](comparators.ComparatorInt)
+		for _, key := range []int{2, 1, 3} {
+			bst.Insert(key, "")
+		}
+		for range bst.All() {
+			break
+		}
+		// The lock must have been released.
+		testutils.Assert(t, "bst.Size()", 3, bst.Size())
+	})
+
+	t.Run("ModifyInBody", func(t *testing.T) {
+		bst := NewEmpty[int, string](comparators.ComparatorInt)
+		for _, key := range []int{2, 1, 3} {
+			bst.Insert(key, "")
+		}
+		for key := range bst.All() {
+			bst.Remove(key)
+		}
+		testutils.Assert(t, "bst.Size()", 0, bst.Size())
+	})
+}
+
+func TestAscendDescend(t *testing.T) {
+	bst := NewEmpty[int, string](comparators.ComparatorInt)
+	for _, key := range []int{50, 30, 70, 20, 40, 60, 80} {
+		bst.Insert(key, "")
+	}
+	keys := func(seq iter.Seq2[int, string]) []int {
+		var keys []int
+		for key := range seq {
+			keys = append(keys, key)
+		}
+		return keys
+	}
+	testutils.AssertSlices(t, []int{40, 50, 60, 70, 80}, keys(bst.Ascend(40)))
+	testutils.AssertSlices(t, []int{50, 60, 70, 80}, keys(bst.Ascend(45)))
+	testutils.AssertSlices(t, []int{}, keys(bst.Ascend(90)))
+	testutils.AssertSlices(t, []int{40, 30, 20}, keys(bst.Descend(40)))
+	testutils.AssertSlices(t, []int{60, 50, 40, 30, 20}, keys(bst.Descend(65)))
+	testutils.AssertSlices(t, []int{}, keys(bst.Descend(10)))
+}
+
+func TestDeleteMin(t *testing.T) {
+	bst := NewEmpty[int, string](comparators.ComparatorInt)
+	if _, _, err := bst.DeleteMin(); err == nil {
+		t.Fatal("Deleted min from an empty BST.")
+	}
+	if _, _, err := bst.PeekMin(); err == nil {
+		t.Fatal("Peeked min in an empty BST.")
+	}
+	for _, key := range []int{50, 30, 70, 20, 40, 30, 80} {
+		bst.Insert(key, fmt.Sprint(key))
+	}
+	snapshot := bst.Snapshot()
+	key, value, err := bst.PeekMin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutils.Assert(t, "key", 20, key)
+	testutils.Assert(t, "value", "20", value)
+	keys := []int{}
+	for bst.Size() > 0 {
+		key, _, err := bst.DeleteMin()
+		if err != nil {
+			t.Fatal(err)
+		}
+		keys = append(keys, key)
+		if err := bst.Validate(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	testutils.AssertSlices(t, []int{20, 30, 30, 40, 50, 70, 80}, keys)
+	testutils.Assert(t, "snapshot.Size()", 7, snapshot.Size())
+	testutils.AssertSlices(t, []int{20, 30, 30, 40, 50, 70, 80}, snapshot.InOrderTraversal())
+}
+
+func TestTraverse(t *testing.T) {
+	bst := NewEmpty[int, string](comparators.ComparatorInt)
+	for _, key := range []int{50, 30, 70, 20, 40, 80} {
+		bst.Insert(key, fmt.Sprint(key))
+	}
+	expected := map[Order][]int{
+		InOrder: {20, 30, 40, 50, 70, 80},
+		PreOrder: {50, 30, 20, 40, 70, 80},
+		PostOrder: {20, 40, 30, 80, 70, 50},
+		LevelOrder: {50, 30, 70, 20, 40, 80},
+	}
+	for order, keys := range expected {
+		t.Run(order.String(), func(t *testing.T) {
+			visited := []int{}
+			err := bst.Traverse(order, func(key int, value string) bool {
+				testutils.Assert(t, "value", fmt.Sprint(key), value)
+				visited = append(visited, key)
+				return true
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			testutils.AssertSlices(t, keys, visited)
+		})
+	}
+	t.Run("Stop", func(t *testing.T) {
+		visited := []int{}
+		bst.Traverse(InOrder, func(key int, value string) bool {
+			visited = append(visited, key)
+			return len(visited) < 2
+		})
+		testutils.AssertSlices(t, []int{20, 30}, visited)
+	})
+	t.Run("Modify", func(t *testing.T) {
+		bst.Traverse(PreOrder, func(key int, value string) bool {
+			bst.Remove(key)
+			return true
+		})
+		testutils.Assert(t, "bst.Size()", 0, bst.Size())
+	})
+	t.Run("UnknownOrder", func(t *testing.T) {
+		if err := bst.Traverse(Order(42), func(int, string) bool { return true }); err == nil {
+			t.Fatal("Traversed a BST in an unknown order.")
+		}
+		testutils.Assert(t, "Order(42).String()", "Order(42)", Order(42).String())
+	})
+}
+
+func TestLevelOrderTraversal(t *testing.T) {
+	bst := NewEmpty[int, string](comparators.ComparatorInt)
+	testutils.AssertSlices(t, []int{}, bst.LevelOrderTraversal())
+	for _, key := range []int{50, 30, 70, 20, 40, 80} {
+		bst.Insert(key, fmt.Sprint(key))
+	}
+	testutils.AssertSlices(t, []int{50, 30, 70, 20, 40, 80}, bst.LevelOrderTraversal())
+}
+
+func TestValuesAndEntries(t *testing.T) {
+	bst := NewEmpty[int, string](comparators.ComparatorInt)
+	for _, key := range []int{2, 1, 3} {
+		bst.Insert(key, fmt.Sprint(key))
+	}
+	testutils.AssertSlices(t, []string{"1", "2", "3"}, bst.InOrderValues())
+	testutils.AssertSlices(t, []string{"2", "1", "3"}, bst.PreOrderValues())
+	testutils.AssertSlices(t, []string{"1", "3", "2"}, bst.PostOrderValues())
+	testutils.AssertSlices(t, []string{"2", "1", "3"}, bst.LevelOrderValues())
+	testutils.AssertSlices(t, []Entry[int, string]{{1, "1"}, {2, "2"}, {3, "3"}}, bst.InOrderEntries())
+	testutils.AssertSlices(t, []Entry[int, string]{{2, "2"}, {1, "1"}, {3, "3"}}, bst.PreOrderEntries())
+	testutils.AssertSlices(t, []Entry[int, string]{{1, "1"}, {3, "3"}, {2, "2"}}, bst.PostOrderEntries())
+	testutils.AssertSlices(t, []Entry[int, string]{{2, "2"}, {1, "1"}, {3, "3"}}, bst.LevelOrderEntries())
+}
+
+func TestNewFromSortedSlice(t *testing.T) {
+	t.Run("Sorted", func(t *testing.T) {
+		entries := make([]Entry[int, string], 15)
+		for i := range entries {
+			entries[i] = Entry[int, string]{i, fmt.Sprint(i)}
+		}
+		bst, err := NewFromSortedSlice(entries, comparators.ComparatorInt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		testutils.Assert(t, "bst.Size()", 15, bst.Size())
+		testutils.Assert(t, "bst.Height()", 3, bst.Height())
+		testutils.AssertSlices(t, entries, bst.InOrderEntries())
+		seven, _, _ := bst.Select(7)
+		testutils.Assert(t, "seven", 7, seven)
+		bst.Insert(20, "20")
+		testutils.Assert(t, "bst.Rank(20)", 15, bst.Rank(20))
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		bst, err := NewFromSortedSlice[int, string](nil, comparators.ComparatorInt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		testutils.Assert(t, "bst.Size()", 0, bst.Size())
+	})
+
+	t.Run("Unsorted", func(t *testing.T) {
+		_, err := NewFromSortedSlice([]Entry[int, string]{{2, ""}, {1, ""}}, comparators.ComparatorInt)
+		if err == nil {
+			t.Fatal("Built a BST from unsorted entries.")
+		}
+	})
+}
+
+func TestRebalance(t *testing.T) {
+	bst := NewEmpty[int, string](comparators.ComparatorInt)
+	for i := 0; i < 127; i++ {
+		bst.Insert(i, "")
+	}
+	testutils.Assert(t, "bst.Height()", 126, bst.Height())
+	bst.Rebalance()
+	testutils.Assert(t, "bst.Height()", 6, bst.Height())
+	testutils.Assert(t, "bst.Size()", 127, bst.Size())
+	testutils.Assert(t, "bst.Rank(100)", 100, bst.Rank(100))
+	if _, err := bst.Remove(63); err != nil {
+		t.Fatal(err)
+	}
+	testutils.Assert(t, "bst.Rank(100)", 99, bst.Rank(100))
+}
+
+func TestSplit(t *testing.T) {
+	bst := NewEmpty[int, string](comparators.ComparatorInt)
+	for _, key := range []int{5, 2, 8, 1, 4, 6, 9, 5} {
+		bst.Insert(key, fmt.Sprint(key))
+	}
+	less, greaterOrEqual := bst.Split(5)
+	testutils.Assert(t, "bst.Size()", 0, bst.Size())
+	testutils.AssertSlices(t, []int{1, 2, 4}, less.InOrderTraversal())
+	testutils.AssertSlices(t, []int{5, 5, 6, 8, 9}, greaterOrEqual.InOrderTraversal())
+	testutils.Assert(t, "greaterOrEqual.Rank(8)", 3, greaterOrEqual.Rank(8))
+	less.Insert(3, "3")
+	testutils.AssertSlices(t, []int{1, 2, 3, 4}, less.InOrderTraversal())
+
+	t.Run("OutOfRange", func(t *testing.T) {
+		bst := NewEmpty[int, string](comparators.ComparatorInt)
+		bst.Insert(1, "")
+		less, greaterOrEqual := bst.Split(0)
+		testutils.Assert(t, "less.Size()", 0, less.Size())
+		testutils.Assert(t, "greaterOrEqual.Size()", 1, greaterOrEqual.Size())
+	})
+}
+
+func TestMerge(t *testing.T) {
+	t.Run("Sequential", func(t *testing.T) {
+		a := NewEmpty[int, string](comparators.ComparatorInt)
+		b := NewEmpty[int, string](comparators.ComparatorInt)
+		for _, key := range []int{1, 3, 5} {
+			a.Insert(key, "a")
+		}
+		for _, key := range []int{2, 3, 6} {
+			b.Insert(key, "b")
+		}
+		a.Merge(b)
+		testutils.Assert(t, "b.Size()", 3, b.Size())
+		testutils.AssertSlices(t, []Entry[int, string]{{1, "a"}, {2, "b"}, {3, "a"}, {3, "b"}, {5, "a"}, {6, "b"}}, a.InOrderEntries())
+		testutils.Assert(t, "a.Count(3)", 2, a.Count(3))
+		// Nodes of b were copied, so modifying a does not affect b.
+		a.RemoveAll(3)
+		testutils.Assert(t, "b.Count(3)", 1, b.Count(3))
+	})
+
+	t.Run("Concurrent", func(t *testing.T) {
+		a := NewEmpty[int, string](comparators.ComparatorInt)
+		b := NewEmpty[int, string](comparators.ComparatorInt)
+		a.Insert(1, "")
+		b.Insert(2, "")
+		testutils.ConcurrentOperations(t, 10, 10, func() error {
+			a.Merge(b)
+			b.Insert(3, "")
+			b.RemoveAll(3)
+			return nil
+		})
+		testutils.Assert(t, "a.Count(1)", 1, a.Count(1))
+		testutils.Assert(t, "a.Count(2)", 100, a.Count(2))
+	})
+}
+
+func TestMergeWith(t *testing.T) {
+	t.Run("Sequential", func(t *testing.T) {
+		a := NewEmpty[string, int](comparators.ComparatorString)
+		b := NewEmpty[string, int](comparators.ComparatorString)
+		for i, key := range []string{"a", "c", "e"} {
+			a.Insert(key, i + 1)
+		}
+		for i, key := range []string{"b", "c", "f"} {
+			b.Insert(key, (i + 1) * 10)
+		}
+		a.MergeWith(b, func(mine, theirs int) int { return mine + theirs })
+		testutils.AssertSlices(t, []Entry[string, int]{{"a", 1}, {"b", 10}, {"c", 22}, {"e", 3}, {"f", 30}}, a.InOrderEntries())
+		testutils.Assert(t, "a.Validate()", nil, a.Validate())
+		a.Update("b", 0)
+		testutils.AssertSlices(t, []int{10, 20, 30}, b.InOrderValues())
+	})
+
+	t.Run("Self", func(t *testing.T) {
+		a := NewEmpty[string, int](comparators.ComparatorString)
+		a.Insert("a", 1)
+		a.MergeWith(a, func(mine, theirs int) int { return mine + theirs })
+		testutils.AssertSlices(t, []Entry[string, int]{{"a", 2}}, a.InOrderEntries())
+	})
+}
+
+func TestIntersectKeys(t *testing.T) {
+	a := NewEmpty[int, string](comparators.ComparatorInt)
+	b := NewEmpty[int, string](comparators.ComparatorInt)
+	for _, key := range []int{1, 3, 3, 3, 5, 7} {
+		a.Insert(key, "a")
+	}
+	for _, key := range []int{0, 3, 3, 7, 8} {
+		b.Insert(key, "b")
+	}
+	testutils.AssertSlices(t, []int{3, 3, 7}, a.IntersectKeys(b))
+	testutils.AssertSlices(t, []int{3, 3, 7}, b.IntersectKeys(a))
+	testutils.AssertSlices(t, []int{}, a.IntersectKeys(NewEmpty[int, string](comparators.ComparatorInt)))
+}
+
+func TestValidate(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		bst := NewEmpty[int, string](comparators.ComparatorInt)
+		testutils.Assert(t, "bst.Validate()", nil, bst.Validate())
+		for _, key := range []int{50, 30, 70, 20, 40, 60, 80, 35, 45, 33, 37, 36, 40} {
+			bst.Insert(key, "")
+		}
+		for _, key := range []int{50, 40, 30} {
+			bst.Remove(key)
+			testutils.Assert(t, "bst.Validate()", nil, bst.Validate())
+		}
+	})
+
+	t.Run("BadComparator", func(t *testing.T) {
+		calls := 0
+		// The comparator flips its answer halfway through, like one with hidden state.
+		flaky := func(a, b int) int {
+			calls++
+			if calls > 3 {
+				return comparators.ComparatorInt(b, a)
+			}
+			return comparators.ComparatorInt(a, b)
+		}
+		bst := NewEmpty[int, string](flaky)
+		for _, key := range []int{2, 1, 3, 4, 0} {
+			bst.Insert(key, "")
+		}
+		if bst.Validate() == nil {
+			t.Fatal("Validated a BST built with an inconsistent comparator.")
+		}
+	})
+
+	t.Run("BadSize", func(t *testing.T) {
+		bst := NewEmpty[int, string](comparators.ComparatorInt)
+		bst.Insert(1, "")
+		bst.Insert(2, "")
+		bst.root.size = 5
+		if bst.Validate() == nil {
+			t.Fatal("Validated a BST with a wrong subtree size.")
+		}
+	})
+}
+
+func TestStats(t *testing.T) {
+	t.Run("Empty", func(t *testing.T) {
+		stats := NewEmpty[int, string](comparators.ComparatorInt).Stats()
+		testutils.Assert(t, "stats.Height", -1, stats.Height)
+		testutils.Assert(t, "stats.Leaves", 0, stats.Leaves)
+	})
+
+	t.Run("NotEmpty", func(t *testing.T) {
+		bst := NewEmpty[int, string](comparators.ComparatorInt)
+		for _, key := range []int{50, 30, 70, 20, 40, 80, 90} {
+			bst.Insert(key, "")
+		}
+		stats := bst.Stats()
+		testutils.Assert(t, "stats.Size", 7, stats.Size)
+		testutils.Assert(t, "stats.Height", 3, stats.Height)
+		testutils.Assert(t, "stats.Leaves", 3, stats.Leaves)
+		testutils.Assert(t, "stats.BalanceFactors[0]", 4, stats.BalanceFactors[0])
+		testutils.Assert(t, "stats.BalanceFactors[-1]", 2, stats.BalanceFactors[-1])
+		testutils.Assert(t, "stats.BalanceFactors[-2]", 1, stats.BalanceFactors[-2])
+	})
+}
+
+func TestString(t *testing.T) {
+	t.Run("Empty", func(t *testing.T) {
+		bst := NewEmpty[int, string](comparators.ComparatorInt)
+		testutils.Assert(t, "bst.String()", "(empty)", bst.String())
+	})
+
+	t.Run("NotEmpty", func(t *testing.T) {
+		bst := NewEmpty[int, string](comparators.ComparatorInt)
+		for _, key := range []int{50, 30, 70, 20, 40, 80} {
+			bst.Insert(key, "")
+		}
+		expected := "50\n" +
+			"├── 30\n" +
+			"│   ├── 20\n" +
+			"│   └── 40\n" +
+			"└── 70\n" +
+			"    ├── (nil)\n" +
+			"    └── 80"
+		testutils.Assert(t, "bst.String()", expected, bst.String())
+	})
+
+	t.Run("Limit", func(t *testing.T) {
+		bst := NewEmpty[int, string](comparators.ComparatorInt)
+		for _, key := range []int{50, 30, 70, 20, 40, 80} {
+			bst.Insert(key, "")
+		}
+		expected := "50\n" +
+			"├── 30\n" +
+			"│   ├── 20\n" +
+			"│   └── 40\n" +
+			"… (+2 more)"
+		testutils.Assert(t, "bst.StringN(4)", expected, bst.StringN(4))
+		testutils.Assert(t, "bst.StringN(0)", "… (+6 more)", bst.StringN(0))
+		testutils.Assert(t, "bst.StringN(10)", bst.StringN(-1), bst.StringN(10))
+		bst.SetStringLimit(4)
+		testutils.Assert(t, "bst.String()", expected, bst.String())
+		testutils.Assert(t, "bst.Copy().String()", expected, bst.Copy().String())
+		bst.SetStringLimit(0)
+		testutils.Assert(t, "bst.String()", bst.StringN(-1), bst.String())
+	})
+}
+
+func TestToDOT(t *testing.T) {
+	bst := NewEmpty[string, int](comparators.ComparatorString)
+	bst.Insert("b", 0)
+	bst.Insert("c", 0)
+	bst.Insert(`"a"`, 0)
+	expected := "digraph BST {\n" +
+		"\tn0 [label=\"b\"];\n" +
+		"\tn1 [label=\"\\\"a\\\"\"];\n" +
+		"\tn2 [label=\"c\"];\n" +
+		"\tn0 -> n1;\n" +
+		"\tn0 -> n2;\n" +
+		"}\n"
+	testutils.Assert(t, "bst.ToDOT()", expected, bst.ToDOT())
+	bst.Remove(`"a"`)
+	if !strings.Contains(bst.ToDOT(), "nil0 [style=invis]") {
+		t.Fatalf("Expected an invisible placeholder, instead got: %s", bst.ToDOT())
+	}
+}
+
+func TestToMap(t *testing.T) {
+	bst := NewEmpty[int, string](comparators.ComparatorInt)
+	testutils.Assert(t, "len(ToMap(bst))", 0, len(ToMap(bst)))
+	bst.Insert(2, "two")
+	bst.Insert(1, "one")
+	bst.Insert(2, "dos")
+	m := ToMap(bst)
+	testutils.Assert(t, "len(m)", 2, len(m))
+	testutils.Assert(t, "m[1]", "one", m[1])
+	testutils.Assert(t, "m[2]", "two", m[2])
+}
+
+func TestKeySet(t *testing.T) {
+	bst := NewEmpty[int, string](comparators.ComparatorInt)
+	for _, key := range []int{3, 1, 3, 2} {
+		bst.Insert(key, "")
+	}
+	s := KeySet(bst)
+	testutils.Assert(t, "s.Size()", 3, s.Size())
+	for _, key := range []int{1, 2, 3} {
+		testutils.Assert(t, "s.Contains(key)", true, s.Contains(key))
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	t.Run("Isolation", func(t *testing.T) {
+		bst := NewEmpty[int, string](comparators.ComparatorInt)
+		for _, key := range []int{50, 30, 70, 20, 40, 60, 80, 35, 45} {
+			bst.Insert(key, fmt.Sprint(key))
+		}
+		snapshot := bst.Snapshot()
+		expected := snapshot.InOrderEntries()
+		bst.Insert(10, "10")
+		bst.Remove(30)
+		bst.Remove(50)
+		bst.Update(80, "eighty")
+		bst.Upsert(45, "forty-five")
+		bst.Rebalance()
+		testutils.AssertSlices(t, expected, snapshot.InOrderEntries())
+		testutils.Assert(t, "snapshot.Validate()", nil, snapshot.Validate())
+		testutils.Assert(t, "bst.Validate()", nil, bst.Validate())
+		testutils.AssertSlices(t, []int{10, 20, 35, 40, 45, 60, 70, 80}, bst.InOrderTraversal())
+		eighty, _ := bst.Search(80)
+		testutils.Assert(t, "eighty", "eighty", eighty)
+
+		// Writes to the snapshot do not leak back either.
+		current := bst.InOrderEntries()
+		snapshot.RemoveAll(40)
+		snapshot.Insert(41, "41")
+		snapshot.Update(70, "seventy")
+		testutils.AssertSlices(t, current, bst.InOrderEntries())
+		testutils.Assert(t, "snapshot.Validate()", nil, snapshot.Validate())
+	})
+
+	t.Run("Concurrent", func(t *testing.T) {
+		bst := NewEmpty[int, string](comparators.ComparatorInt)
+		for i := 0; i < 100; i++ {
+			bst.Insert(i * 7 % 100, "")
+		}
+		testutils.ConcurrentOperations(t, 10, 20, func() error {
+			snapshot := bst.Snapshot()
+			size := snapshot.Size()
+			bst.Insert(size, "")
+			bst.Remove(size)
+			count := 0
+			for range snapshot.All() {
+				count++
+			}
+			if count != size {
+				return fmt.Errorf("Expected %d keys in the snapshot, instead got: %d", size, count)
+			}
+			return snapshot.Validate()
+		})
+		testutils.Assert(t, "bst.Size()", 100, bst.Size())
+		testutils.Assert(t, "bst.Validate()", nil, bst.Validate())
+	})
+}
+
+// unlucky is a comparator for int that panics when comparing 13.
+func unlucky(a, b int) int {
+	if a == 13 || b == 13 {
+		panic("unlucky")
+	}
+	return comparators.ComparatorInt(a, b)
+}
+
+func TestRecovering(t *testing.T) {
+	t.Run("Error", func(t *testing.T) {
+		bst := NewEmpty[int, string](comparators.Recovering(unlucky))
+		bst.Insert(5, "5")
+		bst.Insert(1, "1")
+		err := bst.Insert(13, "13")
+		if !errors.Is(err, comparators.ErrPanicked) {
+			t.Fatalf("Expected ErrPanicked, got %v.", err)
+		}
+		var panicErr *comparators.PanicError[int]
+		if !errors.As(err, &panicErr) {
+			t.Fatalf("Expected a *PanicError[int], got %T.", err)
+		}
+		testutils.Assert(t, "panicErr.A", 13, panicErr.A)
+		testutils.Assert(t, "panicErr.B", 5, panicErr.B)
+		// The failed Insert left the BST unchanged.
+		if err := bst.Validate(); err != nil {
+			t.Fatal(err)
+		}
+		testutils.Assert(t, "bst.Size()", 2, bst.Size())
+		testutils.Assert(t, "bst.Rank(100)", 2, bst.Rank(100))
+		testutils.Assert(t, "panicErr.Value", any("unlucky"), panicErr.Value)
+		if _, err := bst.Search(13); !errors.Is(err, comparators.ErrPanicked) {
+			t.Fatalf("Expected ErrPanicked, got %v.", err)
+		}
+		// The lock was released, so the BST is still usable.
+		if err := bst.Insert(2, "2"); err != nil {
+			t.Fatal(err)
+		}
+		if err := bst.Validate(); err != nil {
+			t.Fatal(err)
+		}
+		testutils.Assert(t, "bst.Rank(100)", 3, bst.Rank(100))
+		testutils.AssertSlices(t, []int{1, 2, 5}, bst.InOrderTraversal())
+	})
+	t.Run("OtherPanics", func(t *testing.T) {
+		bst := NewEmpty[int, string](unlucky)
+		bst.Insert(1, "1")
+		func() {
+			defer func() {
+				testutils.Assert(t, "recover()", any("unlucky"), recover())
+			}()
+			bst.Insert(13, "13")
+		}()
+		testutils.Assert(t, "bst.Size()", 1, bst.Size())
+	})
+	t.Run("Ascend", func(t *testing.T) {
+		bst := NewEmpty[int, string](unlucky)
+		bst.Insert(1, "1")
+		func() {
+			defer func() {
+				recover()
+			}()
+			for range bst.Ascend(13) {
+			}
+		}()
+		// The walk must not leave the BST locked.
+		testutils.Assert(t, "bst.Size()", 1, bst.Size())
+	})
+}
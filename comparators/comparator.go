@@ -1,5 +1,13 @@
 package comparators
 
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+)
+
 type Comparator[T any] func(a, b T) int
 
 // ComparatorString is a comparator function for the string type.
@@ -154,3 +162,153 @@ func ComparatorFloat64(a, b float64) int {
 	}
 	return 0
 }
+
+// ComparatorOrdered is a comparator function for any type satisfying cmp.Ordered.
+// It is the default comparator used by constructors such as list.Collect and bst.Collect.
+// Like cmp.Compare, it treats NaN as less than any other float and equal to itself.
+func ComparatorOrdered[T cmp.Ordered](a, b T) int {
+	return cmp.Compare(a, b)
+}
+
+// ErrIncomparable is returned (wrapped) by ordered structures when a Validator
+// rejects a key or priority that the comparator cannot totally order, and by Auto
+// for types it has no comparator for.
+var ErrIncomparable = errors.New("Value cannot be totally ordered by the comparator.")
+
+// Validator is a function that returns an error if a value cannot be totally
+// ordered by a comparator (e.g. NaN for floats). Ordered structures call it
+// before inserting, so that such values are rejected instead of silently
+// corrupting the structure.
+type Validator[T any] func(T) error
+
+// ValidatorFloat32 is a validator function for the float32 type.
+// It rejects NaN, which compares neither less than, greater than, nor equal to anything.
+func ValidatorFloat32(a float32) error {
+	if a != a {
+		return fmt.Errorf("%w (NaN)", ErrIncomparable)
+	}
+	return nil
+}
+
+// ValidatorFloat64 is a validator function for the float64 type.
+// It rejects NaN, which compares neither less than, greater than, nor equal to anything.
+func ValidatorFloat64(a float64) error {
+	if math.IsNaN(a) {
+		return fmt.Errorf("%w (NaN)", ErrIncomparable)
+	}
+	return nil
+}
+
+// Auto returns a comparator for T picked at runtime, for code paths where the type is
+// not known when writing the code (e.g. configuration-driven ones). It supports:
+//   - types with a method Compare(T) int, such as time.Time,
+//   - types whose underlying type is a bool, string, integer or float type,
+//     which are compared like the matching ready-made comparator.
+//
+// An error wrapping ErrIncomparable is returned for any other type. Prefer passing a comparator directly
+// where the type is known, since the reflection-based fallback is slower.
+func Auto[T any]() (Comparator[T], error) {
+	t := reflect.TypeFor[T]()
+	if t.Implements(reflect.TypeFor[interface{ Compare(T) int }]()) {
+		return func(a, b T) int {
+			return any(a).(interface{ Compare(T) int }).Compare(b)
+		}, nil
+	}
+	// The common built-in types get their ready-made comparator without reflection.
+	var zeroValue T
+	switch any(zeroValue).(type) {
+	case string:
+		return any(ComparatorString).(func(T, T) int), nil
+	case int:
+		return any(ComparatorInt).(func(T, T) int), nil
+	case int64:
+		return any(ComparatorInt64).(func(T, T) int), nil
+	case float64:
+		return any(ComparatorFloat64).(func(T, T) int), nil
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return func(a, b T) int {
+			return ComparatorBool(reflect.ValueOf(a).Bool(), reflect.ValueOf(b).Bool())
+		}, nil
+	case reflect.String:
+		return func(a, b T) int {
+			return ComparatorString(reflect.ValueOf(a).String(), reflect.ValueOf(b).String())
+		}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return func(a, b T) int {
+			return ComparatorInt64(reflect.ValueOf(a).Int(), reflect.ValueOf(b).Int())
+		}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return func(a, b T) int {
+			return ComparatorUint64(reflect.ValueOf(a).Uint(), reflect.ValueOf(b).Uint())
+		}, nil
+	case reflect.Float32, reflect.Float64:
+		return func(a, b T) int {
+			return ComparatorFloat64(reflect.ValueOf(a).Float(), reflect.ValueOf(b).Float())
+		}, nil
+	}
+	return nil, fmt.Errorf("Cannot pick a comparator for type %v: %w", t, ErrIncomparable)
+}
+
+// ErrPanicked is returned (wrapped in a *PanicError) by operations of ordered structures
+// whose comparator, wrapped with Recovering, panicked.
+var ErrPanicked = errors.New("Comparator panicked.")
+
+// PanicError is the error describing a panic of a comparator wrapped with Recovering.
+// It has fields for the pair being compared and the value the comparator panicked with.
+type PanicError[T any] struct {
+	A T
+	B T
+	Value any
+}
+
+// Error returns the description of the panic, including the offending pair.
+func (e *PanicError[T]) Error() string {
+	return fmt.Sprintf("Comparator panicked comparing '%v' and '%v': %v", e.A, e.B, e.Value)
+}
+
+// Unwrap returns ErrPanicked, so that errors.Is(err, ErrPanicked) holds.
+func (e *PanicError[T]) Unwrap() error {
+	return ErrPanicked
+}
+
+// Recovering returns a comparator that calls comparator and, if it panics, panics again with
+// a *PanicError recording the offending pair. Operations of ordered structures that return
+// an error recover such panics with Recover, after releasing their lock, and return the
+// *PanicError instead of crashing the program, e.g. for comparators of untrusted or fuzzed
+// input. Other panics are left alone. The operation that panicked may have modified the
+// structure part-way, so it should be checked (e.g. with BST.Validate) or discarded.
+func Recovering[T any](comparator Comparator[T]) Comparator[T] {
+	return func(a, b T) int {
+		defer func() {
+			if r := recover(); r != nil {
+				panic(&PanicError[T]{A: a, B: b, Value: r})
+			}
+		}()
+		return comparator(a, b)
+	}
+}
+
+// Recover turns a panic of a comparator wrapped with Recovering into an error stored in *err.
+// It must be deferred directly, before the lock is taken, by operations that return an error:
+//
+//	func (bst *BST[K, V]) Insert(key K, value V) (err error) {
+//		defer comparators.Recover(&err)
+//		bst.mu.Lock()
+//		defer bst.mu.Unlock()
+//		...
+//	}
+//
+// Any other panic is propagated unchanged.
+func Recover(err *error) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	if e, ok := r.(error); ok && errors.Is(e, ErrPanicked) {
+		*err = e
+		return
+	}
+	panic(r)
+}
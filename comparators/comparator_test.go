@@ -0,0 +1,67 @@
+package comparators
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/davidpogosian/ds/testutils"
+)
+
+type celsius float32
+type name string
+type level int8
+type port uint16
+type flag bool
+
+// assertAuto checks that the comparator Auto picks for T orders low < high and a value equal to itself.
+func assertAuto[T any](t *testing.T, low, high T) {
+	t.Helper()
+	comparator, err := Auto[T]()
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutils.Assert(t, "comparator(low, high)", -1, comparator(low, high))
+	testutils.Assert(t, "comparator(high, low)", 1, comparator(high, low))
+	testutils.Assert(t, "comparator(low, low)", 0, comparator(low, low))
+}
+
+func TestAuto(t *testing.T) {
+	t.Run("Compare", func(t *testing.T) {
+		now := time.Now()
+		assertAuto(t, now, now.Add(time.Second))
+	})
+	t.Run("Builtin", func(t *testing.T) {
+		assertAuto(t, "a", "b")
+		assertAuto(t, -1, 1)
+		assertAuto[int64](t, -1, 1)
+		assertAuto(t, 0.5, 1.5)
+	})
+	t.Run("Named", func(t *testing.T) {
+		assertAuto[celsius](t, -0.5, 20)
+		assertAuto[name](t, "alice", "bob")
+		assertAuto[level](t, -3, 3)
+		assertAuto[port](t, 80, 443)
+		assertAuto[uintptr](t, 1, 2)
+	})
+	t.Run("Bool", func(t *testing.T) {
+		assertAuto(t, false, true)
+		assertAuto[flag](t, false, true)
+	})
+	t.Run("Unsupported", func(t *testing.T) {
+		tests := []struct {
+			name string
+			auto func() error
+		}{
+			{"struct", func() error { _, err := Auto[struct{ x int }](); return err }},
+			{"slice", func() error { _, err := Auto[[]int](); return err }},
+			{"map", func() error { _, err := Auto[map[string]int](); return err }},
+			{"pointer", func() error { _, err := Auto[*int](); return err }},
+		}
+		for _, test := range tests {
+			if err := test.auto(); !errors.Is(err, ErrIncomparable) {
+				t.Fatalf("Expected ErrIncomparable for a %s, instead got: %v", test.name, err)
+			}
+		}
+	})
+}
@@ -0,0 +1,245 @@
+package crdt
+
+import (
+	"sync"
+	"time"
+)
+
+// Timestamp struct represents a hybrid logical timestamp.
+// It combines the physical wall time with a logical counter, so that
+// causally related events are ordered even when the wall clocks of
+// replicas drift. Ties are broken by the replica id.
+type Timestamp struct {
+	WallTime int64 `json:"wallTime"`
+	Logical uint32 `json:"logical"`
+	Replica string `json:"replica"`
+}
+
+// Compare compares two Timestamps.
+// It returns -1 if t happened before other, 1 if after, and 0 if they are equal.
+func (t Timestamp) Compare(other Timestamp) int {
+	if t.WallTime != other.WallTime {
+		if t.WallTime < other.WallTime {
+			return -1
+		}
+		return 1
+	}
+	if t.Logical != other.Logical {
+		if t.Logical < other.Logical {
+			return -1
+		}
+		return 1
+	}
+	if t.Replica < other.Replica {
+		return -1
+	} else if t.Replica > other.Replica {
+		return 1
+	}
+	return 0
+}
+
+// Clock struct represents a hybrid logical clock owned by a single replica.
+// It has the replica id, the last issued timestamp, a source of wall time,
+// and a mutex for thread-safety.
+type Clock struct {
+	replica string
+	last Timestamp
+	now func() time.Time
+	mu sync.Mutex
+}
+
+// NewClock returns a pointer to a new Clock for the given replica.
+// The now function is used as the source of wall time; if it is nil, time.Now is used.
+func NewClock(replica string, now func() time.Time) *Clock {
+	if now == nil {
+		now = time.Now
+	}
+	return &Clock{replica: replica, now: now}
+}
+
+// Now returns a new Timestamp that is greater than every Timestamp
+// previously issued or observed by the Clock.
+func (c *Clock) Now() Timestamp {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	wall := c.now().UnixNano()
+	if wall > c.last.WallTime {
+		c.last = Timestamp{WallTime: wall, Replica: c.replica}
+	} else {
+		c.last = Timestamp{WallTime: c.last.WallTime, Logical: c.last.Logical + 1, Replica: c.replica}
+	}
+	return c.last
+}
+
+// Observe advances the Clock past a Timestamp received from another replica.
+func (c *Clock) Observe(remote Timestamp) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if remote.WallTime > c.last.WallTime ||
+		(remote.WallTime == c.last.WallTime && remote.Logical > c.last.Logical) {
+		c.last = Timestamp{WallTime: remote.WallTime, Logical: remote.Logical, Replica: c.replica}
+	}
+}
+
+// LWWRegisterState struct represents the state of an LWWRegister that can be
+// serialized and applied on another replica.
+type LWWRegisterState[T any] struct {
+	Value T `json:"value"`
+	Timestamp Timestamp `json:"timestamp"`
+}
+
+// LWWRegister struct represents a last-writer-wins register.
+// It holds a single value and the Timestamp of the write that produced it.
+// It has a Clock for timestamping writes and a mutex for thread-safety.
+type LWWRegister[T any] struct {
+	state LWWRegisterState[T]
+	clock *Clock
+	mu sync.Mutex
+}
+
+// NewLWWRegister returns a pointer to a new LWWRegister holding the zero value.
+// NewLWWRegister requires the Clock of the local replica.
+func NewLWWRegister[T any](clock *Clock) *LWWRegister[T] {
+	return &LWWRegister[T]{clock: clock}
+}
+
+// Set stores a new value in the LWWRegister and returns the resulting state.
+func (r *LWWRegister[T]) Set(value T) LWWRegisterState[T] {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.state = LWWRegisterState[T]{Value: value, Timestamp: r.clock.Now()}
+	return r.state
+}
+
+// Get returns the current value of the LWWRegister and the Timestamp of its write.
+func (r *LWWRegister[T]) Get() (T, Timestamp) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.state.Value, r.state.Timestamp
+}
+
+// State returns the full state of the LWWRegister.
+func (r *LWWRegister[T]) State() LWWRegisterState[T] {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.state
+}
+
+// ApplyState applies a state produced by another replica.
+// The write with the greater Timestamp wins.
+func (r *LWWRegister[T]) ApplyState(state LWWRegisterState[T]) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.clock.Observe(state.Timestamp)
+	if state.Timestamp.Compare(r.state.Timestamp) > 0 {
+		r.state = state
+	}
+}
+
+// Merge merges the state of another replica into this LWWRegister.
+func (r *LWWRegister[T]) Merge(other *LWWRegister[T]) {
+	r.ApplyState(other.State())
+}
+
+// LWWMapEntry struct represents a single key of an LWWMap.
+// Deleted entries are kept as tombstones so that deletes propagate.
+type LWWMapEntry[V any] struct {
+	Value V `json:"value"`
+	Timestamp Timestamp `json:"timestamp"`
+	Deleted bool `json:"deleted"`
+}
+
+// LWWMap struct represents a last-writer-wins map.
+// Every key behaves like an independent LWWRegister, and deletes are tombstones.
+// It has a Clock for timestamping writes and a mutex for thread-safety.
+type LWWMap[K comparable, V any] struct {
+	entries map[K]LWWMapEntry[V]
+	clock *Clock
+	mu sync.Mutex
+}
+
+// NewLWWMap returns a pointer to a new empty LWWMap.
+// NewLWWMap requires the Clock of the local replica.
+func NewLWWMap[K comparable, V any](clock *Clock) *LWWMap[K, V] {
+	return &LWWMap[K, V]{entries: make(map[K]LWWMapEntry[V]), clock: clock}
+}
+
+// Put stores a value under the given key and returns the corresponding delta.
+func (m *LWWMap[K, V]) Put(key K, value V) map[K]LWWMapEntry[V] {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	entry := LWWMapEntry[V]{Value: value, Timestamp: m.clock.Now()}
+	m.entries[key] = entry
+	return map[K]LWWMapEntry[V]{key: entry}
+}
+
+// Delete deletes the given key and returns the corresponding delta.
+// Deleting a key that is not in the LWWMap still records a tombstone.
+func (m *LWWMap[K, V]) Delete(key K) map[K]LWWMapEntry[V] {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	entry := LWWMapEntry[V]{Timestamp: m.clock.Now(), Deleted: true}
+	m.entries[key] = entry
+	return map[K]LWWMapEntry[V]{key: entry}
+}
+
+// Get returns the value stored under the given key and a bool indicating
+// whether or not the key is in the LWWMap.
+func (m *LWWMap[K, V]) Get(key K) (V, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	entry, exists := m.entries[key]
+	if !exists || entry.Deleted {
+		var zeroValue V
+		return zeroValue, false
+	}
+	return entry.Value, true
+}
+
+// Keys returns the keys of the LWWMap that are not deleted.
+func (m *LWWMap[K, V]) Keys() []K {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var keys []K
+	for key, entry := range m.entries {
+		if !entry.Deleted {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// Size returns the number of keys in the LWWMap that are not deleted.
+func (m *LWWMap[K, V]) Size() int {
+	return len(m.Keys())
+}
+
+// State returns the full state of the LWWMap, including tombstones, as a delta.
+func (m *LWWMap[K, V]) State() map[K]LWWMapEntry[V] {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	state := make(map[K]LWWMapEntry[V], len(m.entries))
+	for key, entry := range m.entries {
+		state[key] = entry
+	}
+	return state
+}
+
+// ApplyDelta applies a delta produced by another replica.
+// For every key, the write with the greater Timestamp wins.
+func (m *LWWMap[K, V]) ApplyDelta(delta map[K]LWWMapEntry[V]) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for key, entry := range delta {
+		m.clock.Observe(entry.Timestamp)
+		current, exists := m.entries[key]
+		if !exists || entry.Timestamp.Compare(current.Timestamp) > 0 {
+			m.entries[key] = entry
+		}
+	}
+}
+
+// Merge merges the state of another replica into this LWWMap.
+func (m *LWWMap[K, V]) Merge(other *LWWMap[K, V]) {
+	m.ApplyDelta(other.State())
+}
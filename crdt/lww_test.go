@@ -0,0 +1,133 @@
+package crdt
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/davidpogosian/ds/testutils"
+)
+
+// frozenTime returns a wall time source that never advances.
+func frozenTime() time.Time {
+	return time.Unix(100, 0)
+}
+
+func TestClock(t *testing.T) {
+	t.Run("Monotonic", func(t *testing.T) {
+		c := NewClock("a", frozenTime)
+		t1 := c.Now()
+		t2 := c.Now()
+		testutils.Assert(t, "t1.Compare(t2)", -1, t1.Compare(t2))
+	})
+
+	t.Run("Observe", func(t *testing.T) {
+		c := NewClock("a", frozenTime)
+		remote := Timestamp{WallTime: time.Unix(200, 0).UnixNano(), Logical: 5, Replica: "b"}
+		c.Observe(remote)
+		testutils.Assert(t, "remote.Compare(c.Now())", -1, remote.Compare(c.Now()))
+	})
+}
+
+func TestLWWRegister(t *testing.T) {
+	t.Run("Merge", func(t *testing.T) {
+		a := NewLWWRegister[string](NewClock("a", frozenTime))
+		b := NewLWWRegister[string](NewClock("b", frozenTime))
+		a.Set("first")
+		b.Merge(a)
+		b.Set("second")
+		a.Merge(b)
+		aValue, _ := a.Get()
+		bValue, _ := b.Get()
+		testutils.Assert(t, "aValue", "second", aValue)
+		testutils.Assert(t, "bValue", "second", bValue)
+	})
+
+	t.Run("ConcurrentWrites", func(t *testing.T) {
+		a := NewLWWRegister[int](NewClock("a", frozenTime))
+		b := NewLWWRegister[int](NewClock("b", frozenTime))
+		a.Set(1)
+		b.Set(2)
+		a.Merge(b)
+		b.Merge(a)
+		aValue, _ := a.Get()
+		bValue, _ := b.Get()
+		testutils.Assert(t, "aValue", bValue, aValue)
+	})
+
+	t.Run("State", func(t *testing.T) {
+		a := NewLWWRegister[int](NewClock("a", nil))
+		b := NewLWWRegister[int](NewClock("b", nil))
+		encoded, err := json.Marshal(a.Set(7))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var state LWWRegisterState[int]
+		if err := json.Unmarshal(encoded, &state); err != nil {
+			t.Fatal(err)
+		}
+		b.ApplyState(state)
+		value, _ := b.Get()
+		testutils.Assert(t, "value", 7, value)
+	})
+}
+
+func TestLWWMap(t *testing.T) {
+	t.Run("PutGetDelete", func(t *testing.T) {
+		m := NewLWWMap[string, int](NewClock("a", nil))
+		m.Put("x", 1)
+		value, ok := m.Get("x")
+		testutils.Assert(t, "ok", true, ok)
+		testutils.Assert(t, "value", 1, value)
+		m.Delete("x")
+		_, ok = m.Get("x")
+		testutils.Assert(t, "ok", false, ok)
+		testutils.Assert(t, "m.Size()", 0, m.Size())
+	})
+
+	t.Run("Merge", func(t *testing.T) {
+		a := NewLWWMap[string, int](NewClock("a", frozenTime))
+		b := NewLWWMap[string, int](NewClock("b", frozenTime))
+		a.Put("x", 1)
+		a.Put("y", 2)
+		b.Merge(a)
+		b.Delete("x")
+		b.Put("y", 3)
+		a.Merge(b)
+		_, ok := a.Get("x")
+		testutils.Assert(t, "ok", false, ok)
+		y, _ := a.Get("y")
+		testutils.Assert(t, "y", 3, y)
+		testutils.Assert(t, "a.Size()", 1, a.Size())
+	})
+
+	t.Run("Delta", func(t *testing.T) {
+		a := NewLWWMap[string, int](NewClock("a", nil))
+		b := NewLWWMap[string, int](NewClock("b", nil))
+		encoded, err := json.Marshal(a.Put("x", 1))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var delta map[string]LWWMapEntry[int]
+		if err := json.Unmarshal(encoded, &delta); err != nil {
+			t.Fatal(err)
+		}
+		b.ApplyDelta(delta)
+		x, ok := b.Get("x")
+		testutils.Assert(t, "ok", true, ok)
+		testutils.Assert(t, "x", 1, x)
+	})
+
+	t.Run("Concurrent", func(t *testing.T) {
+		a := NewLWWMap[int, int](NewClock("a", nil))
+		b := NewLWWMap[int, int](NewClock("b", nil))
+		testutils.ConcurrentOperations(t, 10, 100, func() error {
+			a.Put(1, 1)
+			b.Put(2, 2)
+			a.Merge(b)
+			b.Merge(a)
+			return nil
+		})
+		testutils.Assert(t, "a.Size()", 2, a.Size())
+	})
+}
@@ -0,0 +1,324 @@
+// Package crdt provides thread-safe, generic conflict-free replicated data types.
+// Replicas of the same type converge to the same state once they have merged
+// each other's state (or deltas), regardless of the order of merges.
+package crdt
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/davidpogosian/ds/set"
+)
+
+// GSetDelta struct represents a change to a GSet that can be serialized
+// and applied on another replica.
+type GSetDelta[T comparable] struct {
+	Added []T `json:"added"`
+}
+
+// GSet struct represents a grow-only set.
+// Items can be added but never removed. It is backed by a Set.
+type GSet[T comparable] struct {
+	items *set.Set[T]
+}
+
+// NewGSet returns a pointer to a new empty GSet.
+func NewGSet[T comparable]() *GSet[T] {
+	return &GSet[T]{items: set.NewEmpty[T]()}
+}
+
+// Add adds an item to the GSet and returns the corresponding delta.
+func (g *GSet[T]) Add(item T) GSetDelta[T] {
+	g.items.Add(item)
+	return GSetDelta[T]{Added: []T{item}}
+}
+
+// Contains returns a bool indicating whether or not the item is in the GSet.
+func (g *GSet[T]) Contains(item T) bool {
+	return g.items.Contains(item)
+}
+
+// Size returns the number of items in the GSet.
+func (g *GSet[T]) Size() int {
+	return g.items.Size()
+}
+
+// ToSlice returns the items of the GSet as a slice.
+func (g *GSet[T]) ToSlice() []T {
+	return g.items.ToSlice()
+}
+
+// State returns the full state of the GSet as a delta.
+func (g *GSet[T]) State() GSetDelta[T] {
+	return GSetDelta[T]{Added: g.items.ToSlice()}
+}
+
+// ApplyDelta applies a delta produced by another replica.
+func (g *GSet[T]) ApplyDelta(delta GSetDelta[T]) {
+	for _, item := range delta.Added {
+		g.items.Add(item)
+	}
+}
+
+// Merge merges the state of another replica into this GSet.
+func (g *GSet[T]) Merge(other *GSet[T]) {
+	g.ApplyDelta(other.State())
+}
+
+// TwoPSetDelta struct represents a change to a TwoPSet that can be serialized
+// and applied on another replica.
+type TwoPSetDelta[T comparable] struct {
+	Added []T `json:"added"`
+	Removed []T `json:"removed"`
+}
+
+// TwoPSet struct represents a two-phase set.
+// It consists of a set of added items and a set of removed items (tombstones).
+// Once an item has been removed it can never be added back.
+// It has a mutex for thread-safety, so that checking and tombstoning an item is atomic.
+type TwoPSet[T comparable] struct {
+	added *set.Set[T]
+	removed *set.Set[T]
+	mu sync.Mutex
+}
+
+// NewTwoPSet returns a pointer to a new empty TwoPSet.
+func NewTwoPSet[T comparable]() *TwoPSet[T] {
+	return &TwoPSet[T]{
+		added: set.NewEmpty[T](),
+		removed: set.NewEmpty[T](),
+	}
+}
+
+// contains returns a bool indicating whether or not the item is added and not removed.
+func (tp *TwoPSet[T]) contains(item T) bool {
+	return tp.added.Contains(item) && !tp.removed.Contains(item)
+}
+
+// Add adds an item to the TwoPSet and returns the corresponding delta.
+// Adding an item that has already been removed has no visible effect.
+func (tp *TwoPSet[T]) Add(item T) TwoPSetDelta[T] {
+	tp.mu.Lock()
+	defer tp.mu.Unlock()
+	tp.added.Add(item)
+	return TwoPSetDelta[T]{Added: []T{item}}
+}
+
+// Remove removes an item from the TwoPSet and returns the corresponding delta.
+// If the item is not in the TwoPSet, an error is returned.
+func (tp *TwoPSet[T]) Remove(item T) (TwoPSetDelta[T], error) {
+	tp.mu.Lock()
+	defer tp.mu.Unlock()
+	if !tp.contains(item) {
+		return TwoPSetDelta[T]{}, fmt.Errorf("Item '%v' is not in the TwoPSet.", item)
+	}
+	tp.removed.Add(item)
+	return TwoPSetDelta[T]{Removed: []T{item}}, nil
+}
+
+// Contains returns a bool indicating whether or not the item is in the TwoPSet.
+func (tp *TwoPSet[T]) Contains(item T) bool {
+	tp.mu.Lock()
+	defer tp.mu.Unlock()
+	return tp.contains(item)
+}
+
+// ToSlice returns the items of the TwoPSet as a slice.
+func (tp *TwoPSet[T]) ToSlice() []T {
+	tp.mu.Lock()
+	defer tp.mu.Unlock()
+	return tp.added.Difference(tp.removed).ToSlice()
+}
+
+// Size returns the number of items in the TwoPSet.
+func (tp *TwoPSet[T]) Size() int {
+	return len(tp.ToSlice())
+}
+
+// State returns the full state of the TwoPSet as a delta.
+func (tp *TwoPSet[T]) State() TwoPSetDelta[T] {
+	tp.mu.Lock()
+	defer tp.mu.Unlock()
+	return TwoPSetDelta[T]{
+		Added: tp.added.ToSlice(),
+		Removed: tp.removed.ToSlice(),
+	}
+}
+
+// ApplyDelta applies a delta produced by another replica.
+func (tp *TwoPSet[T]) ApplyDelta(delta TwoPSetDelta[T]) {
+	tp.mu.Lock()
+	defer tp.mu.Unlock()
+	for _, item := range delta.Added {
+		tp.added.Add(item)
+	}
+	for _, item := range delta.Removed {
+		tp.added.Add(item)
+		tp.removed.Add(item)
+	}
+}
+
+// Merge merges the state of another replica into this TwoPSet.
+// The state of other is read before this TwoPSet is locked, so the two
+// TwoPSets are never locked at the same time.
+func (tp *TwoPSet[T]) Merge(other *TwoPSet[T]) {
+	tp.ApplyDelta(other.State())
+}
+
+// Tag struct uniquely identifies a single add operation in an ORSet.
+// It consists of the id of the replica that performed the add and
+// a counter local to that replica.
+type Tag struct {
+	Replica string `json:"replica"`
+	Counter uint64 `json:"counter"`
+}
+
+// ORSetAdd struct represents a single tagged add in an ORSetDelta.
+type ORSetAdd[T comparable] struct {
+	Item T `json:"item"`
+	Tag Tag `json:"tag"`
+}
+
+// ORSetDelta struct represents a change to an ORSet that can be serialized
+// and applied on another replica.
+type ORSetDelta[T comparable] struct {
+	Adds []ORSetAdd[T] `json:"adds"`
+	Removes []Tag `json:"removes"`
+}
+
+// ORSet struct represents an observed-remove set.
+// Every add is tagged uniquely, and a remove only tombstones the tags that
+// the removing replica has observed, so a concurrent add wins over a remove.
+// It has the replica id, a counter for generating tags, the tags of every item,
+// a Set of tombstoned tags, and a mutex for thread-safety.
+type ORSet[T comparable] struct {
+	replica string
+	counter uint64
+	tags map[T]*set.Set[Tag]
+	tombstones *set.Set[Tag]
+	mu sync.Mutex
+}
+
+// NewORSet returns a pointer to a new empty ORSet.
+// NewORSet requires an id that is unique among all replicas.
+func NewORSet[T comparable](replica string) *ORSet[T] {
+	return &ORSet[T]{
+		replica: replica,
+		tags: make(map[T]*set.Set[Tag]),
+		tombstones: set.NewEmpty[Tag](),
+	}
+}
+
+// addTag records a tagged add of the item.
+func (or *ORSet[T]) addTag(item T, tag Tag) {
+	tags, exists := or.tags[item]
+	if !exists {
+		tags = set.NewEmpty[Tag]()
+		or.tags[item] = tags
+	}
+	tags.Add(tag)
+}
+
+// contains returns a bool indicating whether or not the item has a live tag.
+func (or *ORSet[T]) contains(item T) bool {
+	tags, exists := or.tags[item]
+	if !exists {
+		return false
+	}
+	for _, tag := range tags.ToSlice() {
+		if !or.tombstones.Contains(tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// Add adds an item to the ORSet and returns the corresponding delta.
+func (or *ORSet[T]) Add(item T) ORSetDelta[T] {
+	or.mu.Lock()
+	defer or.mu.Unlock()
+	or.counter++
+	tag := Tag{Replica: or.replica, Counter: or.counter}
+	or.addTag(item, tag)
+	return ORSetDelta[T]{Adds: []ORSetAdd[T]{{Item: item, Tag: tag}}}
+}
+
+// Remove removes an item from the ORSet and returns the corresponding delta.
+// Only the adds observed by this replica are removed.
+// If the item is not in the ORSet, an error is returned.
+func (or *ORSet[T]) Remove(item T) (ORSetDelta[T], error) {
+	or.mu.Lock()
+	defer or.mu.Unlock()
+	if !or.contains(item) {
+		return ORSetDelta[T]{}, fmt.Errorf("Item '%v' is not in the ORSet.", item)
+	}
+	var removes []Tag
+	for _, tag := range or.tags[item].ToSlice() {
+		if !or.tombstones.Contains(tag) {
+			or.tombstones.Add(tag)
+			removes = append(removes, tag)
+		}
+	}
+	return ORSetDelta[T]{Removes: removes}, nil
+}
+
+// Contains returns a bool indicating whether or not the item is in the ORSet.
+func (or *ORSet[T]) Contains(item T) bool {
+	or.mu.Lock()
+	defer or.mu.Unlock()
+	return or.contains(item)
+}
+
+// ToSlice returns the items of the ORSet as a slice.
+func (or *ORSet[T]) ToSlice() []T {
+	or.mu.Lock()
+	defer or.mu.Unlock()
+	var slice []T
+	for item := range or.tags {
+		if or.contains(item) {
+			slice = append(slice, item)
+		}
+	}
+	return slice
+}
+
+// Size returns the number of items in the ORSet.
+func (or *ORSet[T]) Size() int {
+	return len(or.ToSlice())
+}
+
+// State returns the full state of the ORSet as a delta.
+func (or *ORSet[T]) State() ORSetDelta[T] {
+	or.mu.Lock()
+	defer or.mu.Unlock()
+	var delta ORSetDelta[T]
+	for item, tags := range or.tags {
+		for _, tag := range tags.ToSlice() {
+			delta.Adds = append(delta.Adds, ORSetAdd[T]{Item: item, Tag: tag})
+		}
+	}
+	delta.Removes = or.tombstones.ToSlice()
+	return delta
+}
+
+// ApplyDelta applies a delta produced by another replica.
+func (or *ORSet[T]) ApplyDelta(delta ORSetDelta[T]) {
+	or.mu.Lock()
+	defer or.mu.Unlock()
+	for _, add := range delta.Adds {
+		or.addTag(add.Item, add.Tag)
+		if add.Tag.Replica == or.replica && add.Tag.Counter > or.counter {
+			or.counter = add.Tag.Counter
+		}
+	}
+	for _, tag := range delta.Removes {
+		or.tombstones.Add(tag)
+	}
+}
+
+// Merge merges the state of another replica into this ORSet.
+// The state of other is read before this ORSet is locked, so the two
+// ORSets are never locked at the same time.
+func (or *ORSet[T]) Merge(other *ORSet[T]) {
+	or.ApplyDelta(other.State())
+}
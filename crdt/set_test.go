@@ -0,0 +1,174 @@
+package crdt
+
+import (
+	"encoding/json"
+	"sync/atomic"
+	"testing"
+
+	"github.com/davidpogosian/ds/testutils"
+)
+
+func TestGSet(t *testing.T) {
+	t.Run("Merge", func(t *testing.T) {
+		a := NewGSet[int]()
+		b := NewGSet[int]()
+		a.Add(1)
+		b.Add(2)
+		a.Merge(b)
+		b.Merge(a)
+		testutils.Assert(t, "a.Size()", 2, a.Size())
+		testutils.Assert(t, "b.Size()", 2, b.Size())
+		testutils.Assert(t, "b.Contains(1)", true, b.Contains(1))
+	})
+
+	t.Run("Delta", func(t *testing.T) {
+		a := NewGSet[string]()
+		b := NewGSet[string]()
+		encoded, err := json.Marshal(a.Add("x"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var delta GSetDelta[string]
+		if err := json.Unmarshal(encoded, &delta); err != nil {
+			t.Fatal(err)
+		}
+		b.ApplyDelta(delta)
+		testutils.Assert(t, "b.Contains(\"x\")", true, b.Contains("x"))
+	})
+
+	t.Run("Concurrent", func(t *testing.T) {
+		a := NewGSet[int]()
+		b := NewGSet[int]()
+		b.Add(1)
+		testutils.ConcurrentOperations(t, 10, 100, func() error {
+			a.Merge(b)
+			b.Merge(a)
+			return nil
+		})
+		testutils.Assert(t, "a.Size()", 1, a.Size())
+	})
+}
+
+func TestTwoPSet(t *testing.T) {
+	t.Run("RemoveWins", func(t *testing.T) {
+		a := NewTwoPSet[int]()
+		b := NewTwoPSet[int]()
+		a.Add(1)
+		b.Merge(a)
+		if _, err := b.Remove(1); err != nil {
+			t.Fatal(err)
+		}
+		a.Add(1)
+		a.Merge(b)
+		testutils.Assert(t, "a.Contains(1)", false, a.Contains(1))
+		testutils.Assert(t, "a.Size()", 0, a.Size())
+	})
+
+	t.Run("RemoveMissing", func(t *testing.T) {
+		a := NewTwoPSet[int]()
+		_, err := a.Remove(1)
+		if err == nil {
+			t.Fatal("Removed an item that is not in the TwoPSet.")
+		}
+	})
+
+	t.Run("Delta", func(t *testing.T) {
+		a := NewTwoPSet[int]()
+		b := NewTwoPSet[int]()
+		b.ApplyDelta(a.Add(1))
+		b.ApplyDelta(a.Add(2))
+		delta, err := a.Remove(1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b.ApplyDelta(delta)
+		testutils.AssertSlices(t, []int{2}, b.ToSlice())
+	})
+
+	t.Run("Concurrent", func(t *testing.T) {
+		a := NewTwoPSet[int]()
+		b := NewTwoPSet[int]()
+		a.Add(1)
+		var removals atomic.Int32
+		testutils.ConcurrentOperations(t, 10, 100, func() error {
+			if _, err := a.Remove(1); err == nil {
+				removals.Add(1)
+			}
+			a.Add(2)
+			b.Merge(a)
+			a.Merge(b)
+			return nil
+		})
+		// Checking and tombstoning the item is atomic, so it is removed exactly once.
+		testutils.Assert(t, "removals.Load()", int32(1), removals.Load())
+		testutils.AssertSlices(t, []int{2}, a.ToSlice())
+		testutils.AssertSlices(t, []int{2}, b.ToSlice())
+	})
+}
+
+func TestORSet(t *testing.T) {
+	t.Run("AddWins", func(t *testing.T) {
+		a := NewORSet[int]("a")
+		b := NewORSet[int]("b")
+		a.Add(1)
+		b.Merge(a)
+		if _, err := b.Remove(1); err != nil {
+			t.Fatal(err)
+		}
+		// Concurrent add on a that b has not observed.
+		a.Add(1)
+		a.Merge(b)
+		b.Merge(a)
+		testutils.Assert(t, "a.Contains(1)", true, a.Contains(1))
+		testutils.Assert(t, "b.Contains(1)", true, b.Contains(1))
+	})
+
+	t.Run("ReAdd", func(t *testing.T) {
+		a := NewORSet[string]("a")
+		a.Add("x")
+		if _, err := a.Remove("x"); err != nil {
+			t.Fatal(err)
+		}
+		testutils.Assert(t, "a.Contains(\"x\")", false, a.Contains("x"))
+		a.Add("x")
+		testutils.Assert(t, "a.Contains(\"x\")", true, a.Contains("x"))
+		testutils.Assert(t, "a.Size()", 1, a.Size())
+	})
+
+	t.Run("Delta", func(t *testing.T) {
+		a := NewORSet[string]("a")
+		b := NewORSet[string]("b")
+		encoded, err := json.Marshal(a.Add("x"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var delta ORSetDelta[string]
+		if err := json.Unmarshal(encoded, &delta); err != nil {
+			t.Fatal(err)
+		}
+		b.ApplyDelta(delta)
+		testutils.Assert(t, "b.Contains(\"x\")", true, b.Contains("x"))
+		removed, err := b.Remove("x")
+		if err != nil {
+			t.Fatal(err)
+		}
+		a.ApplyDelta(removed)
+		testutils.Assert(t, "a.Contains(\"x\")", false, a.Contains("x"))
+	})
+
+	t.Run("Concurrent", func(t *testing.T) {
+		a := NewORSet[int]("a")
+		b := NewORSet[int]("b")
+		testutils.ConcurrentOperations(t, 10, 100, func() error {
+			a.Add(1)
+			b.Add(2)
+			a.Merge(b)
+			b.Merge(a)
+			return nil
+		})
+		a.Merge(b)
+		b.Merge(a)
+		testutils.Assert(t, "a.Size()", 2, a.Size())
+		testutils.Assert(t, "b.Size()", 2, b.Size())
+	})
+}
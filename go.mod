@@ -1,3 +1,3 @@
 module github.com/davidpogosian/ds
 
-go 1.22.5
+go 1.23
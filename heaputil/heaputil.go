@@ -0,0 +1,151 @@
+// Package heaputil provides generic binary heap operations over user-owned slices.
+//
+// It is the comparator-based counterpart of container/heap: the slice itself is the
+// heap, and the comparator decides the order, with the item that compares lowest at
+// index 0 (pass a reversed comparator for a max heap). The functions do not lock
+// anything; guarding the slice is up to the caller.
+package heaputil
+
+import (
+	"fmt"
+
+	"github.com/davidpogosian/ds/comparators"
+)
+
+// Init establishes the heap property over the whole slice in O(n).
+func Init[T any](h []T, comparator comparators.Comparator[T]) {
+	for i := len(h) / 2 - 1; i >= 0; i-- {
+		down(h, i, comparator)
+	}
+}
+
+// Push adds an item to the heap in O(log n) and returns the updated slice,
+// which may have been reallocated, like the result of append.
+func Push[T any](h []T, item T, comparator comparators.Comparator[T]) []T {
+	h = append(h, item)
+	up(h, len(h) - 1, comparator)
+	return h
+}
+
+// Pop removes the lowest item from the heap in O(log n) and returns it along with
+// the updated slice. An error is returned if the heap is empty.
+func Pop[T any](h []T, comparator comparators.Comparator[T]) (T, []T, error) {
+	if len(h) == 0 {
+		var zeroValue T
+		return zeroValue, h, fmt.Errorf("Cannot pop from an empty heap.")
+	}
+	return Remove(h, 0, comparator)
+}
+
+// Remove removes the item at index i from the heap in O(log n) and returns it along
+// with the updated slice. An error is returned if i is out of range.
+func Remove[T any](h []T, i int, comparator comparators.Comparator[T]) (T, []T, error) {
+	var zeroValue T
+	if i < 0 || i >= len(h) {
+		return zeroValue, h, fmt.Errorf("Cannot remove index %d from a heap of size %d.", i, len(h))
+	}
+	item := h[i]
+	last := len(h) - 1
+	if i < last {
+		// The last item fills the hole at i. Its place is found before anything moves.
+		place(h[:last], i, h[last], comparator)
+	}
+	// Clear the vacated slot so it does not keep the item alive.
+	h[last] = zeroValue
+	return item, h[:last], nil
+}
+
+// Fix restores the heap property in O(log n) after the item at index i has changed.
+func Fix[T any](h []T, i int, comparator comparators.Comparator[T]) {
+	place(h, i, h[i], comparator)
+}
+
+// Replace replaces the item at index i with item and restores the heap property in O(log n).
+// Unlike changing h[i] and calling Fix, it leaves the heap untouched if the comparator panics.
+func Replace[T any](h []T, i int, item T, comparator comparators.Comparator[T]) {
+	place(h, i, item, comparator)
+}
+
+// Every operation below compares first and moves items only once the destination
+// of the item is known, so a comparator that panics (see comparators.Recovering)
+// leaves the heap exactly as it was.
+
+// place puts item, which replaces the item at index i, where it belongs in the heap,
+// and returns a bool indicating whether it moved away from i.
+func place[T any](h []T, i int, item T, comparator comparators.Comparator[T]) bool {
+	if target := downTarget(h, i, item, comparator); target != i {
+		moveDown(h, i, target, item)
+		return true
+	}
+	target := upTarget(h, i, item, comparator)
+	moveUp(h, i, target, item)
+	return target != i
+}
+
+// up moves the item at index i towards the root until its parent is not greater.
+func up[T any](h []T, i int, comparator comparators.Comparator[T]) {
+	moveUp(h, i, upTarget(h, i, h[i], comparator), h[i])
+}
+
+// down moves the item at index i towards the leaves until neither child is lower,
+// and returns a bool indicating whether it moved.
+func down[T any](h []T, i int, comparator comparators.Comparator[T]) bool {
+	target := downTarget(h, i, h[i], comparator)
+	moveDown(h, i, target, h[i])
+	return target != i
+}
+
+// upTarget returns the index that item, placed at index i, would end up at when moved
+// towards the root. It only compares item with the ancestors of i.
+func upTarget[T any](h []T, i int, item T, comparator comparators.Comparator[T]) int {
+	for i > 0 {
+		parent := (i - 1) / 2
+		if comparator(item, h[parent]) >= 0 {
+			break
+		}
+		i = parent
+	}
+	return i
+}
+
+// downTarget returns the index that item, placed at index i, would end up at when moved
+// towards the leaves. It never reads h[i] itself.
+func downTarget[T any](h []T, i int, item T, comparator comparators.Comparator[T]) int {
+	for {
+		left := 2 * i + 1
+		if left >= len(h) {
+			return i
+		}
+		lowest := left
+		if right := left + 1; right < len(h) && comparator(h[right], h[left]) < 0 {
+			lowest = right
+		}
+		if comparator(h[lowest], item) >= 0 {
+			return i
+		}
+		i = lowest
+	}
+}
+
+// moveUp shifts the items on the path from target down to i one level down,
+// and puts item at target, which must be i or one of its ancestors.
+func moveUp[T any](h []T, i int, target int, item T) {
+	for i != target {
+		parent := (i - 1) / 2
+		h[i] = h[parent]
+		i = parent
+	}
+	h[target] = item
+}
+
+// moveDown shifts the items on the path from i down to target one level up,
+// and puts item at target, which must be i or one of its descendants.
+// It walks the path back up from target, carrying each displaced item to the parent,
+// so that it does not allocate.
+func moveDown[T any](h []T, i int, target int, item T) {
+	carried := item
+	for j := target; j != i; j = (j - 1) / 2 {
+		h[j], carried = carried, h[j]
+	}
+	h[i] = carried
+}
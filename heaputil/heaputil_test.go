@@ -0,0 +1,139 @@
+package heaputil
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/davidpogosian/ds/comparators"
+	"github.com/davidpogosian/ds/testutils"
+)
+
+// drain pops every item off the heap and returns them in popping order.
+func drain[T any](t *testing.T, h []T, comparator comparators.Comparator[T]) []T {
+	var popped []T
+	for len(h) > 0 {
+		item, rest, err := Pop(h, comparator)
+		if err != nil {
+			t.Fatal(err)
+		}
+		popped = append(popped, item)
+		h = rest
+	}
+	return popped
+}
+
+func TestInit(t *testing.T) {
+	h := rand.Perm(100)
+	Init(h, comparators.ComparatorInt)
+	expected := make([]int, 100)
+	for i := range expected {
+		expected[i] = i
+	}
+	testutils.AssertSlices(t, expected, drain(t, h, comparators.ComparatorInt))
+}
+
+func TestPush(t *testing.T) {
+	var h []int
+	for _, item := range []int{5, 3, 8, 1, 9, 2} {
+		h = Push(h, item, comparators.ComparatorInt)
+		testutils.Assert(t, "h[0]", slices.Min(h), h[0])
+	}
+	testutils.AssertSlices(t, []int{1, 2, 3, 5, 8, 9}, drain(t, h, comparators.ComparatorInt))
+}
+
+func TestPop(t *testing.T) {
+	t.Run("Empty", func(t *testing.T) {
+		_, _, err := Pop([]int{}, comparators.ComparatorInt)
+		if err == nil {
+			t.Fatal("Popped from an empty heap.")
+		}
+	})
+
+	t.Run("MaxHeap", func(t *testing.T) {
+		reversed := func(a, b int) int { return comparators.ComparatorInt(b, a) }
+		h := []int{4, 1, 3, 2}
+		Init(h, reversed)
+		testutils.AssertSlices(t, []int{4, 3, 2, 1}, drain(t, h, reversed))
+	})
+}
+
+func TestRemove(t *testing.T) {
+	h := []int{7, 3, 9, 1, 5, 8, 2}
+	Init(h, comparators.ComparatorInt)
+	i := slices.Index(h, 5)
+	item, h, err := Remove(h, i, comparators.ComparatorInt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutils.Assert(t, "item", 5, item)
+	testutils.AssertSlices(t, []int{1, 2, 3, 7, 8, 9}, drain(t, h, comparators.ComparatorInt))
+	if _, _, err := Remove([]int{}, 0, comparators.ComparatorInt); err == nil {
+		t.Fatal("Removed from an empty heap.")
+	}
+}
+
+func TestFix(t *testing.T) {
+	h := []int{1, 2, 3, 4, 5, 6, 7}
+	Init(h, comparators.ComparatorInt)
+	h[0] = 10
+	Fix(h, 0, comparators.ComparatorInt)
+	h[len(h) - 1] = 0
+	Fix(h, len(h) - 1, comparators.ComparatorInt)
+	testutils.Assert(t, "h[0]", 0, h[0])
+	sorted := drain(t, h, comparators.ComparatorInt)
+	testutils.Assert(t, "slices.IsSorted(sorted)", true, slices.IsSorted(sorted))
+	testutils.Assert(t, "len(sorted)", 7, len(sorted))
+}
+
+func TestReplace(t *testing.T) {
+	h := []int{1, 2, 3, 4, 5, 6, 7}
+	Init(h, comparators.ComparatorInt)
+	Replace(h, 0, 10, comparators.ComparatorInt)
+	Replace(h, slices.Index(h, 6), 0, comparators.ComparatorInt)
+	testutils.AssertSlices(t, []int{0, 2, 3, 4, 5, 7, 10}, drain(t, h, comparators.ComparatorInt))
+}
+
+func TestPanickingComparator(t *testing.T) {
+	unlucky := func(a, b int) int {
+		if a == 13 || b == 13 {
+			panic("unlucky")
+		}
+		return comparators.ComparatorInt(a, b)
+	}
+	// mustPanic runs f, which must panic, and checks that h was left untouched.
+	mustPanic := func(name string, h []int, f func()) {
+		t.Helper()
+		before := slices.Clone(h)
+		defer func() {
+			t.Helper()
+			if recover() == nil {
+				t.Fatalf("%s did not panic.", name)
+			}
+			testutils.AssertSlices(t, before, h)
+		}()
+		f()
+	}
+	h := make([]int, 0, 8)
+	for _, item := range []int{1, 20, 30, 40, 50, 60} {
+		h = Push(h, item, unlucky)
+	}
+	mustPanic("Push", h, func() { Push(h, 13, unlucky) })
+	mustPanic("Replace", h, func() { Replace(h, 5, 13, unlucky) })
+	h = append(h, 13)
+	mustPanic("Pop", h, func() { Pop(h, unlucky) })
+}
+
+func TestNoAllocations(t *testing.T) {
+	h := rand.Perm(1000)
+	Init(h, comparators.ComparatorInt)
+	allocs := testing.AllocsPerRun(100, func() {
+		// Pop leaves room at the end of the slice, so Push does not grow it.
+		item, rest, _ := Pop(h, comparators.ComparatorInt)
+		h = Push(rest, item, comparators.ComparatorInt)
+		h[0] = 2000
+		Fix(h, 0, comparators.ComparatorInt)
+		Init(h, comparators.ComparatorInt)
+	})
+	testutils.Assert(t, "allocs", 0.0, allocs)
+}
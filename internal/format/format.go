@@ -0,0 +1,34 @@
+// Package format provides string formatting helpers shared by the containers.
+package format
+
+import "fmt"
+
+// Truncated returns the string representation of items in the same form as
+// fmt.Sprintf("%v", items), followed by a "… (+N more)" marker when
+// remaining items were left out.
+func Truncated[T any](items []T, remaining int) string {
+	s := fmt.Sprintf("%v", items)
+	if remaining <= 0 {
+		return s
+	}
+	marker := More(remaining)
+	if len(items) > 0 {
+		marker = " " + marker
+	}
+	return s[:len(s) - 1] + marker + "]"
+}
+
+// More returns the "… (+N more)" marker for remaining items that were left out,
+// for containers whose string representation is not a plain list (e.g. a tree view).
+func More(remaining int) string {
+	return fmt.Sprintf("… (+%d more)", remaining)
+}
+
+// Limit returns how many of size items should be printed under the given maximum.
+// A negative maximum means no limit.
+func Limit(size int, max int) int {
+	if max < 0 || max > size {
+		return size
+	}
+	return max
+}
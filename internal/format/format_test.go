@@ -0,0 +1,24 @@
+package format
+
+import (
+	"testing"
+
+	"github.com/davidpogosian/ds/testutils"
+)
+
+func TestTruncated(t *testing.T) {
+	testutils.Assert(t, "Truncated([]int{}, 0)", "[]", Truncated([]int{}, 0))
+	testutils.Assert(t, "Truncated([]int{1, 2}, 0)", "[1 2]", Truncated([]int{1, 2}, 0))
+	testutils.Assert(t, "Truncated([]int{1, 2}, 3)", "[1 2 … (+3 more)]", Truncated([]int{1, 2}, 3))
+	testutils.Assert(t, "Truncated([]int{}, 3)", "[… (+3 more)]", Truncated([]int{}, 3))
+}
+
+func TestLimit(t *testing.T) {
+	testutils.Assert(t, "Limit(5, -1)", 5, Limit(5, -1))
+	testutils.Assert(t, "Limit(5, 10)", 5, Limit(5, 10))
+	testutils.Assert(t, "Limit(5, 2)", 2, Limit(5, 2))
+}
+
+func TestMore(t *testing.T) {
+	testutils.Assert(t, "More(3)", "… (+3 more)", More(3))
+}
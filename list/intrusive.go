@@ -0,0 +1,301 @@
+package list
+
+import (
+	"fmt"
+	"iter"
+	"sync"
+)
+
+// Hook links a struct into an Intrusive list. Embed it in the struct to be linked:
+//
+//	type Timer struct {
+//		list.Hook[Timer]
+//		deadline int64
+//	}
+//
+// The zero value is an unlinked Hook. A struct can be in at most one Intrusive list
+// per embedded Hook at a time.
+type Hook[E any] struct {
+	next *E
+	prev *E
+	list any
+}
+
+// hook returns the Hook itself. Being promoted to the embedding struct,
+// it lets the Intrusive list reach the Hook of an element.
+func (h *Hook[E]) hook() *Hook[E] {
+	return h
+}
+
+// hooked is satisfied by pointers to structs that embed a Hook.
+type hooked[E any] interface {
+	*E
+	hook() *Hook[E]
+}
+
+// Intrusive struct represents a double-linked list of structs that embed a Hook.
+// Unlike List, it does not allocate a node per element: the links live in the
+// elements themselves, so an element can be removed in O(1) given only a pointer
+// to it, which suits timers and LRU entries.
+// Intrusive has pointers to the front and the back of the list, a field to keep
+// track of its size, and a mutex for thread-safety. The mutex guards the links
+// only, not the other fields of the elements.
+type Intrusive[E any, P hooked[E]] struct {
+	front *E
+	back *E
+	size int
+	mu sync.Mutex
+}
+
+// NewIntrusive returns a pointer to a new empty Intrusive list of elements of type E,
+// which must embed a Hook[E], e.g. list.NewIntrusive[Timer]().
+func NewIntrusive[E any, P hooked[E]]() *Intrusive[E, P] {
+	return &Intrusive[E, P]{}
+}
+
+// hookOf returns the Hook of an element.
+func (l *Intrusive[E, P]) hookOf(e *E) *Hook[E] {
+	return P(e).hook()
+}
+
+// linkAfter links e after mark, or at the front if mark is nil.
+func (l *Intrusive[E, P]) linkAfter(mark *E, e *E) {
+	h := l.hookOf(e)
+	h.list = l
+	h.prev = mark
+	if mark == nil {
+		h.next = l.front
+		l.front = e
+	} else {
+		markHook := l.hookOf(mark)
+		h.next = markHook.next
+		markHook.next = e
+	}
+	if h.next == nil {
+		l.back = e
+	} else {
+		l.hookOf(h.next).prev = e
+	}
+	l.size++
+}
+
+// unlink removes e, which must be in the list, and resets its Hook.
+func (l *Intrusive[E, P]) unlink(e *E) {
+	h := l.hookOf(e)
+	if h.prev == nil {
+		l.front = h.next
+	} else {
+		l.hookOf(h.prev).next = h.next
+	}
+	if h.next == nil {
+		l.back = h.prev
+	} else {
+		l.hookOf(h.next).prev = h.prev
+	}
+	*h = Hook[E]{}
+	l.size--
+}
+
+// contains returns a bool indicating whether e is in this list.
+func (l *Intrusive[E, P]) contains(e *E) bool {
+	return l.hookOf(e).list == any(l)
+}
+
+// checkFree returns an error if e is already in a list.
+func (l *Intrusive[E, P]) checkFree(e *E) error {
+	if l.hookOf(e).list != nil {
+		return fmt.Errorf("Cannot insert an element that is already in a list.")
+	}
+	return nil
+}
+
+// checkMember returns an error if e is not in this list.
+func (l *Intrusive[E, P]) checkMember(e *E) error {
+	if !l.contains(e) {
+		return fmt.Errorf("Cannot use an element that is not in this list.")
+	}
+	return nil
+}
+
+// PushFront inserts e at the front of the list.
+// An error is returned if e is already in a list.
+func (l *Intrusive[E, P]) PushFront(e *E) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if err := l.checkFree(e); err != nil {
+		return err
+	}
+	l.linkAfter(nil, e)
+	return nil
+}
+
+// PushBack inserts e at the back of the list.
+// An error is returned if e is already in a list.
+func (l *Intrusive[E, P]) PushBack(e *E) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if err := l.checkFree(e); err != nil {
+		return err
+	}
+	l.linkAfter(l.back, e)
+	return nil
+}
+
+// InsertBefore inserts e right before mark.
+// An error is returned if e is already in a list or mark is not in this one.
+func (l *Intrusive[E, P]) InsertBefore(e *E, mark *E) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if err := l.checkFree(e); err != nil {
+		return err
+	}
+	if err := l.checkMember(mark); err != nil {
+		return err
+	}
+	l.linkAfter(l.hookOf(mark).prev, e)
+	return nil
+}
+
+// InsertAfter inserts e right after mark.
+// An error is returned if e is already in a list or mark is not in this one.
+func (l *Intrusive[E, P]) InsertAfter(e *E, mark *E) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if err := l.checkFree(e); err != nil {
+		return err
+	}
+	if err := l.checkMember(mark); err != nil {
+		return err
+	}
+	l.linkAfter(mark, e)
+	return nil
+}
+
+// Remove removes e from the list in O(1), after which it may be inserted again.
+// An error is returned if e is not in this list.
+func (l *Intrusive[E, P]) Remove(e *E) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if err := l.checkMember(e); err != nil {
+		return err
+	}
+	l.unlink(e)
+	return nil
+}
+
+// MoveToFront moves e to the front of the list in O(1), e.g. to mark an LRU entry as used.
+// An error is returned if e is not in this list.
+func (l *Intrusive[E, P]) MoveToFront(e *E) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if err := l.checkMember(e); err != nil {
+		return err
+	}
+	l.unlink(e)
+	l.linkAfter(nil, e)
+	return nil
+}
+
+// MoveToBack moves e to the back of the list in O(1).
+// An error is returned if e is not in this list.
+func (l *Intrusive[E, P]) MoveToBack(e *E) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if err := l.checkMember(e); err != nil {
+		return err
+	}
+	l.unlink(e)
+	l.linkAfter(l.back, e)
+	return nil
+}
+
+// Contains returns a bool indicating whether e is in this list, in O(1).
+func (l *Intrusive[E, P]) Contains(e *E) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.contains(e)
+}
+
+// Front returns the element at the front of the list, or nil if it is empty.
+func (l *Intrusive[E, P]) Front() *E {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.front
+}
+
+// Back returns the element at the back of the list, or nil if it is empty.
+func (l *Intrusive[E, P]) Back() *E {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.back
+}
+
+// Next returns the element after e, or nil if e is the back of the list or not in it.
+func (l *Intrusive[E, P]) Next(e *E) *E {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if !l.contains(e) {
+		return nil
+	}
+	return l.hookOf(e).next
+}
+
+// Prev returns the element before e, or nil if e is the front of the list or not in it.
+func (l *Intrusive[E, P]) Prev(e *E) *E {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if !l.contains(e) {
+		return nil
+	}
+	return l.hookOf(e).prev
+}
+
+// Size returns the number of elements in the list.
+func (l *Intrusive[E, P]) Size() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.size
+}
+
+// IsEmpty returns a bool indicating the emptiness of the list.
+func (l *Intrusive[E, P]) IsEmpty() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.size == 0
+}
+
+// Clear removes all elements from the list, so they may be inserted again.
+func (l *Intrusive[E, P]) Clear() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for l.front != nil {
+		l.unlink(l.front)
+	}
+}
+
+// All returns an iterator over the elements of the list from front to back.
+// Like List.All, the lock is only held while stepping from one element to the next,
+// so the loop body may modify the list, including removing the current element.
+func (l *Intrusive[E, P]) All() iter.Seq[*E] {
+	return func(yield func(*E) bool) {
+		l.mu.Lock()
+		cursor := l.front
+		for cursor != nil {
+			next := l.hookOf(cursor).next
+			l.mu.Unlock()
+			if !yield(cursor) {
+				return
+			}
+			l.mu.Lock()
+			// Continue from the current element if it is still linked, so that
+			// elements inserted right after it are seen.
+			if l.contains(cursor) {
+				next = l.hookOf(cursor).next
+			} else if next != nil && !l.contains(next) {
+				break
+			}
+			cursor = next
+		}
+		l.mu.Unlock()
+	}
+}
@@ -0,0 +1,136 @@
+package list
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/davidpogosian/ds/testutils"
+)
+
+type timer struct {
+	Hook[timer]
+	deadline int
+}
+
+// deadlines returns the deadlines of the timers in the list from front to back.
+func deadlines(l *Intrusive[timer, *timer]) []int {
+	result := []int{}
+	for e := range l.All() {
+		result = append(result, e.deadline)
+	}
+	return result
+}
+
+func TestNewIntrusive(t *testing.T) {
+	l := NewIntrusive[timer]()
+	testutils.Assert(t, "l.Size()", 0, l.Size())
+	testutils.Assert(t, "l.IsEmpty()", true, l.IsEmpty())
+	if l.Front() != nil || l.Back() != nil {
+		t.Fatal("Expected an empty list to have no front or back.")
+	}
+}
+
+func TestIntrusiveInsert(t *testing.T) {
+	l := NewIntrusive[timer]()
+	a, b, c, d := &timer{deadline: 1}, &timer{deadline: 2}, &timer{deadline: 3}, &timer{deadline: 4}
+	l.PushBack(b)
+	l.PushFront(a)
+	l.PushBack(d)
+	if err := l.InsertBefore(c, d); err != nil {
+		t.Fatal(err)
+	}
+	testutils.AssertSlices(t, []int{1, 2, 3, 4}, deadlines(l))
+	if err := l.PushBack(a); err == nil {
+		t.Fatal("Inserted an element that is already in a list.")
+	}
+	other := NewIntrusive[timer]()
+	if err := other.PushBack(a); err == nil {
+		t.Fatal("Inserted an element that is already in another list.")
+	}
+	e := &timer{deadline: 5}
+	if err := other.InsertAfter(e, a); err == nil {
+		t.Fatal("Inserted after an element of another list.")
+	}
+	if err := l.InsertAfter(e, d); err != nil {
+		t.Fatal(err)
+	}
+	testutils.Assert(t, "l.Back()", e, l.Back())
+	testutils.Assert(t, "l.Next(a)", b, l.Next(a))
+	testutils.Assert(t, "l.Prev(a) == nil", true, l.Prev(a) == nil)
+}
+
+func TestIntrusiveRemove(t *testing.T) {
+	l := NewIntrusive[timer]()
+	timers := []*timer{{deadline: 1}, {deadline: 2}, {deadline: 3}}
+	for _, e := range timers {
+		l.PushBack(e)
+	}
+	if err := l.Remove(timers[1]); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Remove(timers[1]); err == nil {
+		t.Fatal("Removed an element that is not in the list.")
+	}
+	testutils.AssertSlices(t, []int{1, 3}, deadlines(l))
+	testutils.Assert(t, "l.Contains(timers[1])", false, l.Contains(timers[1]))
+	// A removed element can be inserted again, into any list.
+	other := NewIntrusive[timer]()
+	if err := other.PushBack(timers[1]); err != nil {
+		t.Fatal(err)
+	}
+	l.Clear()
+	testutils.Assert(t, "l.Size()", 0, l.Size())
+	if err := other.PushBack(timers[0]); err != nil {
+		t.Fatal(err)
+	}
+	testutils.AssertSlices(t, []int{2, 1}, deadlines(other))
+}
+
+func TestIntrusiveMove(t *testing.T) {
+	l := NewIntrusive[timer]()
+	timers := []*timer{{deadline: 1}, {deadline: 2}, {deadline: 3}}
+	for _, e := range timers {
+		l.PushBack(e)
+	}
+	l.MoveToFront(timers[2])
+	testutils.AssertSlices(t, []int{3, 1, 2}, deadlines(l))
+	l.MoveToBack(timers[2])
+	testutils.AssertSlices(t, []int{1, 2, 3}, deadlines(l))
+	if err := l.MoveToFront(&timer{}); err == nil {
+		t.Fatal("Moved an element that is not in the list.")
+	}
+}
+
+func TestIntrusiveAll(t *testing.T) {
+	l := NewIntrusive[timer]()
+	for i := 1; i <= 5; i++ {
+		l.PushBack(&timer{deadline: i})
+	}
+	for e := range l.All() {
+		if e.deadline % 2 == 0 {
+			l.Remove(e)
+		}
+	}
+	testutils.AssertSlices(t, []int{1, 3, 5}, deadlines(l))
+	for e := range l.All() {
+		if e.deadline == 3 {
+			break
+		}
+	}
+	testutils.Assert(t, "l.Size()", 3, l.Size())
+}
+
+func TestIntrusiveConcurrent(t *testing.T) {
+	l := NewIntrusive[timer]()
+	testutils.ConcurrentOperations(t, 10, 100, func() error {
+		e := &timer{}
+		if err := l.PushBack(e); err != nil {
+			return err
+		}
+		if err := l.MoveToFront(e); err != nil {
+			return fmt.Errorf("MoveToFront failed: %w", err)
+		}
+		return l.Remove(e)
+	})
+	testutils.Assert(t, "l.Size()", 0, l.Size())
+}
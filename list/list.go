@@ -2,30 +2,81 @@
 package list
 
 import (
+	"cmp"
 	"fmt"
+	"iter"
 	"sync"
+	"unsafe"
 
 	"github.com/davidpogosian/ds/comparators"
+	"github.com/davidpogosian/ds/internal/format"
 )
 
-// node struct represents a single item in the List.
-// It has a field for a value, and pointers to the previous and the next node.
-type node[T any] struct {
+// Element struct represents a single item in the List.
+// It has a field for a value, pointers to the previous and the next element,
+// and a pointer to the List it belongs to (nil once it has been removed).
+// Elements returned by the List serve as handles for O(1) insertion and removal.
+// An Element must not be used concurrently with its own removal from the List.
+type Element[T any] struct {
 	val T
-	next *node[T]
-	prev *node[T]
+	next *Element[T]
+	prev *Element[T]
+	list *List[T]
+}
+
+// Value returns the value stored in the Element.
+func (e *Element[T]) Value() T {
+	l := e.list
+	if l == nil {
+		return e.val
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return e.val
+}
+
+// Next returns the next Element in the List.
+// It returns nil if e is the back of the List or has been removed from it.
+func (e *Element[T]) Next() *Element[T] {
+	l := e.list
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if e.list != l {
+		return nil
+	}
+	return e.next
+}
+
+// Prev returns the previous Element in the List.
+// It returns nil if e is the front of the List or has been removed from it.
+func (e *Element[T]) Prev() *Element[T] {
+	l := e.list
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if e.list != l {
+		return nil
+	}
+	return e.prev
 }
 
 // List struct represents a double-linked list.
 // It has pointers to the front and the back of the list.
 // A field to keep track of the size of the list.
 // A comparator function to compare elements.
+// A limit on the number of items printed by String (0 means no limit).
 // And a mutex for thread-safety.
 type List[T any] struct {
-	front *node[T]
-	back *node[T]
+	front *Element[T]
+	back *Element[T]
 	size int
 	comparator comparators.Comparator[T]
+	stringLimit int
 	mu sync.Mutex
 }
 
@@ -51,9 +102,19 @@ func NewFromSlice[T any](slice []T, comparator comparators.Comparator[T]) *List[
 	return &l
 }
 
+// Collect returns a pointer to a new List holding the items of seq in order.
+// The List uses comparators.ComparatorOrdered to compare items.
+func Collect[T cmp.Ordered](seq iter.Seq[T]) *List[T] {
+	l := NewEmpty[T](comparators.ComparatorOrdered[T])
+	for item := range seq {
+		l.insertBack(item)
+	}
+	return l
+}
+
 // insertFront inserts new item at the front of the List.
-func (l *List[T]) insertFront(newItem T) {
-	n := &node[T]{val: newItem}
+func (l *List[T]) insertFront(newItem T) *Element[T] {
+	n := &Element[T]{val: newItem, list: l}
 	if l.size == 0 {
 		l.front = n
 		l.back = n
@@ -63,18 +124,20 @@ func (l *List[T]) insertFront(newItem T) {
 		l.front = n
 	}
 	l.size++
+	return n
 }
 
-// InsertFront inserts new item at the front of the List.
-func (l *List[T]) InsertFront(newItem T) {
+// InsertFront inserts new item at the front of the List
+// and returns the Element holding it.
+func (l *List[T]) InsertFront(newItem T) *Element[T] {
 	l.mu.Lock()
 	defer l.mu.Unlock()
-	l.insertFront(newItem)
+	return l.insertFront(newItem)
 }
 
 // insertBack inserts new item at the back of the list.
-func (l *List[T]) insertBack(newItem T) {
-	n := &node[T]{val: newItem}
+func (l *List[T]) insertBack(newItem T) *Element[T] {
+	n := &Element[T]{val: newItem, list: l}
 	if l.size == 0 {
 		l.front = n
 		l.back = n
@@ -84,57 +147,137 @@ func (l *List[T]) insertBack(newItem T) {
 		l.back = n
 	}
 	l.size++
+	return n
 }
 
-// InsertBack inserts new item at the back of the List.
-func (l *List[T]) InsertBack(newItem T) {
+// InsertBack inserts new item at the back of the List
+// and returns the Element holding it.
+func (l *List[T]) InsertBack(newItem T) *Element[T] {
 	l.mu.Lock()
 	defer l.mu.Unlock()
-	l.insertBack(newItem)
+	return l.insertBack(newItem)
+}
+
+// insertBefore inserts new item right before mark, which must belong to the List.
+func (l *List[T]) insertBefore(mark *Element[T], newItem T) *Element[T] {
+	if mark == l.front {
+		return l.insertFront(newItem)
+	}
+	n := &Element[T]{val: newItem, list: l}
+	n.prev = mark.prev
+	mark.prev.next = n
+	n.next = mark
+	mark.prev = n
+	l.size++
+	return n
+}
+
+// insertAfter inserts new item right after mark, which must belong to the List.
+func (l *List[T]) insertAfter(mark *Element[T], newItem T) *Element[T] {
+	if mark == l.back {
+		return l.insertBack(newItem)
+	}
+	n := &Element[T]{val: newItem, list: l}
+	n.next = mark.next
+	mark.next.prev = n
+	n.prev = mark
+	mark.next = n
+	l.size++
+	return n
+}
+
+// unlink removes the given element, which must belong to the List.
+func (l *List[T]) unlink(e *Element[T]) {
+	if e.prev == nil {
+		l.front = e.next
+	} else {
+		e.prev.next = e.next
+	}
+	if e.next == nil {
+		l.back = e.prev
+	} else {
+		e.next.prev = e.prev
+	}
+	e.list = nil
+	l.size--
+}
+
+// elementAt returns the element at the given valid, nonnegative index.
+// It walks from whichever end of the List is closer.
+func (l *List[T]) elementAt(index int) *Element[T] {
+	if index < l.size / 2 {
+		cursor := l.front
+		for i := 0; i < index; i++ {
+			cursor = cursor.next
+		}
+		return cursor
+	}
+	cursor := l.back
+	for i := l.size - 1; i > index; i-- {
+		cursor = cursor.prev
+	}
+	return cursor
 }
 
 // InsertPosition inserts new item at the specified position.
-// If the position is invalid (aka if position < 0 || position > List.size), an error is returned.
+// A negative position counts from the back, like in Python: -1 inserts before the last item.
+// If the position is invalid (aka if position < -List.size || position > List.size), an error is returned.
 func (l *List[T]) InsertPosition(newItem T, position int) error {
 	l.mu.Lock()
 	defer l.mu.Unlock()
-	if position < 0 || position > l.size {
+	index := position
+	if index < 0 {
+		index += l.size
+	}
+	if index < 0 || index > l.size {
 		return fmt.Errorf("Cannot insert into a List of size %d at index %d.", l.size, position)
 	}
-	if position == 0 {
-		l.insertFront(newItem)
-	} else if position == l.size {
+	if index == l.size {
 		l.insertBack(newItem)
 	} else {
-		cursor := l.front
-		for i := 0; i < position; i++ {
-			cursor = cursor.next
-		}
-		n := &node[T]{val: newItem}
-		n.prev = cursor.prev
-		cursor.prev.next = n
-		n.next = cursor
-	 	cursor.prev = n
-		l.size++
+		l.insertBefore(l.elementAt(index), newItem)
 	}
 	return nil
 }
 
 // String returns the string representation of the List.
+// If a limit was set with SetStringLimit, only that many items are printed.
 func (l *List[T]) String() string {
 	l.mu.Lock()
 	defer l.mu.Unlock()
-	s := "["
+	if l.stringLimit > 0 {
+		return l.stringN(l.stringLimit)
+	}
+	return l.stringN(-1)
+}
+
+// StringN returns the string representation of the first max items of the List,
+// followed by "… (+N more)" if items were left out.
+// If max is negative, all items are printed.
+func (l *List[T]) StringN(max int) string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.stringN(max)
+}
+
+// stringN returns the string representation of the first max items of the List.
+func (l *List[T]) stringN(max int) string {
+	limit := format.Limit(l.size, max)
+	items := make([]T, limit)
 	cursor := l.front
-	for i := 0; i < l.size - 1; i++ {
-		s += fmt.Sprintf("%v", cursor.val) + " "
+	for i := 0; i < limit; i++ {
+		items[i] = cursor.val
 		cursor = cursor.next
 	}
-	if l.size > 0 {
-		s += fmt.Sprintf("%v", cursor.val)
-	}
-	s += "]"
-	return s
+	return format.Truncated(items, l.size - limit)
+}
+
+// SetStringLimit limits String to printing the first max items of the List.
+// A max of 0 or less removes the limit.
+func (l *List[T]) SetStringLimit(max int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.stringLimit = max
 }
 
 // Size returns the number of items in the List.
@@ -155,32 +298,46 @@ func (l *List[T]) IsEmpty() bool {
 func (l *List[T]) Clear() {
 	l.mu.Lock()
 	defer l.mu.Unlock()
+	for cursor := l.front; cursor != nil; cursor = cursor.next {
+		cursor.list = nil
+	}
 	l.front = nil
 	l.back = nil
 	l.size = 0
 }
 
 // Get returns an item from the specified index of the List.
-// If the index is invalid (aka index < 0 || index >= List.size), an error is returned.
+// A negative index counts from the back, like in Python: -1 is the last item.
+// If the index is invalid (aka index < -List.size || index >= List.size), an error is returned.
 func (l *List[T]) Get(index int) (T, error) {
+	item, ok := l.TryGet(index)
+	if !ok {
+		return item, fmt.Errorf("Cannot access index %d in a List of size %d.", index, l.Size())
+	}
+	return item, nil
+}
+
+// TryGet returns an item from the specified index of the List and true,
+// or returns false if the index is invalid. Negative indices count from the back, like in Get.
+func (l *List[T]) TryGet(index int) (T, bool) {
 	l.mu.Lock()
 	defer l.mu.Unlock()
-	if index < 0 || index >= l.size {
-		var zeroValue T
-		return zeroValue, fmt.Errorf("Cannot access index %d in a List of size %d.", index, l.size)
+	i := index
+	if i < 0 {
+		i += l.size
 	}
-	cursor := l.front
-	for i := 0; i < index; i++ {
-		cursor = cursor.next
+	if i < 0 || i >= l.size {
+		var zeroValue T
+		return zeroValue, false
 	}
-	return cursor.val, nil
+	return l.elementAt(i).val, true
 }
 
 // Copy returns a pointer to a copy of the List.
 func (l *List[T]) Copy() *List[T] {
 	l.mu.Lock()
 	defer l.mu.Unlock()
-	newList := &List[T]{comparator: l.comparator}
+	newList := &List[T]{comparator: l.comparator, stringLimit: l.stringLimit}
 	cursor := l.front
 	for i := 0; i < l.size; i++ {
 		newList.insertBack(cursor.val)
@@ -212,6 +369,7 @@ func (l *List[T]) removeFront() (T, error) {
 		return zeroValue, fmt.Errorf("Cannot remove the front item from an empty List.")
 	}
 	value := l.front.val
+	l.front.list = nil
 	if l.size == 1 {
 		l.front = nil
 		l.back = nil
@@ -239,6 +397,7 @@ func (l *List[T]) removeBack() (T, error) {
 		return zeroValue, fmt.Errorf("Cannot remove the back item from an empty List.")
 	}
 	value := l.back.val
+	l.back.list = nil
 	if l.size == 1 {
 		l.front = nil
 		l.back = nil
@@ -259,30 +418,22 @@ func (l *List[T]) RemoveBack() (T, error) {
 }
 
 // RemovePosition removes the item at given index from the List.
-// If the index is invalid (aka index < 0 || index >= List.size), an error is returned.
+// A negative index counts from the back, like in Python: -1 is the last item.
+// If the index is invalid (aka index < -List.size || index >= List.size), an error is returned.
 func (l *List[T]) RemovePosition(index int) (T, error) {
 	l.mu.Lock()
 	defer l.mu.Unlock()
-	if index < 0 || index >= l.size {
+	i := index
+	if i < 0 {
+		i += l.size
+	}
+	if i < 0 || i >= l.size {
 		var zeroValue T
 		return zeroValue, fmt.Errorf("Cannot remove item at index %d in a List of size %d.", index, l.size)
 	}
-	var value T
-	if index == 0 {
-		return l.removeFront()
-	} else if index == l.size - 1 {
-		return l.removeBack()
-	} else {
-		cursor := l.front
-		for i := 0; i < index; i++ {
-			cursor = cursor.next
-		}
-		value = cursor.val
-		cursor.prev.next = cursor.next
-		cursor.next.prev = cursor.prev
-		l.size--
-	}
-	return value, nil
+	cursor := l.elementAt(i)
+	l.unlink(cursor)
+	return cursor.val, nil
 }
 
 // ToSlice returns the List as a slice.
@@ -317,3 +468,692 @@ func (l *List[T]) Reverse() {
 		l.back = tempFront
 	}
 }
+
+// InsertSorted inserts new item in comparator order, after any items
+// that compare equal to it.
+// The List is expected to already be sorted (e.g. via Sort).
+func (l *List[T]) InsertSorted(newItem T) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	cursor := l.front
+	for cursor != nil && l.comparator(cursor.val, newItem) <= 0 {
+		cursor = cursor.next
+	}
+	if cursor == nil {
+		l.insertBack(newItem)
+	} else {
+		l.insertBefore(cursor, newItem)
+	}
+}
+
+// Sort sorts the items of the List in increasing order according to the comparator.
+// Sort is stable and relinks the existing nodes using merge sort in O(n log n).
+func (l *List[T]) Sort() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.sort(l.comparator)
+}
+
+// SortStableFunc sorts the items of the List so that a comes before b whenever less(a, b)
+// is true. Like Sort, it is stable and relinks the existing nodes in O(n log n).
+// The comparator of the List is not used or changed.
+func (l *List[T]) SortStableFunc(less func(a, b T) bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.sort(func(a, b T) int {
+		if less(a, b) {
+			return -1
+		}
+		if less(b, a) {
+			return 1
+		}
+		return 0
+	})
+}
+
+// SortBy sorts the items of l in increasing order of key(item) according to comparator,
+// e.g. to order structs by one of their fields. Like Sort, it is stable and relinks the
+// existing nodes in O(n log n). key is called O(n log n) times, so it should be cheap.
+func SortBy[T, K any](l *List[T], key func(T) K, comparator comparators.Comparator[K]) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.sort(func(a, b T) int {
+		return comparator(key(a), key(b))
+	})
+}
+
+// sort stably sorts the items of the List according to compare.
+func (l *List[T]) sort(compare comparators.Comparator[T]) {
+	if l.size < 2 {
+		return
+	}
+	l.front = l.mergeSort(l.front, l.size, compare)
+	var prev *Element[T]
+	for cursor := l.front; cursor != nil; cursor = cursor.next {
+		cursor.prev = prev
+		prev = cursor
+	}
+	l.back = prev
+}
+
+// mergeSort sorts the first length nodes starting at head, using only the
+// next pointers, and returns the new head. The prev pointers are fixed by the caller.
+func (l *List[T]) mergeSort(head *Element[T], length int, compare comparators.Comparator[T]) *Element[T] {
+	if length == 1 {
+		head.next = nil
+		return head
+	}
+	half := length / 2
+	middle := head
+	for i := 0; i < half; i++ {
+		middle = middle.next
+	}
+	left := l.mergeSort(head, half, compare)
+	right := l.mergeSort(middle, length - half, compare)
+	var dummy Element[T]
+	tail := &dummy
+	for left != nil && right != nil {
+		if compare(right.val, left.val) < 0 {
+			tail.next = right
+			right = right.next
+		} else {
+			tail.next = left
+			left = left.next
+		}
+		tail = tail.next
+	}
+	if left != nil {
+		tail.next = left
+	} else {
+		tail.next = right
+	}
+	return dummy.next
+}
+
+// All returns an iterator over the items of the List from front to back.
+//
+// The iterator is live rather than a snapshot: the lock is only held while
+// stepping from one node to the next, never while the loop body runs.
+// The loop body may therefore call methods on the List (including ones that
+// modify it) without deadlocking. Items inserted or removed concurrently may
+// or may not be observed. For a consistent view, iterate over Copy() instead.
+func (l *List[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		l.mu.Lock()
+		cursor := l.front
+		for cursor != nil {
+			val := cursor.val
+			l.mu.Unlock()
+			if !yield(val) {
+				return
+			}
+			l.mu.Lock()
+			cursor = cursor.next
+		}
+		l.mu.Unlock()
+	}
+}
+
+// Backward returns an iterator over the items of the List from back to front.
+// It has the same live semantics as All.
+func (l *List[T]) Backward() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		l.mu.Lock()
+		cursor := l.back
+		for cursor != nil {
+			val := cursor.val
+			l.mu.Unlock()
+			if !yield(val) {
+				return
+			}
+			l.mu.Lock()
+			cursor = cursor.prev
+		}
+		l.mu.Unlock()
+	}
+}
+
+// Entries returns an iterator over the index and item pairs of the List from front to back.
+// The index counts the items yielded so far. It has the same live semantics as All,
+// so under concurrent modification the index may not match the current position of the item.
+func (l *List[T]) Entries() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		i := 0
+		for val := range l.All() {
+			if !yield(i, val) {
+				return
+			}
+			i++
+		}
+	}
+}
+
+// Front returns the Element at the front of the List.
+// It returns nil if the List is empty.
+func (l *List[T]) Front() *Element[T] {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.front
+}
+
+// Back returns the Element at the back of the List.
+// It returns nil if the List is empty.
+func (l *List[T]) Back() *Element[T] {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.back
+}
+
+// InsertBefore inserts new item right before the given Element in O(1)
+// and returns the Element holding it.
+// If mark does not belong to the List, an error is returned.
+func (l *List[T]) InsertBefore(mark *Element[T], newItem T) (*Element[T], error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if mark == nil || mark.list != l {
+		return nil, fmt.Errorf("Cannot insert before an Element that is not in the List.")
+	}
+	return l.insertBefore(mark, newItem), nil
+}
+
+// InsertAfter inserts new item right after the given Element in O(1)
+// and returns the Element holding it.
+// If mark does not belong to the List, an error is returned.
+func (l *List[T]) InsertAfter(mark *Element[T], newItem T) (*Element[T], error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if mark == nil || mark.list != l {
+		return nil, fmt.Errorf("Cannot insert after an Element that is not in the List.")
+	}
+	return l.insertAfter(mark, newItem), nil
+}
+
+// RemoveElement removes the given Element from the List in O(1) and returns its value.
+// If e does not belong to the List (e.g. it has already been removed), an error is returned.
+func (l *List[T]) RemoveElement(e *Element[T]) (T, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if e == nil || e.list != l {
+		var zeroValue T
+		return zeroValue, fmt.Errorf("Cannot remove an Element that is not in the List.")
+	}
+	l.unlink(e)
+	return e.val, nil
+}
+
+// Remove removes the first occurence of the given item from the List.
+// It returns a bool indicating whether or not an item was removed.
+func (l *List[T]) Remove(item T) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for cursor := l.front; cursor != nil; cursor = cursor.next {
+		if l.comparator(cursor.val, item) == 0 {
+			l.unlink(cursor)
+			return true
+		}
+	}
+	return false
+}
+
+// RemoveAllFunc removes every item for which pred returns true in a single pass.
+// It returns the number of items removed.
+func (l *List[T]) RemoveAllFunc(pred func(T) bool) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	removed := 0
+	cursor := l.front
+	for cursor != nil {
+		next := cursor.next
+		if pred(cursor.val) {
+			l.unlink(cursor)
+			removed++
+		}
+		cursor = next
+	}
+	return removed
+}
+
+// Contains returns a bool indicating whether or not the item is in the List.
+func (l *List[T]) Contains(item T) bool {
+	return l.Find(item) != -1
+}
+
+// FindLast returns the index of the last occurence of the given item in the List.
+// If the item is not found in the List, -1 is returned.
+func (l *List[T]) FindLast(item T) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	cursor := l.back
+	for i := l.size - 1; i >= 0; i-- {
+		if l.comparator(cursor.val, item) == 0 {
+			return i
+		}
+		cursor = cursor.prev
+	}
+	return -1
+}
+
+// FindFunc returns the index and the value of the first item for which pred returns true.
+// If no such item is in the List, ok is false and index is -1.
+func (l *List[T]) FindFunc(pred func(T) bool) (index int, value T, ok bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	cursor := l.front
+	for i := 0; i < l.size; i++ {
+		if pred(cursor.val) {
+			return i, cursor.val, true
+		}
+		cursor = cursor.next
+	}
+	var zeroValue T
+	return -1, zeroValue, false
+}
+
+// Filter returns a pointer to a new List containing the items for which pred returns true.
+// The new List shares the comparator of this List.
+func (l *List[T]) Filter(pred func(T) bool) *List[T] {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	filtered := &List[T]{comparator: l.comparator}
+	for cursor := l.front; cursor != nil; cursor = cursor.next {
+		if pred(cursor.val) {
+			filtered.insertBack(cursor.val)
+		}
+	}
+	return filtered
+}
+
+// MapInPlace replaces every item of the List with the result of calling f on it.
+func (l *List[T]) MapInPlace(f func(T) T) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for cursor := l.front; cursor != nil; cursor = cursor.next {
+		cursor.val = f(cursor.val)
+	}
+}
+
+// Map returns a pointer to a new List containing the result of calling f on every item of l.
+// Map requires a comparator function for the new item type, since the new List
+// cannot reuse the comparator of l.
+func Map[T, U any](l *List[T], f func(T) U, comparator comparators.Comparator[U]) *List[U] {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	mapped := &List[U]{comparator: comparator}
+	for cursor := l.front; cursor != nil; cursor = cursor.next {
+		mapped.insertBack(f(cursor.val))
+	}
+	return mapped
+}
+
+// Reduce folds the items of l from front to back into a single value,
+// starting from init and calling f with the accumulator and each item.
+func Reduce[T, A any](l *List[T], init A, f func(A, T) A) A {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	accumulator := init
+	for cursor := l.front; cursor != nil; cursor = cursor.next {
+		accumulator = f(accumulator, cursor.val)
+	}
+	return accumulator
+}
+
+// lockPair locks the mutexes of two distinct Lists in a canonical (address) order,
+// so that concurrent operations on the same pair in opposite roles cannot deadlock.
+func lockPair[T any](a, b *List[T]) {
+	if uintptr(unsafe.Pointer(a)) < uintptr(unsafe.Pointer(b)) {
+		a.mu.Lock()
+		b.mu.Lock()
+	} else {
+		b.mu.Lock()
+		a.mu.Lock()
+	}
+}
+
+// detach removes all nodes from the List and returns the front, the back and the size of the chain.
+// The moved Elements are bound to the given owner, which visits each of them, so it runs in O(n).
+func (l *List[T]) detach(owner *List[T]) (*Element[T], *Element[T], int) {
+	front, back, size := l.front, l.back, l.size
+	for cursor := front; cursor != nil; cursor = cursor.next {
+		cursor.list = owner
+	}
+	l.front = nil
+	l.back = nil
+	l.size = 0
+	return front, back, size
+}
+
+// Concat moves all items of other to the back of the List, leaving other empty, in O(m)
+// where m is the size of other. The nodes themselves are moved rather than copied, but each
+// one is visited to rebind it, so that Element handles from other now belong to this List.
+// Concatenating a List with itself appends a copy of its items.
+func (l *List[T]) Concat(other *List[T]) {
+	if l == other {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		cursor := l.front
+		for i, size := 0, l.size; i < size; i++ {
+			l.insertBack(cursor.val)
+			cursor = cursor.next
+		}
+		return
+	}
+	lockPair(l, other)
+	defer l.mu.Unlock()
+	defer other.mu.Unlock()
+	front, back, size := other.detach(l)
+	if size == 0 {
+		return
+	}
+	if l.size == 0 {
+		l.front = front
+	} else {
+		l.back.next = front
+		front.prev = l.back
+	}
+	l.back = back
+	l.size += size
+}
+
+// Splice moves all items of other into the List at the given position, leaving other empty,
+// in O(n + m) where m is the size of other. Like Concat, it moves the nodes rather than
+// copying them and rebinds each one, so that Element handles from other now belong to this List.
+// If the position is invalid (aka if position < 0 || position > List.size) or other is the List itself,
+// an error is returned and neither List is modified.
+func (l *List[T]) Splice(position int, other *List[T]) error {
+	if l == other {
+		return fmt.Errorf("Cannot splice a List into itself.")
+	}
+	lockPair(l, other)
+	defer l.mu.Unlock()
+	defer other.mu.Unlock()
+	if position < 0 || position > l.size {
+		return fmt.Errorf("Cannot splice into a List of size %d at index %d.", l.size, position)
+	}
+	front, back, size := other.detach(l)
+	if size == 0 {
+		return nil
+	}
+	var before, after *Element[T]
+	if position == l.size {
+		before = l.back
+	} else {
+		after = l.elementAt(position)
+		before = after.prev
+	}
+	if before == nil {
+		l.front = front
+	} else {
+		before.next = front
+		front.prev = before
+	}
+	if after == nil {
+		l.back = back
+	} else {
+		after.prev = back
+		back.next = after
+	}
+	l.size += size
+	return nil
+}
+
+// SplitAt moves the items of the List into two new Lists, the first holding the items
+// before index i and the second holding the rest, and leaves the List empty.
+// The nodes are relinked rather than copied. An index below 0 or above the size of the List
+// is clamped, so one of the returned Lists may be empty.
+func (l *List[T]) SplitAt(i int) (*List[T], *List[T]) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if i < 0 {
+		i = 0
+	} else if i > l.size {
+		i = l.size
+	}
+	first := &List[T]{comparator: l.comparator, stringLimit: l.stringLimit}
+	second := &List[T]{comparator: l.comparator, stringLimit: l.stringLimit}
+	size := l.size
+	front, back, _ := l.detach(first)
+	cursor := front
+	for j := 0; j < i; j++ {
+		cursor = cursor.next
+	}
+	if i > 0 {
+		first.front = front
+		first.size = i
+		if cursor == nil {
+			first.back = back
+		} else {
+			first.back = cursor.prev
+			first.back.next = nil
+			cursor.prev = nil
+		}
+	}
+	if cursor != nil {
+		second.front = cursor
+		second.back = back
+		second.size = size - i
+		for ; cursor != nil; cursor = cursor.next {
+			cursor.list = second
+		}
+	}
+	return first, second
+}
+
+// MemUsage returns an approximation of the number of bytes used by the List,
+// including its nodes. Memory referenced by the items themselves (e.g. the
+// contents of strings or slices) is not counted; use MemUsageFunc for that.
+func (l *List[T]) MemUsage() int64 {
+	return l.MemUsageFunc(nil)
+}
+
+// MemUsageFunc is like MemUsage, but also adds the result of calling size on every item.
+// The size function should return the bytes referenced by an item beyond its own fixed size.
+// If size is nil, it behaves exactly like MemUsage.
+func (l *List[T]) MemUsageFunc(size func(T) int64) int64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var e Element[T]
+	total := int64(unsafe.Sizeof(*l)) + int64(l.size) * int64(unsafe.Sizeof(e))
+	if size != nil {
+		for cursor := l.front; cursor != nil; cursor = cursor.next {
+			total += size(cursor.val)
+		}
+	}
+	return total
+}
+
+// Rotate rotates the items of the List by n positions by relinking the front and the back.
+// A positive n moves items from the back to the front (e.g. [1 2 3] rotated by 1 is [3 1 2]),
+// and a negative n moves items from the front to the back.
+func (l *List[T]) Rotate(n int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.size < 2 {
+		return
+	}
+	// Number of items to move from the front to the back.
+	shift := ((-n % l.size) + l.size) % l.size
+	if shift == 0 {
+		return
+	}
+	newFront := l.front
+	for i := 0; i < shift; i++ {
+		newFront = newFront.next
+	}
+	newBack := newFront.prev
+	l.back.next = l.front
+	l.front.prev = l.back
+	newBack.next = nil
+	newFront.prev = nil
+	l.front = newFront
+	l.back = newBack
+}
+
+// Swap swaps the items at indices i and j of the List.
+// If either index is invalid (aka index < 0 || index >= List.size), an error is returned.
+func (l *List[T]) Swap(i, j int) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if i < 0 || i >= l.size || j < 0 || j >= l.size {
+		return fmt.Errorf("Cannot swap indices %d and %d in a List of size %d.", i, j, l.size)
+	}
+	a := l.elementAt(i)
+	b := l.elementAt(j)
+	a.val, b.val = b.val, a.val
+	return nil
+}
+
+// checkRange returns an error if [from, to) is not a valid range of the List.
+func (l *List[T]) checkRange(from, to int) error {
+	if from < 0 || to > l.size || from > to {
+		return fmt.Errorf("Cannot access range [%d, %d) in a List of size %d.", from, to, l.size)
+	}
+	return nil
+}
+
+// SubList returns a pointer to a new List holding a copy of the items from index from
+// (inclusive) to index to (exclusive).
+// If the range is invalid (aka from < 0 || to > List.size || from > to), an error is returned.
+func (l *List[T]) SubList(from, to int) (*List[T], error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if err := l.checkRange(from, to); err != nil {
+		return nil, err
+	}
+	subList := &List[T]{comparator: l.comparator, stringLimit: l.stringLimit}
+	if from == to {
+		return subList, nil
+	}
+	cursor := l.elementAt(from)
+	for i := from; i < to; i++ {
+		subList.insertBack(cursor.val)
+		cursor = cursor.next
+	}
+	return subList, nil
+}
+
+// Slice returns the items from index from (inclusive) to index to (exclusive) as a slice.
+// If the range is invalid (aka from < 0 || to > List.size || from > to), an error is returned.
+func (l *List[T]) Slice(from, to int) ([]T, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if err := l.checkRange(from, to); err != nil {
+		return nil, err
+	}
+	s := make([]T, to - from)
+	if from == to {
+		return s, nil
+	}
+	cursor := l.elementAt(from)
+	for i := range s {
+		s[i] = cursor.val
+		cursor = cursor.next
+	}
+	return s, nil
+}
+
+// Equals returns a bool indicating whether or not the List and other hold
+// the same items in the same order, according to the comparator of this List.
+func (l *List[T]) Equals(other *List[T]) bool {
+	return l.Compare(other) == 0
+}
+
+// Compare compares the List with other lexicographically, using the comparator of this List.
+// It returns -1 if the List is less than other, 1 if it is greater, and 0 if they are equal.
+// A List that is a prefix of the other List is the lesser one.
+func (l *List[T]) Compare(other *List[T]) int {
+	if l == other {
+		return 0
+	}
+	lockPair(l, other)
+	defer l.mu.Unlock()
+	defer other.mu.Unlock()
+	a, b := l.front, other.front
+	for a != nil && b != nil {
+		comparison := l.comparator(a.val, b.val)
+		if comparison < 0 {
+			return -1
+		} else if comparison > 0 {
+			return 1
+		}
+		a = a.next
+		b = b.next
+	}
+	if a == nil && b == nil {
+		return 0
+	} else if a == nil {
+		return -1
+	}
+	return 1
+}
+
+// Dedup removes items that are equal to the item right before them,
+// collapsing every run of consecutive duplicates into a single item.
+// It returns the number of items removed.
+func (l *List[T]) Dedup() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	removed := 0
+	if l.front == nil {
+		return removed
+	}
+	cursor := l.front.next
+	for cursor != nil {
+		next := cursor.next
+		if l.comparator(cursor.prev.val, cursor.val) == 0 {
+			l.unlink(cursor)
+			removed++
+		}
+		cursor = next
+	}
+	return removed
+}
+
+// Unique removes every item that is equal to an earlier item, preserving the first occurrence.
+// Since only the comparator is available, Unique runs in O(n^2); call Sort and then Dedup
+// instead when the order of the items does not matter.
+// It returns the number of items removed.
+func (l *List[T]) Unique() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	removed := 0
+	for outer := l.front; outer != nil; outer = outer.next {
+		cursor := outer.next
+		for cursor != nil {
+			next := cursor.next
+			if l.comparator(outer.val, cursor.val) == 0 {
+				l.unlink(cursor)
+				removed++
+			}
+			cursor = next
+		}
+	}
+	return removed
+}
+
+// ForEach calls f on every item of the List from front to back, stopping early if f returns false.
+// The lock is held once for the whole walk, so f sees a consistent List but must not call
+// any method of the same List (doing so deadlocks). Use ForEachSnapshot when f needs to
+// modify the List.
+func (l *List[T]) ForEach(f func(index int, value T) bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	i := 0
+	for cursor := l.front; cursor != nil; cursor = cursor.next {
+		if !f(i, cursor.val) {
+			return
+		}
+		i++
+	}
+}
+
+// ForEachSnapshot copies the items of the List under the lock and then calls f on every
+// copied item from front to back without holding the lock, stopping early if f returns false.
+// f may freely call methods of the List, including ones that modify it; such changes are not
+// reflected in the ongoing walk.
+func (l *List[T]) ForEachSnapshot(f func(index int, value T) bool) {
+	for i, value := range l.ToSlice() {
+		if !f(i, value) {
+			return
+		}
+	}
+}
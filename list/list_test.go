@@ -1,6 +1,8 @@
 package list
 
 import (
+	"fmt"
+	"slices"
 	"testing"
 
 	"github.com/davidpogosian/ds/comparators"
@@ -393,3 +395,734 @@ func TestToString(t *testing.T) {
 		testutils.Assert(t, "l.String()", "[1 2 3]", l.String())
 	})
 }
+
+func TestInsertSorted(t *testing.T) {
+	t.Run("Sequential", func(t *testing.T) {
+		l := NewEmpty[int](comparators.ComparatorInt)
+		for _, item := range []int{3, 1, 2, 5, 4, 0} {
+			l.InsertSorted(item)
+		}
+		testutils.Assert(t, "l.String()", "[0 1 2 3 4 5]", l.String())
+		testutils.Assert(t, "l.Size()", 6, l.Size())
+	})
+
+	t.Run("Concurrent", func(t *testing.T) {
+		l := NewEmpty[int](comparators.ComparatorInt)
+		testutils.ConcurrentOperations(t, 10, 100, func() error {
+			l.InsertSorted(l.Size() % 7)
+			return nil
+		})
+		testutils.Assert(t, "l.Size()", 1000, l.Size())
+		slice := l.ToSlice()
+		for j := 1; j < len(slice); j++ {
+			if slice[j - 1] > slice[j] {
+				t.Fatalf("List is not sorted at index %d.", j)
+			}
+		}
+	})
+}
+
+func TestSort(t *testing.T) {
+	t.Run("Empty", func(t *testing.T) {
+		l := NewEmpty[int](comparators.ComparatorInt)
+		l.Sort()
+		testutils.Assert(t, "l.String()", "[]", l.String())
+	})
+
+	t.Run("NotEmpty", func(t *testing.T) {
+		l := NewFromSlice([]int{5, 2, 4, 1, 3, 2}, comparators.ComparatorInt)
+		l.Sort()
+		testutils.Assert(t, "l.String()", "[1 2 2 3 4 5]", l.String())
+		l.Reverse()
+		testutils.Assert(t, "l.String()", "[5 4 3 2 2 1]", l.String())
+		l.InsertBack(0)
+		testutils.Assert(t, "l.Size()", 7, l.Size())
+	})
+}
+
+func TestSortBy(t *testing.T) {
+	words := []string{"ccc", "a", "bb", "d", "ee"}
+	l := NewFromSlice(words, comparators.ComparatorString)
+	SortBy(l, func(word string) int { return len(word) }, comparators.ComparatorInt)
+	testutils.AssertSlices(t, []string{"a", "d", "bb", "ee", "ccc"}, l.ToSlice())
+	testutils.Assert(t, "l.Back().Value()", "ccc", l.Back().Value())
+}
+
+func TestSortStableFunc(t *testing.T) {
+	l := NewFromSlice([]string{"ccc", "a", "bb", "d", "ee"}, comparators.ComparatorString)
+	l.SortStableFunc(func(a, b string) bool { return len(a) > len(b) })
+	testutils.AssertSlices(t, []string{"ccc", "bb", "ee", "a", "d"}, l.ToSlice())
+	l.Sort()
+	testutils.AssertSlices(t, []string{"a", "bb", "ccc", "d", "ee"}, l.ToSlice())
+}
+
+func TestAll(t *testing.T) {
+	t.Run("Empty", func(t *testing.T) {
+		l := NewEmpty[int](comparators.ComparatorInt)
+		for range l.All() {
+			t.Fatal("Iterated over an empty List.")
+		}
+	})
+
+	t.Run("NotEmpty", func(t *testing.T) {
+		l := NewFromSlice([]int{1, 2, 3}, comparators.ComparatorInt)
+		var slice []int
+		for v := range l.All() {
+			slice = append(slice, v)
+		}
+		testutils.AssertSlices(t, []int{1, 2, 3}, slice)
+	})
+
+	t.Run("Break", func(t *testing.T) {
+		l := NewFromSlice([]int{1, 2, 3}, comparators.ComparatorInt)
+		var slice []int
+		for v := range l.All() {
+			if v == 2 {
+				break
+			}
+			slice = append(slice, v)
+		}
+		testutils.AssertSlices(t, []int{1}, slice)
+		// The lock must have been released.
+		testutils.Assert(t, "l.Size()", 3, l.Size())
+	})
+
+	t.Run("ModifyDuringIteration", func(t *testing.T) {
+		l := NewFromSlice([]int{1, 2, 3}, comparators.ComparatorInt)
+		for v := range l.All() {
+			if v == 1 {
+				l.InsertBack(4)
+			}
+		}
+		testutils.Assert(t, "l.String()", "[1 2 3 4]", l.String())
+	})
+}
+
+func TestBackward(t *testing.T) {
+	l := NewFromSlice([]int{1, 2, 3}, comparators.ComparatorInt)
+	var slice []int
+	for v := range l.Backward() {
+		slice = append(slice, v)
+	}
+	testutils.AssertSlices(t, []int{3, 2, 1}, slice)
+}
+
+func TestEntries(t *testing.T) {
+	l := NewFromSlice([]string{"a", "b", "c"}, comparators.ComparatorString)
+	var indices []int
+	var values []string
+	for i, v := range l.Entries() {
+		indices = append(indices, i)
+		values = append(values, v)
+	}
+	testutils.AssertSlices(t, []int{0, 1, 2}, indices)
+	testutils.AssertSlices(t, []string{"a", "b", "c"}, values)
+}
+
+func TestElement(t *testing.T) {
+	t.Run("NextPrev", func(t *testing.T) {
+		l := NewEmpty[int](comparators.ComparatorInt)
+		one := l.InsertBack(1)
+		two := l.InsertBack(2)
+		zero := l.InsertFront(0)
+		testutils.Assert(t, "l.Front()", zero, l.Front())
+		testutils.Assert(t, "l.Back()", two, l.Back())
+		testutils.Assert(t, "zero.Next()", one, zero.Next())
+		testutils.Assert(t, "two.Prev()", one, two.Prev())
+		testutils.Assert(t, "two.Next() == nil", true, two.Next() == nil)
+		testutils.Assert(t, "zero.Prev() == nil", true, zero.Prev() == nil)
+		testutils.Assert(t, "one.Value()", 1, one.Value())
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		l := NewEmpty[int](comparators.ComparatorInt)
+		testutils.Assert(t, "l.Front() == nil", true, l.Front() == nil)
+		testutils.Assert(t, "l.Back() == nil", true, l.Back() == nil)
+	})
+}
+
+func TestInsertBeforeAfter(t *testing.T) {
+	t.Run("Sequential", func(t *testing.T) {
+		l := NewEmpty[int](comparators.ComparatorInt)
+		two := l.InsertBack(2)
+		if _, err := l.InsertBefore(two, 1); err != nil {
+			t.Fatal(err)
+		}
+		four, err := l.InsertAfter(two, 4)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := l.InsertBefore(four, 3); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := l.InsertAfter(four, 5); err != nil {
+			t.Fatal(err)
+		}
+		testutils.Assert(t, "l.String()", "[1 2 3 4 5]", l.String())
+		testutils.Assert(t, "l.Size()", 5, l.Size())
+		testutils.Assert(t, "l.Back().Value()", 5, l.Back().Value())
+	})
+
+	t.Run("ForeignElement", func(t *testing.T) {
+		l1 := NewEmpty[int](comparators.ComparatorInt)
+		l2 := NewEmpty[int](comparators.ComparatorInt)
+		e := l1.InsertBack(1)
+		if _, err := l2.InsertBefore(e, 0); err == nil {
+			t.Fatal("Inserted before an Element of a different List.")
+		}
+		if _, err := l2.InsertAfter(nil, 0); err == nil {
+			t.Fatal("Inserted after a nil Element.")
+		}
+	})
+}
+
+func TestRemoveElement(t *testing.T) {
+	t.Run("Sequential", func(t *testing.T) {
+		l := NewEmpty[int](comparators.ComparatorInt)
+		one := l.InsertBack(1)
+		two := l.InsertBack(2)
+		three := l.InsertBack(3)
+		val, err := l.RemoveElement(two)
+		if err != nil {
+			t.Fatal(err)
+		}
+		testutils.Assert(t, "val", 2, val)
+		testutils.Assert(t, "l.String()", "[1 3]", l.String())
+		testutils.Assert(t, "one.Next()", three, one.Next())
+		testutils.Assert(t, "two.Next() == nil", true, two.Next() == nil)
+		if _, err := l.RemoveElement(two); err == nil {
+			t.Fatal("Removed the same Element twice.")
+		}
+		if _, err := l.RemoveElement(one); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := l.RemoveElement(three); err != nil {
+			t.Fatal(err)
+		}
+		testutils.Assert(t, "l.Size()", 0, l.Size())
+		testutils.Assert(t, "l.Front() == nil", true, l.Front() == nil)
+	})
+
+	t.Run("AfterRemoveFront", func(t *testing.T) {
+		l := NewEmpty[int](comparators.ComparatorInt)
+		e := l.InsertBack(1)
+		l.InsertBack(2)
+		if _, err := l.RemoveFront(); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := l.RemoveElement(e); err == nil {
+			t.Fatal("Removed an Element that was already removed by RemoveFront.")
+		}
+		testutils.Assert(t, "l.Size()", 1, l.Size())
+	})
+
+	t.Run("Concurrent", func(t *testing.T) {
+		l := NewEmpty[int](comparators.ComparatorInt)
+		testutils.ConcurrentOperations(t, 10, 100, func() error {
+			e := l.InsertBack(1)
+			_, err := l.RemoveElement(e)
+			return err
+		})
+		testutils.Assert(t, "l.Size()", 0, l.Size())
+	})
+}
+
+func TestRemove(t *testing.T) {
+	t.Run("Sequential", func(t *testing.T) {
+		l := NewFromSlice([]int{1, 2, 3, 2}, comparators.ComparatorInt)
+		testutils.Assert(t, "l.Remove(2)", true, l.Remove(2))
+		testutils.Assert(t, "l.String()", "[1 3 2]", l.String())
+		testutils.Assert(t, "l.Remove(4)", false, l.Remove(4))
+		testutils.Assert(t, "l.Remove(2)", true, l.Remove(2))
+		testutils.Assert(t, "l.Remove(1)", true, l.Remove(1))
+		testutils.Assert(t, "l.Remove(3)", true, l.Remove(3))
+		testutils.Assert(t, "l.String()", "[]", l.String())
+	})
+
+	t.Run("Concurrent", func(t *testing.T) {
+		l := NewEmpty[int](comparators.ComparatorInt)
+		for i := 0; i < 1000; i++ {
+			l.InsertBack(1)
+		}
+		testutils.ConcurrentOperations(t, 10, 100, func() error {
+			if !l.Remove(1) {
+				return fmt.Errorf("Failed to remove 1 from the List.")
+			}
+			return nil
+		})
+		testutils.Assert(t, "l.Size()", 0, l.Size())
+	})
+}
+
+func TestRemoveAllFunc(t *testing.T) {
+	t.Run("Some", func(t *testing.T) {
+		l := NewFromSlice([]int{1, 2, 3, 4, 5, 6}, comparators.ComparatorInt)
+		removed := l.RemoveAllFunc(func(i int) bool { return i % 2 == 0 })
+		testutils.Assert(t, "removed", 3, removed)
+		testutils.Assert(t, "l.String()", "[1 3 5]", l.String())
+		testutils.Assert(t, "l.Size()", 3, l.Size())
+	})
+
+	t.Run("All", func(t *testing.T) {
+		l := NewFromSlice([]int{1, 2, 3}, comparators.ComparatorInt)
+		removed := l.RemoveAllFunc(func(i int) bool { return true })
+		testutils.Assert(t, "removed", 3, removed)
+		testutils.Assert(t, "l.String()", "[]", l.String())
+		l.InsertBack(4)
+		testutils.Assert(t, "l.String()", "[4]", l.String())
+	})
+}
+
+func TestContains(t *testing.T) {
+	l := NewFromSlice([]int{1, 2, 3}, comparators.ComparatorInt)
+	testutils.Assert(t, "l.Contains(2)", true, l.Contains(2))
+	testutils.Assert(t, "l.Contains(4)", false, l.Contains(4))
+}
+
+func TestFindLast(t *testing.T) {
+	l := NewFromSlice([]int{1, 2, 3, 2, 1}, comparators.ComparatorInt)
+	testutils.Assert(t, "l.FindLast(2)", 3, l.FindLast(2))
+	testutils.Assert(t, "l.FindLast(1)", 4, l.FindLast(1))
+	testutils.Assert(t, "l.FindLast(4)", -1, l.FindLast(4))
+}
+
+func TestFindFunc(t *testing.T) {
+	t.Run("Found", func(t *testing.T) {
+		l := NewFromSlice([]int{1, 4, 6}, comparators.ComparatorInt)
+		index, value, ok := l.FindFunc(func(i int) bool { return i % 2 == 0 })
+		testutils.Assert(t, "index", 1, index)
+		testutils.Assert(t, "value", 4, value)
+		testutils.Assert(t, "ok", true, ok)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		l := NewFromSlice([]int{1, 3, 5}, comparators.ComparatorInt)
+		index, value, ok := l.FindFunc(func(i int) bool { return i % 2 == 0 })
+		testutils.Assert(t, "index", -1, index)
+		testutils.Assert(t, "value", 0, value)
+		testutils.Assert(t, "ok", false, ok)
+	})
+}
+
+func TestFilter(t *testing.T) {
+	l := NewFromSlice([]int{1, 2, 3, 4}, comparators.ComparatorInt)
+	evens := l.Filter(func(i int) bool { return i % 2 == 0 })
+	testutils.Assert(t, "evens.String()", "[2 4]", evens.String())
+	testutils.Assert(t, "evens.Find(4)", 1, evens.Find(4))
+	testutils.Assert(t, "l.Size()", 4, l.Size())
+}
+
+func TestMapInPlace(t *testing.T) {
+	l := NewFromSlice([]int{1, 2, 3}, comparators.ComparatorInt)
+	l.MapInPlace(func(i int) int { return i * 10 })
+	testutils.Assert(t, "l.String()", "[10 20 30]", l.String())
+}
+
+func TestMap(t *testing.T) {
+	l := NewFromSlice([]int{1, 2, 3}, comparators.ComparatorInt)
+	strs := Map(l, func(i int) string { return fmt.Sprint(i) }, comparators.ComparatorString)
+	testutils.AssertSlices(t, []string{"1", "2", "3"}, strs.ToSlice())
+	testutils.Assert(t, "strs.Find(\"2\")", 1, strs.Find("2"))
+}
+
+func TestReduce(t *testing.T) {
+	t.Run("Empty", func(t *testing.T) {
+		l := NewEmpty[int](comparators.ComparatorInt)
+		testutils.Assert(t, "sum", 7, Reduce(l, 7, func(a, i int) int { return a + i }))
+	})
+
+	t.Run("NotEmpty", func(t *testing.T) {
+		l := NewFromSlice([]string{"a", "b", "c"}, comparators.ComparatorString)
+		joined := Reduce(l, "", func(a string, s string) string { return a + s })
+		testutils.Assert(t, "joined", "abc", joined)
+	})
+}
+
+func TestStringN(t *testing.T) {
+	l := NewFromSlice([]int{1, 2, 3, 4, 5}, comparators.ComparatorInt)
+	testutils.Assert(t, "l.StringN(2)", "[1 2 … (+3 more)]", l.StringN(2))
+	testutils.Assert(t, "l.StringN(5)", "[1 2 3 4 5]", l.StringN(5))
+	testutils.Assert(t, "l.StringN(-1)", "[1 2 3 4 5]", l.StringN(-1))
+	testutils.Assert(t, "l.StringN(0)", "[… (+5 more)]", l.StringN(0))
+}
+
+func TestSetStringLimit(t *testing.T) {
+	l := NewFromSlice([]int{1, 2, 3}, comparators.ComparatorInt)
+	l.SetStringLimit(1)
+	testutils.Assert(t, "l.String()", "[1 … (+2 more)]", l.String())
+	testutils.Assert(t, "l.Copy().String()", "[1 … (+2 more)]", l.Copy().String())
+	l.SetStringLimit(0)
+	testutils.Assert(t, "l.String()", "[1 2 3]", l.String())
+}
+
+func TestConcat(t *testing.T) {
+	t.Run("Sequential", func(t *testing.T) {
+		l1 := NewFromSlice([]int{1, 2}, comparators.ComparatorInt)
+		l2 := NewFromSlice([]int{3, 4}, comparators.ComparatorInt)
+		e := l2.Front()
+		l1.Concat(l2)
+		testutils.Assert(t, "l1.String()", "[1 2 3 4]", l1.String())
+		testutils.Assert(t, "l1.Size()", 4, l1.Size())
+		testutils.Assert(t, "l2.Size()", 0, l2.Size())
+		testutils.Assert(t, "l2.String()", "[]", l2.String())
+		if _, err := l1.RemoveElement(e); err != nil {
+			t.Fatal(err)
+		}
+		testutils.Assert(t, "l1.String()", "[1 2 4]", l1.String())
+		testutils.Assert(t, "l1.Back().Value()", 4, l1.Back().Value())
+	})
+
+	t.Run("EmptyReceiver", func(t *testing.T) {
+		l1 := NewEmpty[int](comparators.ComparatorInt)
+		l2 := NewFromSlice([]int{1}, comparators.ComparatorInt)
+		l1.Concat(l2)
+		l1.Concat(NewEmpty[int](comparators.ComparatorInt))
+		testutils.Assert(t, "l1.String()", "[1]", l1.String())
+	})
+
+	t.Run("Self", func(t *testing.T) {
+		l := NewFromSlice([]int{1, 2}, comparators.ComparatorInt)
+		l.Concat(l)
+		testutils.Assert(t, "l.String()", "[1 2 1 2]", l.String())
+	})
+
+	t.Run("Concurrent", func(t *testing.T) {
+		l1 := NewEmpty[int](comparators.ComparatorInt)
+		l2 := NewEmpty[int](comparators.ComparatorInt)
+		testutils.ConcurrentOperations(t, 10, 100, func() error {
+			l1.InsertBack(1)
+			l1.Concat(l2)
+			l2.Concat(l1)
+			return nil
+		})
+		testutils.Assert(t, "l1.Size() + l2.Size()", 1000, l1.Size() + l2.Size())
+	})
+}
+
+func TestSplice(t *testing.T) {
+	t.Run("Middle", func(t *testing.T) {
+		l1 := NewFromSlice([]int{1, 4}, comparators.ComparatorInt)
+		l2 := NewFromSlice([]int{2, 3}, comparators.ComparatorInt)
+		if err := l1.Splice(1, l2); err != nil {
+			t.Fatal(err)
+		}
+		testutils.Assert(t, "l1.String()", "[1 2 3 4]", l1.String())
+		testutils.Assert(t, "l2.Size()", 0, l2.Size())
+		l1.Reverse()
+		testutils.Assert(t, "l1.String()", "[4 3 2 1]", l1.String())
+	})
+
+	t.Run("Ends", func(t *testing.T) {
+		l := NewFromSlice([]int{2}, comparators.ComparatorInt)
+		if err := l.Splice(0, NewFromSlice([]int{1}, comparators.ComparatorInt)); err != nil {
+			t.Fatal(err)
+		}
+		if err := l.Splice(2, NewFromSlice([]int{3}, comparators.ComparatorInt)); err != nil {
+			t.Fatal(err)
+		}
+		testutils.Assert(t, "l.String()", "[1 2 3]", l.String())
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		l1 := NewFromSlice([]int{1}, comparators.ComparatorInt)
+		l2 := NewFromSlice([]int{2}, comparators.ComparatorInt)
+		if err := l1.Splice(2, l2); err == nil {
+			t.Fatal("Spliced into a List of size 1 at index 2.")
+		}
+		testutils.Assert(t, "l2.Size()", 1, l2.Size())
+		if err := l1.Splice(0, l1); err == nil {
+			t.Fatal("Spliced a List into itself.")
+		}
+	})
+}
+
+func TestSplitAt(t *testing.T) {
+	t.Run("Middle", func(t *testing.T) {
+		l := NewFromSlice([]int{1, 2, 3, 4}, comparators.ComparatorInt)
+		e := l.Back()
+		first, second := l.SplitAt(1)
+		testutils.Assert(t, "first.String()", "[1]", first.String())
+		testutils.Assert(t, "second.String()", "[2 3 4]", second.String())
+		testutils.Assert(t, "l.Size()", 0, l.Size())
+		if _, err := second.RemoveElement(e); err != nil {
+			t.Fatal(err)
+		}
+		testutils.Assert(t, "second.String()", "[2 3]", second.String())
+		first.InsertBack(5)
+		testutils.Assert(t, "first.String()", "[1 5]", first.String())
+	})
+
+	t.Run("Bounds", func(t *testing.T) {
+		l := NewFromSlice([]int{1, 2}, comparators.ComparatorInt)
+		first, second := l.SplitAt(-3)
+		testutils.Assert(t, "first.String()", "[]", first.String())
+		testutils.Assert(t, "second.String()", "[1 2]", second.String())
+		first, second = second.SplitAt(10)
+		testutils.Assert(t, "first.String()", "[1 2]", first.String())
+		testutils.Assert(t, "second.String()", "[]", second.String())
+	})
+}
+
+func TestMemUsage(t *testing.T) {
+	l := NewEmpty[int](comparators.ComparatorInt)
+	empty := l.MemUsage()
+	l.InsertBack(1)
+	l.InsertBack(2)
+	one := l.MemUsage()
+	if one <= empty {
+		t.Fatalf("Expected MemUsage to grow, got %d then %d.", empty, one)
+	}
+	testutils.Assert(t, "l.MemUsageFunc(...)", one + 20, l.MemUsageFunc(func(int) int64 { return 10 }))
+}
+
+func TestRotate(t *testing.T) {
+	t.Run("Positive", func(t *testing.T) {
+		l := NewFromSlice([]int{1, 2, 3, 4}, comparators.ComparatorInt)
+		l.Rotate(1)
+		testutils.Assert(t, "l.String()", "[4 1 2 3]", l.String())
+		l.Rotate(6)
+		testutils.Assert(t, "l.String()", "[2 3 4 1]", l.String())
+	})
+
+	t.Run("Negative", func(t *testing.T) {
+		l := NewFromSlice([]int{1, 2, 3, 4}, comparators.ComparatorInt)
+		l.Rotate(-1)
+		testutils.Assert(t, "l.String()", "[2 3 4 1]", l.String())
+		l.Reverse()
+		testutils.Assert(t, "l.String()", "[1 4 3 2]", l.String())
+	})
+
+	t.Run("Trivial", func(t *testing.T) {
+		l := NewFromSlice([]int{1}, comparators.ComparatorInt)
+		l.Rotate(3)
+		testutils.Assert(t, "l.String()", "[1]", l.String())
+		l = NewFromSlice([]int{1, 2}, comparators.ComparatorInt)
+		l.Rotate(4)
+		testutils.Assert(t, "l.String()", "[1 2]", l.String())
+	})
+}
+
+func TestSwap(t *testing.T) {
+	l := NewFromSlice([]int{1, 2, 3}, comparators.ComparatorInt)
+	if err := l.Swap(0, 2); err != nil {
+		t.Fatal(err)
+	}
+	testutils.Assert(t, "l.String()", "[3 2 1]", l.String())
+	if err := l.Swap(1, 1); err != nil {
+		t.Fatal(err)
+	}
+	testutils.Assert(t, "l.String()", "[3 2 1]", l.String())
+	if err := l.Swap(0, 3); err == nil {
+		t.Fatal("Swapped with an index out of range.")
+	}
+}
+
+func TestNegativeIndex(t *testing.T) {
+	t.Run("Get", func(t *testing.T) {
+		l := NewFromSlice([]int{1, 2, 3, 4, 5}, comparators.ComparatorInt)
+		for i, expected := range []int{5, 4, 3, 2, 1} {
+			val, err := l.Get(-i - 1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			testutils.Assert(t, "val", expected, val)
+		}
+		if _, err := l.Get(-6); err == nil {
+			t.Fatal("Accessed index -6 in a List of size 5.")
+		}
+	})
+
+	t.Run("InsertPosition", func(t *testing.T) {
+		l := NewFromSlice([]int{1, 3}, comparators.ComparatorInt)
+		if err := l.InsertPosition(2, -1); err != nil {
+			t.Fatal(err)
+		}
+		if err := l.InsertPosition(0, -3); err != nil {
+			t.Fatal(err)
+		}
+		testutils.Assert(t, "l.String()", "[0 1 2 3]", l.String())
+		if err := l.InsertPosition(9, -5); err == nil {
+			t.Fatal("Inserted at index -5 in a List of size 4.")
+		}
+	})
+
+	t.Run("RemovePosition", func(t *testing.T) {
+		l := NewFromSlice([]int{1, 2, 3}, comparators.ComparatorInt)
+		val, err := l.RemovePosition(-2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		testutils.Assert(t, "val", 2, val)
+		testutils.Assert(t, "l.String()", "[1 3]", l.String())
+		if _, err := l.RemovePosition(-3); err == nil {
+			t.Fatal("Removed index -3 in a List of size 2.")
+		}
+	})
+}
+
+func TestTryGet(t *testing.T) {
+	l := NewFromSlice([]int{1, 2, 3}, comparators.ComparatorInt)
+	for index, expected := range map[int]int{0: 1, 2: 3, -1: 3, -3: 1} {
+		item, ok := l.TryGet(index)
+		testutils.Assert(t, "ok", true, ok)
+		testutils.Assert(t, "item", expected, item)
+	}
+	for _, index := range []int{3, -4} {
+		if _, ok := l.TryGet(index); ok {
+			t.Fatalf("Got index %d of a List of size 3.", index)
+		}
+	}
+}
+
+func TestGetFromBack(t *testing.T) {
+	slice := make([]int, 101)
+	for i := range slice {
+		slice[i] = i
+	}
+	l := NewFromSlice(slice, comparators.ComparatorInt)
+	for i := range slice {
+		val, err := l.Get(i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		testutils.Assert(t, "val", i, val)
+	}
+}
+
+func TestSubList(t *testing.T) {
+	l := NewFromSlice([]int{1, 2, 3, 4, 5}, comparators.ComparatorInt)
+	subList, err := l.SubList(1, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutils.Assert(t, "subList.String()", "[2 3 4]", subList.String())
+	subList.InsertBack(6)
+	testutils.Assert(t, "l.Size()", 5, l.Size())
+	empty, err := l.SubList(5, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutils.Assert(t, "empty.String()", "[]", empty.String())
+	if _, err := l.SubList(3, 2); err == nil {
+		t.Fatal("Created a SubList with from > to.")
+	}
+	if _, err := l.SubList(0, 6); err == nil {
+		t.Fatal("Created a SubList past the end of the List.")
+	}
+}
+
+func TestSlice(t *testing.T) {
+	l := NewFromSlice([]int{1, 2, 3, 4, 5}, comparators.ComparatorInt)
+	s, err := l.Slice(3, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutils.AssertSlices(t, []int{4, 5}, s)
+	s, err = l.Slice(0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutils.AssertSlices(t, []int{}, s)
+	if _, err := l.Slice(-1, 2); err == nil {
+		t.Fatal("Sliced from a negative index.")
+	}
+}
+
+func TestEquals(t *testing.T) {
+	l1 := NewFromSlice([]int{1, 2, 3}, comparators.ComparatorInt)
+	l2 := NewFromSlice([]int{1, 2, 3}, comparators.ComparatorInt)
+	l3 := NewFromSlice([]int{1, 2}, comparators.ComparatorInt)
+	testutils.Assert(t, "l1.Equals(l2)", true, l1.Equals(l2))
+	testutils.Assert(t, "l1.Equals(l1)", true, l1.Equals(l1))
+	testutils.Assert(t, "l1.Equals(l3)", false, l1.Equals(l3))
+	testutils.Assert(t, "NewEmpty.Equals(NewEmpty)", true, NewEmpty[int](comparators.ComparatorInt).Equals(NewEmpty[int](comparators.ComparatorInt)))
+}
+
+func TestCompare(t *testing.T) {
+	t.Run("Sequential", func(t *testing.T) {
+		l1 := NewFromSlice([]int{1, 2, 3}, comparators.ComparatorInt)
+		l2 := NewFromSlice([]int{1, 3}, comparators.ComparatorInt)
+		l3 := NewFromSlice([]int{1, 2}, comparators.ComparatorInt)
+		testutils.Assert(t, "l1.Compare(l2)", -1, l1.Compare(l2))
+		testutils.Assert(t, "l2.Compare(l1)", 1, l2.Compare(l1))
+		testutils.Assert(t, "l3.Compare(l1)", -1, l3.Compare(l1))
+		testutils.Assert(t, "l1.Compare(l3)", 1, l1.Compare(l3))
+		testutils.Assert(t, "l1.Compare(l1.Copy())", 0, l1.Compare(l1.Copy()))
+	})
+
+	t.Run("Concurrent", func(t *testing.T) {
+		l1 := NewFromSlice([]int{1, 2, 3}, comparators.ComparatorInt)
+		l2 := NewFromSlice([]int{1, 2, 3}, comparators.ComparatorInt)
+		testutils.ConcurrentOperations(t, 10, 100, func() error {
+			if !l1.Equals(l2) || !l2.Equals(l1) {
+				return fmt.Errorf("Expected the Lists to be equal.")
+			}
+			return nil
+		})
+	})
+}
+
+func TestDedup(t *testing.T) {
+	l := NewFromSlice([]int{1, 1, 2, 2, 2, 1, 3, 3}, comparators.ComparatorInt)
+	testutils.Assert(t, "l.Dedup()", 4, l.Dedup())
+	testutils.Assert(t, "l.String()", "[1 2 1 3]", l.String())
+	testutils.Assert(t, "l.Size()", 4, l.Size())
+	testutils.Assert(t, "l.Back().Value()", 3, l.Back().Value())
+	empty := NewEmpty[int](comparators.ComparatorInt)
+	testutils.Assert(t, "empty.Dedup()", 0, empty.Dedup())
+}
+
+func TestUnique(t *testing.T) {
+	l := NewFromSlice([]int{3, 1, 3, 2, 1, 3}, comparators.ComparatorInt)
+	testutils.Assert(t, "l.Unique()", 3, l.Unique())
+	testutils.Assert(t, "l.String()", "[3 1 2]", l.String())
+	testutils.Assert(t, "l.Back().Value()", 2, l.Back().Value())
+}
+
+func TestForEach(t *testing.T) {
+	t.Run("All", func(t *testing.T) {
+		l := NewFromSlice([]int{1, 2, 3}, comparators.ComparatorInt)
+		sum := 0
+		l.ForEach(func(index int, value int) bool {
+			sum += index * value
+			return true
+		})
+		testutils.Assert(t, "sum", 8, sum)
+	})
+
+	t.Run("EarlyExit", func(t *testing.T) {
+		l := NewFromSlice([]int{1, 2, 3}, comparators.ComparatorInt)
+		visited := 0
+		l.ForEach(func(index int, value int) bool {
+			visited++
+			return value < 2
+		})
+		testutils.Assert(t, "visited", 2, visited)
+	})
+}
+
+func TestForEachSnapshot(t *testing.T) {
+	l := NewFromSlice([]int{1, 2, 3}, comparators.ComparatorInt)
+	visited := 0
+	l.ForEachSnapshot(func(index int, value int) bool {
+		visited++
+		l.InsertBack(value)
+		return true
+	})
+	testutils.Assert(t, "visited", 3, visited)
+	testutils.Assert(t, "l.String()", "[1 2 3 1 2 3]", l.String())
+	visited = 0
+	l.ForEachSnapshot(func(index int, value int) bool {
+		visited++
+		return index < 1
+	})
+	testutils.Assert(t, "visited", 2, visited)
+}
+
+func TestCollect(t *testing.T) {
+	l := Collect(slices.Values([]int{3, 1, 2}))
+	testutils.AssertSlices(t, []int{3, 1, 2}, l.ToSlice())
+	l.Sort()
+	testutils.AssertSlices(t, []int{1, 2, 3}, l.ToSlice())
+	testutils.AssertSlices(t, []int{1, 2, 3}, slices.Collect(l.All()))
+}
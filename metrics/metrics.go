@@ -0,0 +1,100 @@
+// Package metrics provides an optional, thread-safe registry that publishes
+// the size and operation counts of named containers through expvar, and
+// annotates operations with pprof labels.
+package metrics
+
+import (
+	"context"
+	"expvar"
+	"fmt"
+	"runtime/pprof"
+	"sync"
+)
+
+// Sizer is implemented by any container that reports its number of items,
+// which includes most containers in this library.
+type Sizer interface {
+	Size() int
+}
+
+// Registry struct represents a set of named containers published under
+// a single expvar name. Every container gets a "size" variable that is read
+// on demand, and an "ops" map of operation counters.
+// It also has a mutex for thread-safety.
+type Registry struct {
+	vars *expvar.Map
+	containers map[string]*expvar.Map
+	mu sync.Mutex
+}
+
+// publishMu serializes NewRegistry, so that two Registries cannot both see a name
+// as free and then race to publish it (expvar.Publish panics on duplicates).
+var publishMu sync.Mutex
+
+// NewRegistry returns a pointer to a new Registry published to expvar under the given name.
+// If the name is already published, an error is returned.
+func NewRegistry(name string) (*Registry, error) {
+	publishMu.Lock()
+	defer publishMu.Unlock()
+	if expvar.Get(name) != nil {
+		return nil, fmt.Errorf("Name '%s' is already published to expvar.", name)
+	}
+	r := &Registry{
+		vars: new(expvar.Map),
+		containers: make(map[string]*expvar.Map),
+	}
+	expvar.Publish(name, r.vars)
+	return r, nil
+}
+
+// Register adds a container to the Registry under the given name.
+// If the name is already registered, an error is returned.
+func (r *Registry) Register(name string, container Sizer) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.containers[name]; exists {
+		return fmt.Errorf("Container '%s' is already registered.", name)
+	}
+	vars := new(expvar.Map)
+	vars.Set("size", expvar.Func(func() any {
+		return container.Size()
+	}))
+	vars.Set("ops", new(expvar.Map))
+	r.containers[name] = vars
+	r.vars.Set(name, vars)
+	return nil
+}
+
+// Unregister removes the container with the given name from the Registry.
+// If the name is not registered, nothing happens.
+func (r *Registry) Unregister(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.containers, name)
+	r.vars.Delete(name)
+}
+
+// CountOp increments the counter of the given operation for the named container.
+// If the name is not registered, an error is returned.
+func (r *Registry) CountOp(name string, op string) error {
+	r.mu.Lock()
+	vars, exists := r.containers[name]
+	r.mu.Unlock()
+	if !exists {
+		return fmt.Errorf("Container '%s' is not registered.", name)
+	}
+	vars.Get("ops").(*expvar.Map).Add(op, 1)
+	return nil
+}
+
+// Do counts the given operation for the named container and calls f with
+// pprof labels "container" and "op" set, so that time spent in long operations
+// is attributed to the container in CPU profiles.
+// If the name is not registered, an error is returned and f is not called.
+func (r *Registry) Do(ctx context.Context, name string, op string, f func(context.Context)) error {
+	if err := r.CountOp(name, op); err != nil {
+		return err
+	}
+	pprof.Do(ctx, pprof.Labels("container", name, "op", op), f)
+	return nil
+}
@@ -0,0 +1,87 @@
+package metrics
+
+import (
+	"context"
+	"expvar"
+	"fmt"
+	"runtime/pprof"
+	"testing"
+
+	"github.com/davidpogosian/ds/comparators"
+	"github.com/davidpogosian/ds/stack"
+	"github.com/davidpogosian/ds/testutils"
+)
+
+func TestNewRegistry(t *testing.T) {
+	if _, err := NewRegistry("TestNewRegistry"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewRegistry("TestNewRegistry"); err == nil {
+		t.Fatal("Published two Registries under the same name.")
+	}
+}
+
+func TestNewRegistryConcurrent(t *testing.T) {
+	published := make(chan bool, 10)
+	testutils.ConcurrentOperations(t, 10, 1, func() error {
+		_, err := NewRegistry("TestNewRegistryConcurrent")
+		published <- err == nil
+		return nil
+	})
+	close(published)
+	count := 0
+	for ok := range published {
+		if ok {
+			count++
+		}
+	}
+	testutils.Assert(t, "count", 1, count)
+}
+
+func TestRegister(t *testing.T) {
+	r, err := NewRegistry("TestRegister")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := stack.NewFromSlice([]int{1, 2}, comparators.ComparatorInt)
+	if err := r.Register("stack", s); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Register("stack", s); err == nil {
+		t.Fatal("Registered two containers under the same name.")
+	}
+	vars := expvar.Get("TestRegister").(*expvar.Map)
+	size := vars.Get("stack").(*expvar.Map).Get("size")
+	testutils.Assert(t, "size.String()", "2", size.String())
+	s.Push(3)
+	testutils.Assert(t, "size.String()", "3", size.String())
+	r.Unregister("stack")
+	testutils.Assert(t, "vars.Get(\"stack\") == nil", true, vars.Get("stack") == nil)
+}
+
+func TestDo(t *testing.T) {
+	r, err := NewRegistry("TestDo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := stack.NewEmpty[int](comparators.ComparatorInt)
+	if err := r.Register("stack", s); err != nil {
+		t.Fatal(err)
+	}
+	testutils.ConcurrentOperations(t, 10, 10, func() error {
+		var label string
+		err := r.Do(context.Background(), "stack", "push", func(ctx context.Context) {
+			label, _ = pprof.Label(ctx, "container")
+			s.Push(1)
+		})
+		if err == nil && label != "stack" {
+			return fmt.Errorf("Expected label 'container' to be: stack, instead got: %s", label)
+		}
+		return err
+	})
+	ops := expvar.Get("TestDo").(*expvar.Map).Get("stack").(*expvar.Map).Get("ops").(*expvar.Map)
+	testutils.Assert(t, "push", "100", ops.Get("push").String())
+	if err := r.Do(context.Background(), "missing", "push", func(context.Context) {}); err == nil {
+		t.Fatal("Counted an operation for a container that is not registered.")
+	}
+}
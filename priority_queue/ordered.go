@@ -0,0 +1,81 @@
+package priority_queue
+
+import "fmt"
+
+// Interface is the priority queue API shared by PriorityQueue and OrderedQueue,
+// so that schedulers can be written once against either.
+type Interface[P, V any] interface {
+	Enqueue(p P, v V) error
+	Peek() (P, V, error)
+	ExtractTop() (P, V, error)
+	Size() int
+	IsEmpty() bool
+}
+
+// Ordered is implemented by ordered structures that can serve as a min priority queue,
+// such as *bst.BST. Each method must be atomic on its own.
+type Ordered[P, V any] interface {
+	Insert(p P, v V) error
+	PeekMin() (P, V, error)
+	DeleteMin() (P, V, error)
+	Size() int
+}
+
+// OrderedQueue struct represents a min priority queue backed by an ordered structure,
+// e.g. a BST keyed by deadline, which keeps answering range queries (Floor, Ascend,
+// CountBetween, ...) while it is used as a scheduler, instead of mirroring its data
+// into a PriorityQueue. The lowest priority is at the top, and ties are broken the
+// way the structure's DeleteMin breaks them. It has no lock of its own: it relies on
+// the structure being thread-safe. It has a field for the structure.
+type OrderedQueue[P, V any] struct {
+	ordered Ordered[P, V]
+}
+
+// FromOrdered returns a pointer to a new OrderedQueue backed by ordered.
+// The OrderedQueue starts with the nodes already in ordered, and changes made
+// through either one are seen by the other.
+func FromOrdered[P, V any](ordered Ordered[P, V]) *OrderedQueue[P, V] {
+	return &OrderedQueue[P, V]{ordered: ordered}
+}
+
+// Enqueue inserts a given value with given priority into the structure.
+// Errors of the structure's Insert (e.g. from a key validator) are returned.
+func (oq *OrderedQueue[P, V]) Enqueue(p P, v V) error {
+	return oq.ordered.Insert(p, v)
+}
+
+// Peek returns the lowest priority in the structure and its value.
+// If the structure is empty, its error is returned, wrapped.
+func (oq *OrderedQueue[P, V]) Peek() (P, V, error) {
+	p, v, err := oq.ordered.PeekMin()
+	if err != nil {
+		return p, v, fmt.Errorf("Cannot peek the OrderedQueue: %w", err)
+	}
+	return p, v, nil
+}
+
+// ExtractTop removes the node with the lowest priority from the structure
+// and returns its priority and value.
+// If the structure is empty, its error is returned, wrapped.
+func (oq *OrderedQueue[P, V]) ExtractTop() (P, V, error) {
+	p, v, err := oq.ordered.DeleteMin()
+	if err != nil {
+		return p, v, fmt.Errorf("Cannot extract top of the OrderedQueue: %w", err)
+	}
+	return p, v, nil
+}
+
+// Size returns the number of nodes in the structure.
+func (oq *OrderedQueue[P, V]) Size() int {
+	return oq.ordered.Size()
+}
+
+// IsEmpty returns a bool indicating whether the structure is empty.
+func (oq *OrderedQueue[P, V]) IsEmpty() bool {
+	return oq.ordered.Size() == 0
+}
+
+// Ordered returns the structure backing the OrderedQueue, e.g. to run range queries on it.
+func (oq *OrderedQueue[P, V]) Ordered() Ordered[P, V] {
+	return oq.ordered
+}
@@ -0,0 +1,80 @@
+package priority_queue
+
+import (
+	"testing"
+
+	"github.com/davidpogosian/ds/bst"
+	"github.com/davidpogosian/ds/comparators"
+	"github.com/davidpogosian/ds/testutils"
+)
+
+// drain extracts every node of pq and returns their values in order.
+func drain(t *testing.T, pq Interface[int, string]) []string {
+	values := []string{}
+	for !pq.IsEmpty() {
+		_, v, err := pq.ExtractTop()
+		if err != nil {
+			t.Fatal(err)
+		}
+		values = append(values, v)
+	}
+	return values
+}
+
+func TestOrderedQueue(t *testing.T) {
+	t.Run("Sequential", func(t *testing.T) {
+		tree := bst.NewEmpty[int, string](comparators.ComparatorInt)
+		tree.Insert(5, "e")
+		oq := FromOrdered[int, string](tree)
+		for p, v := range map[int]string{3: "c", 1: "a", 4: "d", 2: "b"} {
+			if err := oq.Enqueue(p, v); err != nil {
+				t.Fatal(err)
+			}
+		}
+		testutils.Assert(t, "oq.Size()", 5, oq.Size())
+		p, v, err := oq.Peek()
+		if err != nil {
+			t.Fatal(err)
+		}
+		testutils.Assert(t, "p", 1, p)
+		testutils.Assert(t, "v", "a", v)
+		// The BST still answers range queries.
+		testutils.Assert(t, "tree.CountBetween(2, 4)", 3, tree.CountBetween(2, 4))
+		testutils.AssertSlices(t, []string{"a", "b", "c", "d", "e"}, drain(t, oq))
+		if _, _, err := oq.Peek(); err == nil {
+			t.Fatal("Peeked an empty OrderedQueue.")
+		}
+		if _, _, err := oq.ExtractTop(); err == nil {
+			t.Fatal("Extracted top of an empty OrderedQueue.")
+		}
+	})
+	t.Run("Ties", func(t *testing.T) {
+		oq := FromOrdered[int, string](bst.NewEmpty[int, string](comparators.ComparatorInt))
+		oq.Enqueue(1, "a")
+		oq.Enqueue(0, "x")
+		oq.Enqueue(1, "b")
+		oq.Enqueue(1, "c")
+		testutils.AssertSlices(t, []string{"x", "a", "b", "c"}, drain(t, oq))
+	})
+	t.Run("Interface", func(t *testing.T) {
+		for _, pq := range []Interface[int, string]{
+			NewEmpty[int, string](comparators.ComparatorInt, true),
+			FromOrdered[int, string](bst.NewEmpty[int, string](comparators.ComparatorInt)),
+		} {
+			pq.Enqueue(2, "b")
+			pq.Enqueue(1, "a")
+			testutils.AssertSlices(t, []string{"a", "b"}, drain(t, pq))
+		}
+	})
+	t.Run("Concurrent", func(t *testing.T) {
+		oq := FromOrdered[int, string](bst.NewEmpty[int, string](comparators.ComparatorInt))
+		testutils.ConcurrentOperations(t, 10, 100, func() error {
+			if err := oq.Enqueue(1, "a"); err != nil {
+				return err
+			}
+			_, _, err := oq.ExtractTop()
+			return err
+		})
+		testutils.Assert(t, "oq.IsEmpty()", true, oq.IsEmpty())
+	})
+}
@@ -2,31 +2,54 @@
 package priority_queue
 
 import (
+	"cmp"
 	"fmt"
+	"iter"
 	"sync"
+	"unsafe"
 
 	"github.com/davidpogosian/ds/comparators"
+	"github.com/davidpogosian/ds/heaputil"
+	"github.com/davidpogosian/ds/internal/format"
 )
 
 // Node struct represents a single item in the priority queue.
 // It consists of two fields, one for determining priority,
-// and another for storing a value.
+// and another for storing a value, as well as the sequence number
+// of its Enqueue, used to break ties in a stable PriorityQueue.
 type Node[P, V any] struct {
 	p P
 	v V
+	seq uint64
+}
+
+// Entry struct represents a priority and value pair of the PriorityQueue.
+type Entry[P, V any] struct {
+	Priority P
+	Value V
 }
 
 // PriorityQueue struct represents a priority queue.
 // It contains a slice of the Node type that is used as a heap.
 // It also has a field to keep track of its size, a minHeap flag
 // (to specify whether a min heap or a max heap is used),
-// a comparator function for comparing priorities, and a mutex
-// for thread-safety.
+// a comparator function for comparing priorities, an optional validator
+// for rejecting priorities the comparator cannot order, an optional comparator
+// for finding values (see SetValueComparator), a flag set while
+// the heap slice may be shared with a copy, a stable flag (to break ties
+// by insertion order), the sequence number of the next Enqueue, a limit on the
+// number of entries printed by String (0 means no limit), and a mutex for thread-safety.
 type PriorityQueue[P, V any] struct {
 	heap []Node[P, V]
 	size int
 	minHeap bool
 	comparator comparators.Comparator[P]
+	validator comparators.Validator[P]
+	valueComparator comparators.Comparator[V]
+	shared bool
+	stable bool
+	seq uint64
+	stringLimit int
 	mu sync.Mutex
 }
 
@@ -44,101 +67,465 @@ func NewEmpty[P, V any](comparator comparators.Comparator[P], minHeap bool) *Pri
 	}
 }
 
-// heapifyUp restores the heap property of the PriorityQueue's heap by moving the
-// element at the given index up to its correct position.
-func (pq *PriorityQueue[P, V]) heapifyUp(index int) {
-	for index > 0 {
-		parentIndex := (index - 1) / 2
-		if pq.minHeap {
-			if pq.comparator(pq.heap[index].p, pq.heap[parentIndex].p) >= 0 {
-				break
-			}
-		} else {
-			if pq.comparator(pq.heap[index].p, pq.heap[parentIndex].p) <= 0 {
-				break
-			}
-		}
-		pq.heap[index], pq.heap[parentIndex] = pq.heap[parentIndex], pq.heap[index]
-		index = parentIndex
+// NewStable returns a pointer to a new empty PriorityQueue that breaks ties between equal
+// priorities by insertion order: among nodes of equal priority, the one enqueued first is
+// extracted first. It takes the same arguments as NewEmpty.
+func NewStable[P, V any](comparator comparators.Comparator[P], minHeap bool) *PriorityQueue[P, V] {
+	return &PriorityQueue[P, V]{
+		minHeap: minHeap,
+		comparator: comparator,
+		stable: true,
 	}
 }
 
+// NewNode returns a Node with the given priority and value.
+// It is intended for building the slice passed to NewFromSlice.
+func NewNode[P, V any](p P, v V) Node[P, V] {
+	return Node[P, V]{p: p, v: v}
+}
+
+// Priority returns the priority of the Node.
+func (n Node[P, V]) Priority() P {
+	return n.p
+}
+
+// Value returns the value of the Node.
+func (n Node[P, V]) Value() V {
+	return n.v
+}
+
+// NewFromSlice returns a pointer to a new PriorityQueue initialized with a slice of nodes.
+// The slice is copied and then heapified bottom-up in O(n).
+// NewFromSlice requires a comparator function to compare priorities and a boolean value
+// "minHeap", exactly like NewEmpty.
+func NewFromSlice[P, V any](nodes []Node[P, V], comparator comparators.Comparator[P], minHeap bool) *PriorityQueue[P, V] {
+	heap := make([]Node[P, V], len(nodes))
+	copy(heap, nodes)
+	pq := &PriorityQueue[P, V]{
+		heap: heap,
+		size: len(heap),
+		minHeap: minHeap,
+		comparator: comparator,
+	}
+	pq.heapify()
+	return pq
+}
+
+// NewFromSlices returns a pointer to a new PriorityQueue where priorities[i] is the
+// priority of values[i]. The heap is built bottom-up in O(n).
+// If the slices are of different lengths, an error is returned.
+func NewFromSlices[P, V any](priorities []P, values []V, comparator comparators.Comparator[P], minHeap bool) (*PriorityQueue[P, V], error) {
+	if len(priorities) != len(values) {
+		return nil, fmt.Errorf("Cannot pair %d priorities with %d values.", len(priorities), len(values))
+	}
+	heap := make([]Node[P, V], len(priorities))
+	for i := range priorities {
+		heap[i] = Node[P, V]{p: priorities[i], v: values[i]}
+	}
+	pq := &PriorityQueue[P, V]{
+		heap: heap,
+		size: len(heap),
+		minHeap: minHeap,
+		comparator: comparator,
+	}
+	pq.heapify()
+	return pq, nil
+}
+
+// heapify establishes the heap property over the whole heap.
+func (pq *PriorityQueue[P, V]) heapify() {
+	heaputil.Init(pq.heap, pq.compare)
+}
+
+// compare orders nodes for heaputil: a negative result means node a belongs above
+// node b in the heap, because a has the higher priority or, in a stable PriorityQueue,
+// an equal priority and an earlier Enqueue.
+func (pq *PriorityQueue[P, V]) compare(a, b Node[P, V]) int {
+	comparison := pq.comparator(a.p, b.p)
+	if !pq.minHeap {
+		comparison = -comparison
+	}
+	if comparison != 0 || !pq.stable {
+		return comparison
+	}
+	return cmp.Compare(a.seq, b.seq)
+}
+
+// SetPriorityValidator sets a validator that Enqueue calls on every priority.
+// Priorities rejected by the validator are not enqueued. Passing nil removes the validator.
+// For built-in float types, the comparators package provides ready-made validators
+// (e.g., comparators.ValidatorFloat64 for float64).
+func (pq *PriorityQueue[P, V]) SetPriorityValidator(validator comparators.Validator[P]) {
+	pq.mu.Lock()
+	defer pq.mu.Unlock()
+	pq.validator = validator
+}
+
+// SetValueComparator sets the comparator that UpdatePriority and Remove use to find values.
+// Passing nil removes it.
+func (pq *PriorityQueue[P, V]) SetValueComparator(valueComparator comparators.Comparator[V]) {
+	pq.mu.Lock()
+	defer pq.mu.Unlock()
+	pq.valueComparator = valueComparator
+}
+
 // Enqueue enqueues a given value with given priority into the heap
 // of the PriorityQueue.
-func (pq *PriorityQueue[P, V]) Enqueue(p P, v V) {
+// If a priority validator is set and rejects the priority, its error is returned.
+func (pq *PriorityQueue[P, V]) Enqueue(p P, v V) (err error) {
+	defer comparators.Recover(&err)
 	pq.mu.Lock()
 	defer pq.mu.Unlock()
+	if pq.validator != nil {
+		if err := pq.validator(p); err != nil {
+			return fmt.Errorf("Cannot enqueue priority '%v' into the PriorityQueue: %w", p, err)
+		}
+	}
 	n := Node[P, V] {
 		p: p,
 		v: v,
+		seq: pq.seq,
 	}
-	pq.heap = append(pq.heap, n)
+	pq.seq++
+	pq.unshare()
+	pq.heap = heaputil.Push(pq.heap, n, pq.compare)
 	pq.size++
-	pq.heapifyUp(pq.size - 1)
+	return nil
 }
 
 // Peek returns the priority and the value of the node at the top of heap
 // of the PriorityQueue.
 // If the heap is empty, an error is returned.
 func (pq *PriorityQueue[P, V]) Peek() (P, V, error) {
+	p, v, ok := pq.TryPeek()
+	if !ok {
+		return p, v, fmt.Errorf("Cannot peek an empty PriorityQueue")
+	}
+	return p, v, nil
+}
+
+// TryPeek returns the priority and the value of the node at the top of heap
+// of the PriorityQueue and true, or returns false if the heap is empty.
+func (pq *PriorityQueue[P, V]) TryPeek() (P, V, bool) {
 	pq.mu.Lock()
 	defer pq.mu.Unlock()
 	if pq.size == 0 {
 		var zeroPriority P
 		var zeroValue V
-		return zeroPriority, zeroValue, fmt.Errorf("Cannot peek an empty PriorityQueue")
+		return zeroPriority, zeroValue, false
 	}
-	return pq.heap[0].p, pq.heap[0].v, nil
+	return pq.heap[0].p, pq.heap[0].v, true
 }
 
-// heapifyDown restores the heap property of the PriorityQueue's heap by moving the
-// element at the given index down to its correct position.
-func (pq *PriorityQueue[P, V]) heapifyDown(index int) {
-	for {
-		leftChild := 2 * index + 1
-		rightChild := 2 * index + 2
-		smallestOrLargest := index
-		if pq.minHeap {
-			if leftChild < pq.size && pq.comparator(pq.heap[leftChild].p, pq.heap[smallestOrLargest].p) == -1 {
-				smallestOrLargest = leftChild
-			}
-			if rightChild < pq.size && pq.comparator(pq.heap[rightChild].p, pq.heap[smallestOrLargest].p) == -1 {
-				smallestOrLargest = rightChild
-			}
-		} else {
-			if leftChild < pq.size && pq.comparator(pq.heap[leftChild].p, pq.heap[smallestOrLargest].p) == 1 {
-				smallestOrLargest = leftChild
+// ExtractTop removes the node at the top of the heap
+// and returns the corresponding priority and value.
+// If the heap is empty, an error is returned.
+func (pq *PriorityQueue[P, V]) ExtractTop() (_ P, _ V, err error) {
+	defer comparators.Recover(&err)
+	p, v, ok := pq.TryExtractTop()
+	if !ok {
+		return p, v, fmt.Errorf("Cannot extract top on an empty PriorityQueue")
+	}
+	return p, v, nil
+}
+
+// TryExtractTop removes the node at the top of the heap and returns the corresponding
+// priority and value and true, or returns false if the heap is empty.
+// Unlike ExtractTop, it never allocates an error, so it does not recover panics
+// of a comparator wrapped with comparators.Recovering either.
+func (pq *PriorityQueue[P, V]) TryExtractTop() (P, V, bool) {
+	pq.mu.Lock()
+	defer pq.mu.Unlock()
+	if pq.size == 0 {
+		var zeroPriority P
+		var zeroValue V
+		return zeroPriority, zeroValue, false
+	}
+	pq.unshare()
+	// The heap is not empty, so Pop cannot fail.
+	top, heap, _ := heaputil.Pop(pq.heap, pq.compare)
+	pq.heap = heap
+	pq.size--
+	return top.p, top.v, true
+}
+
+// ExtractTopN removes the top n nodes of the heap under a single lock
+// and returns them in the order ExtractTop would, in O(n log size).
+// An error is returned, and nothing is removed, if n is negative or greater than the size.
+// If the comparator panics (see comparators.Recovering), the nodes removed before the panic
+// are returned along with the error.
+func (pq *PriorityQueue[P, V]) ExtractTopN(n int) (nodes []Node[P, V], err error) {
+	defer comparators.Recover(&err)
+	pq.mu.Lock()
+	defer pq.mu.Unlock()
+	if n < 0 || n > pq.size {
+		return nil, fmt.Errorf("Cannot extract %d nodes from a PriorityQueue of size %d.", n, pq.size)
+	}
+	nodes = make([]Node[P, V], 0, n)
+	pq.extractTopN(n, &nodes)
+	return nodes, nil
+}
+
+// DrainSorted removes all nodes of the heap under a single lock
+// and returns them in the order ExtractTop would, e.g. to flush on shutdown.
+func (pq *PriorityQueue[P, V]) DrainSorted() []Node[P, V] {
+	pq.mu.Lock()
+	defer pq.mu.Unlock()
+	nodes := make([]Node[P, V], 0, pq.size)
+	pq.extractTopN(pq.size, &nodes)
+	return nodes
+}
+
+// extractTopN removes the top n nodes of the heap, n being at most the size,
+// and appends them in order to nodes. Each node is appended as soon as it is removed,
+// so if the comparator panics, the nodes removed before the panic are not lost.
+func (pq *PriorityQueue[P, V]) extractTopN(n int, nodes *[]Node[P, V]) {
+	pq.unshare()
+	for i := 0; i < n; i++ {
+		var node Node[P, V]
+		node, pq.heap, _ = heaputil.Pop(pq.heap, pq.compare)
+		pq.size--
+		*nodes = append(*nodes, node)
+	}
+}
+
+// PeekN returns the top n nodes of the heap in the order ExtractTop would return them,
+// without removing them. It explores the heap from the root with a second heap of
+// candidates, so it runs in O(n log n) whatever the size of the PriorityQueue.
+// An error is returned if n is negative or greater than the size.
+func (pq *PriorityQueue[P, V]) PeekN(n int) (_ []Node[P, V], err error) {
+	defer comparators.Recover(&err)
+	pq.mu.Lock()
+	defer pq.mu.Unlock()
+	if n < 0 || n > pq.size {
+		return nil, fmt.Errorf("Cannot peek %d nodes of a PriorityQueue of size %d.", n, pq.size)
+	}
+	nodes := make([]Node[P, V], 0, n)
+	for node := range pq.sorted(pq.heap[:pq.size]) {
+		if len(nodes) == n {
+			break
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes, nil
+}
+
+// sorted returns an iterator over the nodes of heap in the order ExtractTop would return them.
+// It explores heap from the root with a second heap of candidates instead of modifying it,
+// so the first k nodes cost O(k log k) whatever the size of heap.
+func (pq *PriorityQueue[P, V]) sorted(heap []Node[P, V]) iter.Seq[Node[P, V]] {
+	return func(yield func(Node[P, V]) bool) {
+		if len(heap) == 0 {
+			return
+		}
+		compareIndices := func(a, b int) int {
+			return pq.compare(heap[a], heap[b])
+		}
+		candidates := []int{0}
+		for len(candidates) > 0 {
+			var index int
+			index, candidates, _ = heaputil.Pop(candidates, compareIndices)
+			if !yield(heap[index]) {
+				return
 			}
-			if rightChild < pq.size && pq.comparator(pq.heap[rightChild].p, pq.heap[smallestOrLargest].p) == 1 {
-				smallestOrLargest = rightChild
+			for _, child := range []int{2 * index + 1, 2 * index + 2} {
+				if child < len(heap) {
+					candidates = heaputil.Push(candidates, child, compareIndices)
+				}
 			}
 		}
-		if smallestOrLargest == index {
+	}
+}
+
+// ToSlice returns the entries of the PriorityQueue in heap order, which is cheap to produce
+// but only guarantees that the first entry is the top one. Use ToSortedSlice for priority order.
+func (pq *PriorityQueue[P, V]) ToSlice() []Entry[P, V] {
+	pq.mu.Lock()
+	defer pq.mu.Unlock()
+	entries := make([]Entry[P, V], pq.size)
+	for i, node := range pq.heap[:pq.size] {
+		entries[i] = Entry[P, V]{Priority: node.p, Value: node.v}
+	}
+	return entries
+}
+
+// ToSortedSlice returns the entries of the PriorityQueue in the order ExtractTop would return them,
+// in O(n log n), without modifying the PriorityQueue.
+func (pq *PriorityQueue[P, V]) ToSortedSlice() []Entry[P, V] {
+	pq.mu.Lock()
+	defer pq.mu.Unlock()
+	return pq.sortedEntries(pq.size)
+}
+
+// sortedEntries returns the first max entries of the PriorityQueue in the order
+// ExtractTop would return them.
+func (pq *PriorityQueue[P, V]) sortedEntries(max int) []Entry[P, V] {
+	entries := make([]Entry[P, V], 0, max)
+	for node := range pq.sorted(pq.heap[:pq.size]) {
+		if len(entries) == max {
 			break
 		}
-		pq.heap[index], pq.heap[smallestOrLargest] = pq.heap[smallestOrLargest], pq.heap[index]
-		index = smallestOrLargest
+		entries = append(entries, Entry[P, V]{Priority: node.p, Value: node.v})
 	}
+	return entries
 }
 
-// ExtractTop removes the node at the top of the heap
-// and returns the corresponding priority and value.
-// If the heap is empty, an error is returned.
-func (pq *PriorityQueue[P, V]) ExtractTop() (P, V, error) {
+// All returns an iterator over the priorities and values of the PriorityQueue in the order
+// ExtractTop would return them. It iterates over a snapshot taken in O(1) when iteration
+// starts (the heap is shared copy-on-write, like with Copy), so the loop body may call
+// methods on the PriorityQueue (including ones that modify it), and breaking out early
+// after k items costs only O(k log k).
+func (pq *PriorityQueue[P, V]) All() iter.Seq2[P, V] {
+	return func(yield func(P, V) bool) {
+		pq.mu.Lock()
+		pq.shared = true
+		heap := pq.heap[:pq.size]
+		pq.mu.Unlock()
+		for node := range pq.sorted(heap) {
+			if !yield(node.p, node.v) {
+				return
+			}
+		}
+	}
+}
+
+// String returns the string representation of the PriorityQueue, with its entries
+// in the order ExtractTop would return them, e.g. "[{1 a} {2 b}]".
+// If a limit was set with SetStringLimit, only that many entries are printed.
+func (pq *PriorityQueue[P, V]) String() string {
 	pq.mu.Lock()
 	defer pq.mu.Unlock()
-	if pq.size == 0 {
-		var zeroPriority P
-		var zeroValue V
-		return zeroPriority, zeroValue, fmt.Errorf("Cannot extract top on an empty PriorityQueue")
+	if pq.stringLimit > 0 {
+		return pq.stringN(pq.stringLimit)
+	}
+	return pq.stringN(-1)
+}
+
+// StringN returns the string representation of the top max entries of the PriorityQueue,
+// followed by "… (+N more)" if entries were left out.
+// If max is negative, all entries are printed.
+func (pq *PriorityQueue[P, V]) StringN(max int) string {
+	pq.mu.Lock()
+	defer pq.mu.Unlock()
+	return pq.stringN(max)
+}
+
+// stringN returns the string representation of the top max entries of the PriorityQueue.
+func (pq *PriorityQueue[P, V]) stringN(max int) string {
+	limit := format.Limit(pq.size, max)
+	return format.Truncated(pq.sortedEntries(limit), pq.size - limit)
+}
+
+// SetStringLimit limits String to printing the top max entries of the PriorityQueue.
+// A max of 0 or less removes the limit.
+func (pq *PriorityQueue[P, V]) SetStringLimit(max int) {
+	pq.mu.Lock()
+	defer pq.mu.Unlock()
+	pq.stringLimit = max
+}
+
+// UpdatePriority changes the priority of the first node found whose value is equal to v
+// according to the value comparator, and moves it to its new place in the heap in O(n).
+// An error is returned if no value comparator is set, if no such node exists, or if
+// a priority validator is set and rejects the new priority.
+func (pq *PriorityQueue[P, V]) UpdatePriority(v V, newPriority P) (err error) {
+	defer comparators.Recover(&err)
+	pq.mu.Lock()
+	defer pq.mu.Unlock()
+	index, err := pq.findValue(v)
+	if err != nil {
+		return err
+	}
+	if pq.validator != nil {
+		if err := pq.validator(newPriority); err != nil {
+			return fmt.Errorf("Cannot update to priority '%v' in the PriorityQueue: %w", newPriority, err)
+		}
+	}
+	pq.unshare()
+	node := pq.heap[index]
+	node.p = newPriority
+	heaputil.Replace(pq.heap, index, node, pq.compare)
+	return nil
+}
+
+// Remove removes the first node found whose value is equal to v according to the
+// value comparator in O(n).
+// An error is returned if no value comparator is set or if no such node exists.
+func (pq *PriorityQueue[P, V]) Remove(v V) (err error) {
+	defer comparators.Recover(&err)
+	pq.mu.Lock()
+	defer pq.mu.Unlock()
+	index, err := pq.findValue(v)
+	if err != nil {
+		return err
+	}
+	pq.removeAt(index)
+	return nil
+}
+
+// findValue returns the index in the heap of the first node whose value is equal to v
+// according to the value comparator, or an error if there is none.
+func (pq *PriorityQueue[P, V]) findValue(v V) (int, error) {
+	if pq.valueComparator == nil {
+		return -1, fmt.Errorf("Cannot find a value in a PriorityQueue without a value comparator.")
 	}
-	p := pq.heap[0].p
-	v := pq.heap[0].v
-	pq.heap[0] = pq.heap[pq.size - 1]
+	index := pq.indexOfValue(v, func(a, b V) bool {
+		return pq.valueComparator(a, b) == 0
+	})
+	if index == -1 {
+		return -1, fmt.Errorf("Cannot find value '%v' in the PriorityQueue.", v)
+	}
+	return index, nil
+}
+
+// RemoveValue removes the first node found whose value is equal to v according to eq,
+// and returns true. If no such node exists, false is returned.
+// It scans the whole heap, so it runs in O(n).
+func (pq *PriorityQueue[P, V]) RemoveValue(v V, eq func(V, V) bool) bool {
+	pq.mu.Lock()
+	defer pq.mu.Unlock()
+	index := pq.indexOfValue(v, eq)
+	if index == -1 {
+		return false
+	}
+	pq.removeAt(index)
+	return true
+}
+
+// ReplaceValue replaces the value of the first node found whose value is equal to old
+// according to eq with new, keeping its priority, and returns true.
+// If no such node exists, false is returned.
+// It scans the whole heap, so it runs in O(n).
+func (pq *PriorityQueue[P, V]) ReplaceValue(old, new V, eq func(V, V) bool) bool {
+	pq.mu.Lock()
+	defer pq.mu.Unlock()
+	index := pq.indexOfValue(old, eq)
+	if index == -1 {
+		return false
+	}
+	pq.unshare()
+	pq.heap[index].v = new
+	return true
+}
+
+// indexOfValue returns the index in the heap of the first node whose value is
+// equal to v according to eq, or -1 if there is none.
+func (pq *PriorityQueue[P, V]) indexOfValue(v V, eq func(V, V) bool) int {
+	for i := 0; i < pq.size; i++ {
+		if eq(pq.heap[i].v, v) {
+			return i
+		}
+	}
+	return -1
+}
+
+// removeAt removes the node at the given index of the heap, which must be in range,
+// and restores the heap property.
+func (pq *PriorityQueue[P, V]) removeAt(index int) {
+	pq.unshare()
+	_, heap, _ := heaputil.Remove(pq.heap, index, pq.compare)
+	pq.heap = heap
 	pq.size--
-	pq.heap = pq.heap[:pq.size]
-	pq.heapifyDown(0)
-	return p, v, nil
 }
 
 // Clear removes all items from the PriorityQueue.
@@ -147,6 +534,7 @@ func (pq *PriorityQueue[P, V]) Clear() {
 	defer pq.mu.Unlock()
 	pq.heap = []Node[P, V]{}
 	pq.size = 0
+	pq.shared = false
 }
 
 // Size returns the number of items in the PriorityQueue.
@@ -163,21 +551,106 @@ func (pq *PriorityQueue[P, V]) IsEmpty() bool {
 	return pq.size == 0
 }
 
-// Copy returns a pointer to a copy of this PriorityQueue.
+// Copy returns a pointer to a copy of this PriorityQueue in O(1).
+// The copy shares the heap slice with this PriorityQueue until either of them
+// is next modified, at which point the modified one copies the heap first.
+// This keeps the lock short when copying a large, busy PriorityQueue.
 func (pq *PriorityQueue[P, V]) Copy() *PriorityQueue[P, V] {
 	pq.mu.Lock()
 	defer pq.mu.Unlock()
-	newHeap := make([]Node[P, V], pq.size)
-	for i, node := range pq.heap {
-		newHeap[i] = Node[P, V]{
-			p: node.p,
-			v: node.v,
-		}
+	pq.shared = true
+	return &PriorityQueue[P, V]{
+		heap: pq.heap,
+		size: pq.size,
+		minHeap: pq.minHeap,
+		comparator: pq.comparator,
+		validator: pq.validator,
+		valueComparator: pq.valueComparator,
+		shared: true,
+		stable: pq.stable,
+		seq: pq.seq,
+		stringLimit: pq.stringLimit,
+	}
+}
+
+// Merge adds copies of all nodes of other to the PriorityQueue and re-heapifies it in O(n+m),
+// instead of the O(m log(n+m)) of enqueueing them one by one. Priorities are compared with
+// the comparator of the PriorityQueue, and the priority validator is not applied.
+// In a stable PriorityQueue, the nodes of other keep their relative order and count as
+// enqueued after the existing ones. other is not modified.
+// other is snapshotted under its own lock first, so the two queues are never locked at once.
+func (pq *PriorityQueue[P, V]) Merge(other *PriorityQueue[P, V]) {
+	other.mu.Lock()
+	otherNodes := make([]Node[P, V], other.size)
+	copy(otherNodes, other.heap)
+	otherSeq := other.seq
+	other.mu.Unlock()
+	pq.mu.Lock()
+	defer pq.mu.Unlock()
+	pq.unshare()
+	for _, n := range otherNodes {
+		n.seq += pq.seq
+		pq.heap = append(pq.heap, n)
 	}
+	pq.seq += otherSeq
+	pq.size = len(pq.heap)
+	pq.heapify()
+}
+
+// unshare gives the PriorityQueue its own heap slice if it may be shared with a copy.
+// It must be called before the heap is modified.
+func (pq *PriorityQueue[P, V]) unshare() {
+	if !pq.shared {
+		return
+	}
+	heap := make([]Node[P, V], pq.size, max(cap(pq.heap), 1))
+	copy(heap, pq.heap)
+	pq.heap = heap
+	pq.shared = false
+}
+
+// CopyCompact returns a pointer to a copy of the PriorityQueue whose heap slice holds exactly
+// its nodes. Unlike Copy, it copies the heap right away, in O(n).
+func (pq *PriorityQueue[P, V]) CopyCompact() *PriorityQueue[P, V] {
+	pq.mu.Lock()
+	defer pq.mu.Unlock()
+	heap := make([]Node[P, V], pq.size)
+	copy(heap, pq.heap)
 	return &PriorityQueue[P, V]{
-		heap: newHeap,
+		heap: heap,
 		size: pq.size,
 		minHeap: pq.minHeap,
 		comparator: pq.comparator,
+		validator: pq.validator,
+		valueComparator: pq.valueComparator,
+		stable: pq.stable,
+		seq: pq.seq,
+		stringLimit: pq.stringLimit,
+	}
+}
+
+// MemUsage returns an approximation of the number of bytes used by the PriorityQueue,
+// including its whole backing heap slice. Memory referenced by the priorities and values
+// themselves (e.g. the contents of strings) is not counted; use MemUsageFunc for that.
+func (pq *PriorityQueue[P, V]) MemUsage() int64 {
+	return pq.MemUsageFunc(nil, nil)
+}
+
+// MemUsageFunc is like MemUsage, but also adds the result of calling prioritySize on every
+// priority and valueSize on every value. They should return the bytes referenced beyond the
+// fixed size. Either function may be nil.
+func (pq *PriorityQueue[P, V]) MemUsageFunc(prioritySize func(P) int64, valueSize func(V) int64) int64 {
+	pq.mu.Lock()
+	defer pq.mu.Unlock()
+	var n Node[P, V]
+	total := int64(unsafe.Sizeof(*pq)) + int64(cap(pq.heap)) * int64(unsafe.Sizeof(n))
+	for _, node := range pq.heap {
+		if prioritySize != nil {
+			total += prioritySize(node.p)
+		}
+		if valueSize != nil {
+			total += valueSize(node.v)
+		}
 	}
+	return total
 }
@@ -1,6 +1,9 @@
 package priority_queue
 
 import (
+	"errors"
+	"fmt"
+	"math"
 	"testing"
 
 	"github.com/davidpogosian/ds/comparators"
@@ -12,6 +15,40 @@ func TestNewEmpty(t *testing.T) {
 	testutils.Assert(t, "pq.Size()", 0, pq.Size())
 }
 
+func TestNewStable(t *testing.T) {
+	t.Run("Min", func(t *testing.T) {
+		pq := NewStable[int, string](comparators.ComparatorInt, true)
+		values := []string{"a", "b", "c", "d", "e", "f", "g", "h"}
+		for i, v := range values {
+			pq.Enqueue(i % 2, v)
+		}
+		var extracted []string
+		for !pq.IsEmpty() {
+			_, v, _ := pq.ExtractTop()
+			extracted = append(extracted, v)
+		}
+		testutils.AssertSlices(t, []string{"a", "c", "e", "g", "b", "d", "f", "h"}, extracted)
+	})
+
+	t.Run("Max", func(t *testing.T) {
+		pq := NewStable[int, int](comparators.ComparatorInt, false)
+		for i := 0; i < 100; i++ {
+			pq.Enqueue(i % 3, i)
+		}
+		c := pq.Copy()
+		c.Enqueue(2, 100)
+		previous := -1
+		for i := 0; i < 34; i++ {
+			p, v, _ := c.ExtractTop()
+			testutils.Assert(t, "p", 2, p)
+			if v <= previous {
+				t.Fatalf("Expected values of equal priority in insertion order, got %d after %d.", v, previous)
+			}
+			previous = v
+		}
+	})
+}
+
 func TestEnqueue(t *testing.T) {
 	t.Run("Sequential", func(t *testing.T) {
 		pq := NewEmpty[int, string](comparators.ComparatorInt, false)
@@ -144,4 +181,548 @@ func TestCopy(t *testing.T) {
 	testutils.Assert(t, "pq2.Size()", 1, pq2.Size())
 	pq1.Enqueue(3, "Awso Stwing")
 	testutils.Assert(t, "pq2.Size()", 1, pq2.Size())
+
+	t.Run("CopyOnWrite", func(t *testing.T) {
+		pq1 := NewEmpty[int, string](comparators.ComparatorInt, true)
+		for i := 1; i <= 4; i++ {
+			pq1.Enqueue(i, "")
+		}
+		pq2 := pq1.Copy()
+		pq3 := pq2.Copy()
+		pq2.ExtractTop()
+		pq2.Enqueue(0, "")
+		pq3.Enqueue(-1, "")
+		extractAll := func(pq *PriorityQueue[int, string]) []int {
+			var priorities []int
+			for !pq.IsEmpty() {
+				p, _, _ := pq.ExtractTop()
+				priorities = append(priorities, p)
+			}
+			return priorities
+		}
+		testutils.AssertSlices(t, []int{1, 2, 3, 4}, extractAll(pq1))
+		testutils.AssertSlices(t, []int{0, 2, 3, 4}, extractAll(pq2))
+		testutils.AssertSlices(t, []int{-1, 1, 2, 3, 4}, extractAll(pq3))
+	})
+
+	t.Run("Concurrent", func(t *testing.T) {
+		pq := NewEmpty[int, string](comparators.ComparatorInt, true)
+		testutils.ConcurrentOperations(t, 10, 100, func() error {
+			pq.Enqueue(1, "")
+			c := pq.Copy()
+			c.Enqueue(0, "")
+			if p, _, _ := c.ExtractTop(); p != 0 {
+				return fmt.Errorf("Expected the copy to extract 0, instead got: %d", p)
+			}
+			return nil
+		})
+		testutils.Assert(t, "pq.Size()", 1000, pq.Size())
+	})
+}
+
+func TestCopyCompact(t *testing.T) {
+	pq := NewEmpty[int, string](comparators.ComparatorInt, true)
+	for i := 0; i < 100; i++ {
+		pq.Enqueue(i, "")
+	}
+	for i := 0; i < 98; i++ {
+		pq.ExtractTop()
+	}
+	c := pq.CopyCompact()
+	testutils.Assert(t, "c.Size()", 2, c.Size())
+	if c.MemUsage() >= pq.MemUsage() {
+		t.Fatal("Expected the compact copy to use less memory.")
+	}
+}
+
+func TestNewFromSlice(t *testing.T) {
+	t.Run("Empty", func(t *testing.T) {
+		pq := NewFromSlice[int, string](nil, comparators.ComparatorInt, true)
+		testutils.Assert(t, "pq.Size()", 0, pq.Size())
+		pq.Enqueue(1, "one")
+		testutils.Assert(t, "pq.Size()", 1, pq.Size())
+	})
+
+	t.Run("Max", func(t *testing.T) {
+		nodes := []Node[int, string]{
+			NewNode(3, "three"),
+			NewNode(1, "one"),
+			NewNode(5, "five"),
+			NewNode(2, "two"),
+			NewNode(4, "four"),
+		}
+		pq := NewFromSlice(nodes, comparators.ComparatorInt, false)
+		testutils.Assert(t, "pq.Size()", 5, pq.Size())
+		for _, expected := range []int{5, 4, 3, 2, 1} {
+			p, _, err := pq.ExtractTop()
+			if err != nil {
+				t.Fatal(err)
+			}
+			testutils.Assert(t, "p", expected, p)
+		}
+	})
+
+	t.Run("ModifySlice", func(t *testing.T) {
+		nodes := []Node[int, string]{NewNode(1, "one"), NewNode(2, "two")}
+		pq := NewFromSlice(nodes, comparators.ComparatorInt, true)
+		nodes[0] = NewNode(99, "ninety-nine")
+		p, v, err := pq.Peek()
+		if err != nil {
+			t.Fatal(err)
+		}
+		testutils.Assert(t, "p", 1, p)
+		testutils.Assert(t, "v", "one", v)
+	})
+}
+
+func TestNewFromSlices(t *testing.T) {
+	t.Run("Min", func(t *testing.T) {
+		pq, err := NewFromSlices([]int{4, 2, 6, 1}, []string{"d", "b", "f", "a"}, comparators.ComparatorInt, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, expected := range []string{"a", "b", "d", "f"} {
+			_, v, err := pq.ExtractTop()
+			if err != nil {
+				t.Fatal(err)
+			}
+			testutils.Assert(t, "v", expected, v)
+		}
+	})
+
+	t.Run("MismatchedLengths", func(t *testing.T) {
+		_, err := NewFromSlices([]int{1, 2}, []string{"a"}, comparators.ComparatorInt, true)
+		if err == nil {
+			t.Fatal("Created a PriorityQueue from slices of different lengths.")
+		}
+	})
+}
+
+func TestMemUsage(t *testing.T) {
+	pq := NewEmpty[int, string](comparators.ComparatorInt, true)
+	empty := pq.MemUsage()
+	pq.Enqueue(1, "one")
+	if pq.MemUsage() <= empty {
+		t.Fatal("Expected MemUsage to grow after enqueueing.")
+	}
+	extra := pq.MemUsageFunc(func(int) int64 { return 1 }, func(v string) int64 { return int64(len(v)) }) - pq.MemUsage()
+	testutils.Assert(t, "extra", int64(4), extra)
+}
+
+func TestSetPriorityValidator(t *testing.T) {
+	pq := NewEmpty[float32, string](comparators.ComparatorFloat32, true)
+	pq.SetPriorityValidator(comparators.ValidatorFloat32)
+	nan := float32(math.NaN())
+	err := pq.Enqueue(nan, "nan")
+	if !errors.Is(err, comparators.ErrIncomparable) {
+		t.Fatalf("Expected ErrIncomparable, instead got: %v", err)
+	}
+	testutils.Assert(t, "pq.Size()", 0, pq.Size())
+	if err := pq.Enqueue(1, "one"); err != nil {
+		t.Fatal(err)
+	}
+	testutils.Assert(t, "pq.Size()", 1, pq.Size())
+}
+
+func TestRemoveValue(t *testing.T) {
+	eq := func(a, b string) bool { return a == b }
+
+	t.Run("Sequential", func(t *testing.T) {
+		pq := NewEmpty[int, string](comparators.ComparatorInt, true)
+		for i, v := range []string{"a", "b", "c", "d", "e", "f"} {
+			pq.Enqueue(i, v)
+		}
+		testutils.Assert(t, "pq.RemoveValue(\"c\")", true, pq.RemoveValue("c", eq))
+		testutils.Assert(t, "pq.RemoveValue(\"x\")", false, pq.RemoveValue("x", eq))
+		for _, expected := range []string{"a", "b", "d", "e", "f"} {
+			_, v, err := pq.ExtractTop()
+			if err != nil {
+				t.Fatal(err)
+			}
+			testutils.Assert(t, "v", expected, v)
+		}
+	})
+
+	t.Run("Concurrent", func(t *testing.T) {
+		pq := NewEmpty[int, string](comparators.ComparatorInt, true)
+		for i := 0; i < 1000; i++ {
+			pq.Enqueue(i, "x")
+		}
+		testutils.ConcurrentOperations(t, 10, 100, func() error {
+			if !pq.RemoveValue("x", eq) {
+				return errors.New("Expected to find a value to remove.")
+			}
+			return nil
+		})
+		testutils.Assert(t, "pq.Size()", 0, pq.Size())
+	})
+}
+
+func TestReplaceValue(t *testing.T) {
+	eq := func(a, b string) bool { return a == b }
+	pq := NewEmpty[int, string](comparators.ComparatorInt, true)
+	pq.Enqueue(2, "b")
+	pq.Enqueue(1, "a")
+	testutils.Assert(t, "pq.ReplaceValue(\"a\", \"z\")", true, pq.ReplaceValue("a", "z", eq))
+	testutils.Assert(t, "pq.ReplaceValue(\"a\", \"z\")", false, pq.ReplaceValue("a", "z", eq))
+	p, v, err := pq.Peek()
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutils.Assert(t, "p", 1, p)
+	testutils.Assert(t, "v", "z", v)
+}
+
+func TestUpdatePriority(t *testing.T) {
+	t.Run("NoValueComparator", func(t *testing.T) {
+		pq := NewEmpty[int, string](comparators.ComparatorInt, true)
+		pq.Enqueue(1, "a")
+		if err := pq.UpdatePriority("a", 2); err == nil {
+			t.Fatal("Updated a priority without a value comparator.")
+		}
+	})
+
+	t.Run("Sequential", func(t *testing.T) {
+		pq := NewEmpty[int, string](comparators.ComparatorInt, true)
+		pq.SetValueComparator(comparators.ComparatorString)
+		for i, v := range []string{"a", "b", "c", "d", "e"} {
+			pq.Enqueue(i, v)
+		}
+		if err := pq.UpdatePriority("a", 10); err != nil {
+			t.Fatal(err)
+		}
+		if err := pq.UpdatePriority("e", -1); err != nil {
+			t.Fatal(err)
+		}
+		if err := pq.UpdatePriority("x", 0); err == nil {
+			t.Fatal("Updated the priority of a missing value.")
+		}
+		var extracted []string
+		for !pq.IsEmpty() {
+			_, v, _ := pq.ExtractTop()
+			extracted = append(extracted, v)
+		}
+		testutils.AssertSlices(t, []string{"e", "b", "c", "d", "a"}, extracted)
+	})
+
+	t.Run("Validator", func(t *testing.T) {
+		pq := NewEmpty[float64, string](comparators.ComparatorFloat64, true)
+		pq.SetValueComparator(comparators.ComparatorString)
+		pq.SetPriorityValidator(comparators.ValidatorFloat64)
+		pq.Enqueue(1, "a")
+		err := pq.UpdatePriority("a", math.NaN())
+		if !errors.Is(err, comparators.ErrIncomparable) {
+			t.Fatalf("Expected ErrIncomparable, instead got: %v", err)
+		}
+	})
+}
+
+func TestRemove(t *testing.T) {
+	t.Run("Sequential", func(t *testing.T) {
+		pq := NewEmpty[int, string](comparators.ComparatorInt, false)
+		pq.SetValueComparator(comparators.ComparatorString)
+		pq.Enqueue(1, "a")
+		pq.Enqueue(2, "b")
+		pq.Enqueue(3, "c")
+		if err := pq.Remove("c"); err != nil {
+			t.Fatal(err)
+		}
+		if err := pq.Remove("c"); err == nil {
+			t.Fatal("Removed a missing value.")
+		}
+		p, v, _ := pq.Peek()
+		testutils.Assert(t, "p", 2, p)
+		testutils.Assert(t, "v", "b", v)
+	})
+
+	t.Run("Concurrent", func(t *testing.T) {
+		pq := NewEmpty[int, int](comparators.ComparatorInt, true)
+		pq.SetValueComparator(comparators.ComparatorInt)
+		for i := 0; i < 1000; i++ {
+			pq.Enqueue(i % 7, i % 10)
+		}
+		testutils.ConcurrentOperations(t, 10, 100, func() error {
+			for v := 0; v < 10; v++ {
+				if pq.Remove(v) == nil {
+					return nil
+				}
+			}
+			return errors.New("Expected to find a value to remove.")
+		})
+		testutils.Assert(t, "pq.Size()", 0, pq.Size())
+	})
+}
+
+// priorities returns the priorities of the nodes in order.
+func priorities[P, V any](nodes []Node[P, V]) []P {
+	result := make([]P, len(nodes))
+	for i, node := range nodes {
+		result[i] = node.Priority()
+	}
+	return result
+}
+
+func TestTryPeekAndExtractTop(t *testing.T) {
+	pq := NewEmpty[int, string](comparators.ComparatorInt, true)
+	if _, _, ok := pq.TryPeek(); ok {
+		t.Fatal("Peeked an empty PriorityQueue.")
+	}
+	if _, _, ok := pq.TryExtractTop(); ok {
+		t.Fatal("Extracted top of an empty PriorityQueue.")
+	}
+	pq.Enqueue(2, "two")
+	pq.Enqueue(1, "one")
+	p, v, ok := pq.TryPeek()
+	testutils.Assert(t, "ok", true, ok)
+	testutils.Assert(t, "p", 1, p)
+	testutils.Assert(t, "v", "one", v)
+	p, v, ok = pq.TryExtractTop()
+	testutils.Assert(t, "ok", true, ok)
+	testutils.Assert(t, "p", 1, p)
+	testutils.Assert(t, "v", "one", v)
+	testutils.Assert(t, "pq.Size()", 1, pq.Size())
+}
+
+func TestExtractTopN(t *testing.T) {
+	t.Run("Sequential", func(t *testing.T) {
+		pq := NewEmpty[int, string](comparators.ComparatorInt, false)
+		for _, p := range []int{4, 9, 1, 7, 3} {
+			pq.Enqueue(p, "")
+		}
+		nodes, err := pq.ExtractTopN(3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		testutils.AssertSlices(t, []int{9, 7, 4}, priorities(nodes))
+		testutils.Assert(t, "pq.Size()", 2, pq.Size())
+		if _, err := pq.ExtractTopN(3); err == nil {
+			t.Fatal("Extracted 3 nodes from a PriorityQueue of size 2.")
+		}
+		testutils.Assert(t, "pq.Size()", 2, pq.Size())
+	})
+
+	t.Run("Concurrent", func(t *testing.T) {
+		pq := NewEmpty[int, string](comparators.ComparatorInt, true)
+		for i := 0; i < 2000; i++ {
+			pq.Enqueue(i, "")
+		}
+		testutils.ConcurrentOperations(t, 10, 100, func() error {
+			_, err := pq.ExtractTopN(2)
+			return err
+		})
+		testutils.Assert(t, "pq.Size()", 0, pq.Size())
+	})
+}
+
+func TestPeekN(t *testing.T) {
+	pq := NewEmpty[int, string](comparators.ComparatorInt, true)
+	for _, p := range []int{5, 3, 8, 1, 9, 2, 7, 4, 6} {
+		pq.Enqueue(p, "")
+	}
+	nodes, err := pq.PeekN(5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutils.AssertSlices(t, []int{1, 2, 3, 4, 5}, priorities(nodes))
+	testutils.Assert(t, "pq.Size()", 9, pq.Size())
+	nodes, err = pq.PeekN(9)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutils.AssertSlices(t, []int{1, 2, 3, 4, 5, 6, 7, 8, 9}, priorities(nodes))
+	if _, err := pq.PeekN(10); err == nil {
+		t.Fatal("Peeked 10 nodes of a PriorityQueue of size 9.")
+	}
+}
+
+func TestDrainSorted(t *testing.T) {
+	pq := NewStable[int, string](comparators.ComparatorInt, true)
+	pq.Enqueue(2, "b")
+	pq.Enqueue(1, "a")
+	pq.Enqueue(2, "c")
+	nodes := pq.DrainSorted()
+	testutils.AssertSlices(t, []int{1, 2, 2}, priorities(nodes))
+	testutils.Assert(t, "nodes[2].Value()", "c", nodes[2].Value())
+	testutils.Assert(t, "pq.Size()", 0, pq.Size())
+	testutils.Assert(t, "len(pq.DrainSorted())", 0, len(pq.DrainSorted()))
+}
+
+func TestToSlice(t *testing.T) {
+	pq := NewEmpty[int, string](comparators.ComparatorInt, false)
+	testutils.Assert(t, "len(pq.ToSlice())", 0, len(pq.ToSlice()))
+	for _, p := range []int{5, 3, 8, 1} {
+		pq.Enqueue(p, fmt.Sprint(p))
+	}
+	entries := pq.ToSlice()
+	testutils.Assert(t, "len(entries)", 4, len(entries))
+	testutils.Assert(t, "entries[0]", Entry[int, string]{8, "8"}, entries[0])
+	testutils.Assert(t, "pq.Size()", 4, pq.Size())
+}
+
+func TestToSortedSlice(t *testing.T) {
+	pq := NewEmpty[int, string](comparators.ComparatorInt, false)
+	for _, p := range []int{5, 3, 8, 1, 9, 2} {
+		pq.Enqueue(p, fmt.Sprint(p))
+	}
+	entries := pq.ToSortedSlice()
+	expected := []Entry[int, string]{{9, "9"}, {8, "8"}, {5, "5"}, {3, "3"}, {2, "2"}, {1, "1"}}
+	testutils.AssertSlices(t, expected, entries)
+	testutils.Assert(t, "pq.Size()", 6, pq.Size())
+}
+
+func TestAll(t *testing.T) {
+	pq := NewEmpty[int, string](comparators.ComparatorInt, true)
+	for _, p := range []int{5, 3, 8, 1} {
+		pq.Enqueue(p, fmt.Sprint(p))
+	}
+	t.Run("Sorted", func(t *testing.T) {
+		var priorities []int
+		var values []string
+		for p, v := range pq.All() {
+			priorities = append(priorities, p)
+			values = append(values, v)
+		}
+		testutils.AssertSlices(t, []int{1, 3, 5, 8}, priorities)
+		testutils.AssertSlices(t, []string{"1", "3", "5", "8"}, values)
+	})
+	t.Run("Break", func(t *testing.T) {
+		var priorities []int
+		for p := range pq.All() {
+			if len(priorities) == 2 {
+				break
+			}
+			priorities = append(priorities, p)
+		}
+		testutils.AssertSlices(t, []int{1, 3}, priorities)
+	})
+	t.Run("Modify", func(t *testing.T) {
+		var priorities []int
+		for p := range pq.All() {
+			priorities = append(priorities, p)
+			pq.ExtractTop()
+			pq.Enqueue(0, "0")
+		}
+		testutils.AssertSlices(t, []int{1, 3, 5, 8}, priorities)
+		testutils.Assert(t, "pq.Size()", 4, pq.Size())
+		p, _, _ := pq.Peek()
+		testutils.Assert(t, "p", 0, p)
+	})
+}
+
+func TestString(t *testing.T) {
+	pq := NewEmpty[int, string](comparators.ComparatorInt, true)
+	testutils.Assert(t, "pq.String()", "[]", pq.String())
+	for _, p := range []int{3, 1, 2} {
+		pq.Enqueue(p, fmt.Sprint(p))
+	}
+	testutils.Assert(t, "pq.String()", "[{1 1} {2 2} {3 3}]", pq.String())
+	testutils.Assert(t, "pq.StringN(2)", "[{1 1} {2 2} … (+1 more)]", pq.StringN(2))
+	pq.SetStringLimit(1)
+	testutils.Assert(t, "pq.String()", "[{1 1} … (+2 more)]", pq.String())
+	testutils.Assert(t, "pq.Copy().String()", "[{1 1} … (+2 more)]", pq.Copy().String())
+}
+
+func TestRecovering(t *testing.T) {
+	unlucky := func(a, b int) int {
+		if a == 13 || b == 13 {
+			panic("unlucky")
+		}
+		return comparators.ComparatorInt(a, b)
+	}
+	entries := func(pq *PriorityQueue[int, string]) []int {
+		ps := []int{}
+		for _, entry := range pq.ToSortedSlice() {
+			ps = append(ps, entry.Priority)
+		}
+		return ps
+	}
+	pq := NewEmpty[int, string](comparators.Recovering(unlucky), true)
+	pq.SetValueComparator(comparators.ComparatorString)
+	for _, p := range []int{1, 20, 30, 40, 50, 60} {
+		pq.Enqueue(p, fmt.Sprint(p))
+	}
+	// 13 would be sifted up past 30 and 20 before the comparison with 1 panics.
+	if err := pq.Enqueue(13, "13"); !errors.Is(err, comparators.ErrPanicked) {
+		t.Fatalf("Expected ErrPanicked, got %v.", err)
+	}
+	// The lock was released and no node was lost or moved.
+	testutils.Assert(t, "pq.Size()", 6, pq.Size())
+	testutils.AssertSlices(t, []int{1, 20, 30, 40, 50, 60}, entries(pq))
+	p, _, err := pq.Peek()
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutils.Assert(t, "p", 1, p)
+	if err := pq.UpdatePriority("40", 13); !errors.Is(err, comparators.ErrPanicked) {
+		t.Fatalf("Expected ErrPanicked, got %v.", err)
+	}
+	testutils.Assert(t, "pq.Size()", 6, pq.Size())
+	testutils.AssertSlices(t, []int{1, 20, 30, 40, 50, 60}, entries(pq))
+	nodes, err := pq.ExtractTopN(6)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutils.AssertSlices(t, []int{1, 20, 30, 40, 50, 60}, priorities(nodes))
+	testutils.Assert(t, "pq.Size()", 0, pq.Size())
+}
+
+func TestMerge(t *testing.T) {
+	t.Run("Sequential", func(t *testing.T) {
+		pq1 := NewEmpty[int, string](comparators.ComparatorInt, true)
+		pq2 := NewEmpty[int, string](comparators.ComparatorInt, true)
+		for _, p := range []int{5, 1, 9} {
+			pq1.Enqueue(p, fmt.Sprint(p))
+		}
+		for _, p := range []int{4, 8, 2, 6} {
+			pq2.Enqueue(p, fmt.Sprint(p))
+		}
+		pq1.Merge(pq2)
+		testutils.Assert(t, "pq1.Size()", 7, pq1.Size())
+		testutils.Assert(t, "pq2.Size()", 4, pq2.Size())
+		testutils.AssertSlices(t, []int{1, 2, 4, 5, 6, 8, 9}, priorities(pq1.DrainSorted()))
+		testutils.AssertSlices(t, []int{2, 4, 6, 8}, priorities(pq2.DrainSorted()))
+	})
+	t.Run("Self", func(t *testing.T) {
+		pq := NewEmpty[int, string](comparators.ComparatorInt, false)
+		pq.Enqueue(1, "a")
+		pq.Enqueue(2, "b")
+		pq.Merge(pq)
+		testutils.AssertSlices(t, []int{2, 2, 1, 1}, priorities(pq.DrainSorted()))
+	})
+	t.Run("Stable", func(t *testing.T) {
+		pq1 := NewStable[int, string](comparators.ComparatorInt, true)
+		pq2 := NewStable[int, string](comparators.ComparatorInt, true)
+		pq1.Enqueue(1, "a")
+		pq2.Enqueue(1, "b")
+		pq2.Enqueue(1, "c")
+		pq1.Merge(pq2)
+		pq1.Enqueue(1, "d")
+		values := []string{}
+		for _, node := range pq1.DrainSorted() {
+			values = append(values, node.Value())
+		}
+		testutils.AssertSlices(t, []string{"a", "b", "c", "d"}, values)
+	})
+	t.Run("Copy", func(t *testing.T) {
+		pq1 := NewEmpty[int, string](comparators.ComparatorInt, true)
+		pq1.Enqueue(3, "3")
+		copied := pq1.Copy()
+		pq2 := NewEmpty[int, string](comparators.ComparatorInt, true)
+		pq2.Enqueue(1, "1")
+		pq1.Merge(pq2)
+		testutils.AssertSlices(t, []int{1, 3}, priorities(pq1.DrainSorted()))
+		testutils.AssertSlices(t, []int{3}, priorities(copied.DrainSorted()))
+	})
+	t.Run("Concurrent", func(t *testing.T) {
+		pq1 := NewEmpty[int, string](comparators.ComparatorInt, true)
+		pq2 := NewEmpty[int, string](comparators.ComparatorInt, true)
+		pq1.Enqueue(1, "1")
+		pq2.Enqueue(2, "2")
+		// Each Merge may double a queue, so only a few rounds are run.
+		testutils.ConcurrentOperations(t, 2, 3, func() error {
+			pq1.Merge(pq2)
+			pq2.Merge(pq1)
+			return nil
+		})
+	})
 }
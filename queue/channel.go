@@ -0,0 +1,61 @@
+package queue
+
+import "context"
+
+// ToChannel starts a goroutine that dequeues items from the Queue and sends them on the
+// returned unbuffered channel, waiting for new items whenever the Queue is empty.
+// The channel is closed once ctx is done. An item that was dequeued but could not be
+// sent before ctx was done is put back at the front of the Queue, so no item is lost.
+func (queue *Queue[T]) ToChannel(ctx context.Context) <-chan T {
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		for {
+			item, err := queue.DequeueWait(ctx)
+			if err != nil {
+				return
+			}
+			select {
+			case out <- item:
+			case <-ctx.Done():
+				queue.requeue(item)
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// requeue puts an item that was just dequeued back at the front of the Queue.
+// The item had a slot, so it is put back even if the Queue has since been filled
+// up to its bound; the Queue is then over its bound until an item is dequeued.
+func (queue *Queue[T]) requeue(item T) {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+	if queue.size == len(queue.items) {
+		capacity := max(len(queue.items) * 2, defaultCapacity)
+		if queue.bound > 0 {
+			capacity = min(capacity, queue.bound)
+		}
+		queue.resize(max(capacity, queue.size + 1))
+	}
+	queue.front = (queue.front - 1 + len(queue.items)) % len(queue.items)
+	queue.items[queue.front] = item
+	queue.size++
+	broadcast(&queue.enqueued)
+}
+
+// FromChannel starts a goroutine that receives items from ch and enqueues them into the Queue
+// until ch is closed. If the Queue is bounded and full, the goroutine waits for room,
+// so a slow consumer applies backpressure to the sender.
+// The returned channel is closed once ch is closed and all of its items have been enqueued.
+func (queue *Queue[T]) FromChannel(ch <-chan T) <-chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for item := range ch {
+			queue.EnqueueWait(context.Background(), item)
+		}
+	}()
+	return done
+}
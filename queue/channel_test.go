@@ -0,0 +1,92 @@
+package queue
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/davidpogosian/ds/testutils"
+)
+
+func TestToChannel(t *testing.T) {
+	t.Run("Drain", func(t *testing.T) {
+		q := New[int]()
+		q.EnqueueAll([]int{1, 2, 3})
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		out := q.ToChannel(ctx)
+		var received []int
+		for item := range out {
+			received = append(received, item)
+			if len(received) == 3 {
+				cancel()
+			}
+		}
+		testutils.AssertSlices(t, []int{1, 2, 3}, received)
+	})
+
+	t.Run("Waits", func(t *testing.T) {
+		q := New[int]()
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		out := q.ToChannel(ctx)
+		q.Enqueue(7)
+		testutils.Assert(t, "<-out", 7, <-out)
+	})
+
+	t.Run("CancelWhileSending", func(t *testing.T) {
+		q, _ := NewBounded[int](3)
+		q.EnqueueAll([]int{1, 2, 3})
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		out := q.ToChannel(ctx)
+		received := []int{<-out}
+		// Wait for 2 to be dequeued, then fill its slot while it is being sent.
+		for q.Size() != 1 {
+			time.Sleep(time.Millisecond)
+		}
+		q.EnqueueAll([]int{4, 5})
+		cancel()
+		for item := range out {
+			received = append(received, item)
+		}
+		// Whether or not 2 was sent before the cancellation, nothing is lost or reordered.
+		testutils.AssertSlices(t, []int{1, 2, 3, 4, 5}, append(received, q.ToSlice()...))
+	})
+}
+
+func TestFromChannel(t *testing.T) {
+	t.Run("Unbounded", func(t *testing.T) {
+		q := New[int]()
+		ch := make(chan int)
+		done := q.FromChannel(ch)
+		for i := 0; i < 100; i++ {
+			ch <- i
+		}
+		close(ch)
+		<-done
+		testutils.Assert(t, "q.Size()", 100, q.Size())
+	})
+
+	t.Run("Pipeline", func(t *testing.T) {
+		q, err := NewBounded[int](2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ch := make(chan int)
+		done := q.FromChannel(ch)
+		go func() {
+			for i := 0; i < 100; i++ {
+				ch <- i
+			}
+			close(ch)
+		}()
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		out := q.ToChannel(ctx)
+		for i := 0; i < 100; i++ {
+			testutils.Assert(t, "<-out", i, <-out)
+		}
+		<-done
+	})
+}
@@ -0,0 +1,131 @@
+package queue
+
+import (
+	"fmt"
+	"iter"
+	"sync"
+)
+
+// DedupQueue is a struct representing a queue that holds at most one pending item per key,
+// e.g. a work queue whose consumers should not process the same job twice while it waits.
+// Once an item is dequeued, an item with the same key may be enqueued again, so duplicates
+// are only rejected within the window of pending items.
+// It contains a Queue of the pending items, a function returning the key of an item,
+// the set of pending keys, and a mutex for thread-safety.
+type DedupQueue[T any, K comparable] struct {
+	items *Queue[T]
+	key func(T) K
+	pending map[K]struct{}
+	mutex sync.Mutex
+}
+
+// NewDedupQueue creates a new empty DedupQueue whose items are considered equal
+// when key returns the same key for them, and returns a pointer to it.
+func NewDedupQueue[T any, K comparable](key func(T) K) *DedupQueue[T, K] {
+	return &DedupQueue[T, K]{
+		items: New[T](),
+		key: key,
+		pending: make(map[K]struct{}),
+	}
+}
+
+// NewDedupQueueOf creates a new empty DedupQueue of comparable items, which are
+// their own keys, and returns a pointer to it.
+func NewDedupQueueOf[T comparable]() *DedupQueue[T, T] {
+	return NewDedupQueue(func(item T) T { return item })
+}
+
+// EnqueueIfAbsent adds an item to the rear of the DedupQueue, unless an item with the same key
+// is already pending, in O(1). It returns a bool indicating whether the item was added.
+func (dq *DedupQueue[T, K]) EnqueueIfAbsent(newItem T) bool {
+	dq.mutex.Lock()
+	defer dq.mutex.Unlock()
+	k := dq.key(newItem)
+	if _, ok := dq.pending[k]; ok {
+		return false
+	}
+	dq.items.Enqueue(newItem)
+	dq.pending[k] = struct{}{}
+	return true
+}
+
+// Dequeue removes and returns the item at the front of the DedupQueue,
+// after which an item with the same key may be enqueued again.
+// It returns an error if the DedupQueue is empty.
+func (dq *DedupQueue[T, K]) Dequeue() (T, error) {
+	dq.mutex.Lock()
+	defer dq.mutex.Unlock()
+	item, err := dq.items.Dequeue()
+	if err != nil {
+		return item, fmt.Errorf("Cannot dequeue from an empty DedupQueue.")
+	}
+	delete(dq.pending, dq.key(item))
+	return item, nil
+}
+
+// Peek returns the item at the front of the DedupQueue.
+// It returns an error if the DedupQueue is empty.
+func (dq *DedupQueue[T, K]) Peek() (T, error) {
+	dq.mutex.Lock()
+	defer dq.mutex.Unlock()
+	item, err := dq.items.Peek()
+	if err != nil {
+		return item, fmt.Errorf("Cannot peek an empty DedupQueue.")
+	}
+	return item, nil
+}
+
+// Contains returns a bool indicating whether an item with the same key as item is pending, in O(1).
+func (dq *DedupQueue[T, K]) Contains(item T) bool {
+	dq.mutex.Lock()
+	defer dq.mutex.Unlock()
+	_, ok := dq.pending[dq.key(item)]
+	return ok
+}
+
+// Size returns the number of pending items in the DedupQueue.
+func (dq *DedupQueue[T, K]) Size() int {
+	dq.mutex.Lock()
+	defer dq.mutex.Unlock()
+	return len(dq.pending)
+}
+
+// IsEmpty returns a bool indicating whether or not the DedupQueue is empty.
+func (dq *DedupQueue[T, K]) IsEmpty() bool {
+	return dq.Size() == 0
+}
+
+// Clear removes all items from the DedupQueue.
+func (dq *DedupQueue[T, K]) Clear() {
+	dq.mutex.Lock()
+	defer dq.mutex.Unlock()
+	dq.items.Clear()
+	clear(dq.pending)
+}
+
+// ToSlice returns the pending items of the DedupQueue from front to rear as a slice.
+func (dq *DedupQueue[T, K]) ToSlice() []T {
+	dq.mutex.Lock()
+	defer dq.mutex.Unlock()
+	return dq.items.ToSlice()
+}
+
+// All returns an iterator over the pending items of the DedupQueue from front to rear.
+// It iterates over a snapshot taken when iteration starts, so the loop body
+// may call methods on the DedupQueue (including ones that modify it).
+func (dq *DedupQueue[T, K]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, item := range dq.ToSlice() {
+			if !yield(item) {
+				return
+			}
+		}
+	}
+}
+
+// String returns the string representation of the DedupQueue.
+func (dq *DedupQueue[T, K]) String() string {
+	dq.mutex.Lock()
+	defer dq.mutex.Unlock()
+	return dq.items.String()
+}
@@ -0,0 +1,77 @@
+package queue
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/davidpogosian/ds/testutils"
+)
+
+type job struct {
+	id int
+	payload string
+}
+
+func TestDedupQueueEnqueueIfAbsent(t *testing.T) {
+	t.Run("Sequential", func(t *testing.T) {
+		dq := NewDedupQueue(func(j job) int { return j.id })
+		testutils.Assert(t, "dq.EnqueueIfAbsent(job{1, \"a\"})", true, dq.EnqueueIfAbsent(job{1, "a"}))
+		testutils.Assert(t, "dq.EnqueueIfAbsent(job{2, \"b\"})", true, dq.EnqueueIfAbsent(job{2, "b"}))
+		testutils.Assert(t, "dq.EnqueueIfAbsent(job{1, \"c\"})", false, dq.EnqueueIfAbsent(job{1, "c"}))
+		testutils.Assert(t, "dq.Size()", 2, dq.Size())
+		testutils.AssertSlices(t, []job{{1, "a"}, {2, "b"}}, dq.ToSlice())
+		testutils.Assert(t, "dq.Contains(job{id: 1})", true, dq.Contains(job{id: 1}))
+		testutils.Assert(t, "dq.Contains(job{id: 3})", false, dq.Contains(job{id: 3}))
+	})
+	t.Run("Concurrent", func(t *testing.T) {
+		dq := NewDedupQueueOf[int]()
+		testutils.ConcurrentOperations(t, 10, 100, func() error {
+			for i := 0; i < 10; i++ {
+				dq.EnqueueIfAbsent(i)
+			}
+			return nil
+		})
+		testutils.Assert(t, "dq.Size()", 10, dq.Size())
+	})
+}
+
+func TestDedupQueueDequeue(t *testing.T) {
+	dq := NewDedupQueueOf[string]()
+	if _, err := dq.Dequeue(); err == nil {
+		t.Fatal("Dequeued from an empty DedupQueue.")
+	}
+	dq.EnqueueIfAbsent("a")
+	dq.EnqueueIfAbsent("b")
+	item, err := dq.Dequeue()
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutils.Assert(t, "item", "a", item)
+	// "a" is no longer pending, so it may be enqueued again.
+	testutils.Assert(t, "dq.EnqueueIfAbsent(\"a\")", true, dq.EnqueueIfAbsent("a"))
+	testutils.Assert(t, "dq.EnqueueIfAbsent(\"b\")", false, dq.EnqueueIfAbsent("b"))
+	item, err = dq.Peek()
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutils.Assert(t, "item", "b", item)
+	testutils.Assert(t, "dq.String()", "[b a]", dq.String())
+}
+
+func TestDedupQueueClear(t *testing.T) {
+	dq := NewDedupQueueOf[int]()
+	for i := 0; i < 5; i++ {
+		dq.EnqueueIfAbsent(i)
+	}
+	dq.Clear()
+	testutils.Assert(t, "dq.IsEmpty()", true, dq.IsEmpty())
+	testutils.Assert(t, "dq.EnqueueIfAbsent(1)", true, dq.EnqueueIfAbsent(1))
+	if _, err := dq.Peek(); err != nil {
+		t.Fatal(err)
+	}
+	items := []string{}
+	for item := range dq.All() {
+		items = append(items, fmt.Sprint(item))
+	}
+	testutils.AssertSlices(t, []string{"1"}, items)
+}
@@ -2,21 +2,40 @@
 package queue
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"iter"
 	"sync"
+	"unsafe"
 
 	"github.com/davidpogosian/ds/comparators"
+	"github.com/davidpogosian/ds/internal/format"
 )
 
+// defaultCapacity is the initial capacity of the circular slice of a Queue,
+// and the smallest one it grows from.
+const defaultCapacity = 4
+
+// ErrFull is returned by Enqueue when a bounded Queue already holds as many items as it can.
+var ErrFull = errors.New("Cannot enqueue into a full Queue.")
+
 // Queue is a struct representing a queue. It contains a circular slice to store items, pointers to the
-// front and the rear of the queue, a field to keep track of the size, a comparator function,
-// and a mutex for thread-safety.
+// front and the rear of the queue, a field to keep track of the size, a comparator function
+// (nil if the Queue was created with New or NewBounded), the maximum number of items
+// (0 means unbounded), a limit on the number of items printed by String
+// (0 means no limit), channels that are closed when an item is enqueued or dequeued (created
+// only while someone waits for one), and a mutex for thread-safety.
 type Queue[T any] struct {
 	items []T
 	front int
 	rear int
 	size int
 	comparator comparators.Comparator[T]
+	bound int
+	stringLimit int
+	enqueued chan struct{}
+	dequeued chan struct{}
 	mutex sync.Mutex
 }
 
@@ -25,8 +44,43 @@ type Queue[T any] struct {
 // For built-in types, the comparators package provides ready-made comparators
 // (e.g., comparators.CompareInt for int).
 // Custom types will require a user-defined comparator.
-func NewEmpty[T any](comparator comparators.Comparator[T]) *Queue[T] {
-	return &Queue[T]{items: make([]T, 4), comparator: comparator}
+// An optional capacity hint sets how many items the Queue can hold before its
+// circular slice must grow; otherwise it starts with room for 4.
+func NewEmpty[T any](comparator comparators.Comparator[T], capacityHint ...int) *Queue[T] {
+	capacity := defaultCapacity
+	if len(capacityHint) > 0 && capacityHint[0] > 0 {
+		capacity = capacityHint[0]
+	}
+	return &Queue[T]{items: make([]T, capacity), comparator: comparator}
+}
+
+// New creates a new empty Queue and returns a pointer to it, without asking for a comparator.
+// It suits plain FIFO use. Find and Contains use the comparator picked by comparators.Auto
+// (e.g. for numbers, strings, or types with a Compare method); for any other type they
+// panic, and FindFunc must be used instead.
+func New[T any]() *Queue[T] {
+	return &Queue[T]{items: make([]T, defaultCapacity), comparator: autoComparator[T]()}
+}
+
+// NewBounded creates a new empty Queue that holds at most capacity items, and returns a pointer to it.
+// Its circular slice is allocated up front and never grows beyond capacity: once the Queue is full,
+// Enqueue returns ErrFull and TryEnqueue returns false. Like New, it uses the comparator picked
+// by comparators.Auto, if any.
+// An error is returned if capacity is not positive.
+func NewBounded[T any](capacity int) (*Queue[T], error) {
+	if capacity <= 0 {
+		return nil, fmt.Errorf("Cannot create a Queue with a capacity of %d.", capacity)
+	}
+	return &Queue[T]{items: make([]T, capacity), bound: capacity, comparator: autoComparator[T]()}, nil
+}
+
+// autoComparator returns the comparator comparators.Auto picks for T, or nil if there is none.
+func autoComparator[T any]() comparators.Comparator[T] {
+	comparator, err := comparators.Auto[T]()
+	if err != nil {
+		return nil
+	}
+	return comparator
 }
 
 // NewFromSlice creates a new Queue from a slice and returns a pointer to it.
@@ -47,33 +101,155 @@ func NewFromSlice[T any](slice []T, comparator comparators.Comparator[T]) *Queue
 	}
 }
 
-// grow doubles the capacity of the Queue and copies over existing items.
-func (queue *Queue[T]) grow() {
-	newCapacity := len(queue.items) * 2
-	newItems := make([]T, newCapacity)
-	if queue.front < queue.rear {
-        copy(newItems, queue.items[queue.front:queue.rear])
-    } else {
-        copy(newItems, queue.items[queue.front:])
-        copy(newItems[len(queue.items) - queue.front:], queue.items[:queue.rear])
-    }
+// grow at least doubles the capacity of the Queue (starting from defaultCapacity if it has none),
+// so that it can hold minimum items, but at most up to its bound, and copies over existing items.
+func (queue *Queue[T]) grow(minimum int) {
+	capacity := max(len(queue.items) * 2, defaultCapacity, minimum)
+	if queue.bound > 0 {
+		capacity = min(capacity, queue.bound)
+	}
+	queue.resize(capacity)
+}
+
+// resize replaces the circular slice of the Queue with one of the given capacity,
+// which must be at least the size, and copies over existing items.
+func (queue *Queue[T]) resize(capacity int) {
+	newItems := make([]T, capacity)
+	queue.read(newItems[:queue.size])
 	queue.front = 0
-	queue.rear = queue.size
+	queue.rear = queue.size % max(capacity, 1)
 	queue.items = newItems
 }
 
+// read copies the first len(dst) items of the Queue, starting from the front, into dst,
+// using at most two copies for the two segments of the circular slice.
+func (queue *Queue[T]) read(dst []T) {
+	n := copy(dst, queue.items[queue.front:])
+	copy(dst[n:], queue.items)
+}
+
 // Enqueue adds an item to the rear of the Queue.
-func (queue *Queue[T]) Enqueue(newItem T) {
+// ErrFull is returned if the Queue is bounded and full.
+func (queue *Queue[T]) Enqueue(newItem T) error {
 	queue.mutex.Lock()
 	defer queue.mutex.Unlock()
+	if queue.isFull() {
+		return ErrFull
+	}
+	queue.enqueue(newItem)
+	return nil
+}
+
+// enqueue adds an item to the rear of the Queue, which must not be full,
+// and wakes up everyone waiting for an item.
+func (queue *Queue[T]) enqueue(newItem T) {
 	if queue.size == len(queue.items) {
-		queue.grow()
+		queue.grow(queue.size + 1)
 	}
 	queue.items[queue.rear] = newItem
 	queue.rear = (queue.rear + 1) % len(queue.items)
 	queue.size++
+	broadcast(&queue.enqueued)
+}
+
+// EnqueueAll adds the items to the rear of the Queue in order, under a single lock.
+// If the Queue is bounded and the items do not all fit, ErrFull is returned and nothing is added.
+func (queue *Queue[T]) EnqueueAll(items []T) error {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+	if len(items) == 0 {
+		return nil
+	}
+	if queue.bound > 0 && queue.size + len(items) > queue.bound {
+		return ErrFull
+	}
+	if queue.size + len(items) > len(queue.items) {
+		queue.grow(queue.size + len(items))
+	}
+	n := copy(queue.items[queue.rear:], items)
+	copy(queue.items, items[n:])
+	queue.rear = (queue.rear + len(items)) % len(queue.items)
+	queue.size += len(items)
+	broadcast(&queue.enqueued)
+	return nil
+}
+
+// EnqueueWait adds an item to the rear of the Queue. If the Queue is bounded and full,
+// EnqueueWait blocks until an item is dequeued or ctx is done, in which case ctx.Err() is returned.
+func (queue *Queue[T]) EnqueueWait(ctx context.Context, newItem T) error {
+	for {
+		queue.mutex.Lock()
+		if !queue.isFull() {
+			queue.enqueue(newItem)
+			queue.mutex.Unlock()
+			return nil
+		}
+		dequeued := subscribe(&queue.dequeued)
+		queue.mutex.Unlock()
+		select {
+		case <-dequeued:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// DequeueWait removes and returns the item at the front of the Queue. If the Queue is empty,
+// DequeueWait blocks until an item is enqueued or ctx is done, in which case ctx.Err() is returned.
+func (queue *Queue[T]) DequeueWait(ctx context.Context) (T, error) {
+	for {
+		item, enqueued, ok := queue.dequeueOrWait()
+		if ok {
+			return item, nil
+		}
+		select {
+		case <-enqueued:
+		case <-ctx.Done():
+			var zeroValue T
+			return zeroValue, ctx.Err()
+		}
+	}
+}
+
+// broadcast wakes up everyone waiting on the channel by closing it.
+// The next subscriber gets a new channel.
+func broadcast(channel *chan struct{}) {
+	if *channel != nil {
+		close(*channel)
+		*channel = nil
+	}
 }
 
+// subscribe returns a channel that is closed by the next broadcast on it.
+// The Queue must be locked from checking the condition being waited for until
+// subscribe returns, so that no broadcast is missed.
+func subscribe(channel *chan struct{}) <-chan struct{} {
+	if *channel == nil {
+		*channel = make(chan struct{})
+	}
+	return *channel
+}
+
+// TryEnqueue adds an item to the rear of the Queue and returns true,
+// or returns false if the Queue is bounded and full.
+func (queue *Queue[T]) TryEnqueue(newItem T) bool {
+	return queue.Enqueue(newItem) == nil
+}
+
+// isFull returns a bool indicating if the Queue is bounded and full.
+func (queue *Queue[T]) isFull() bool {
+	return queue.bound > 0 && queue.size >= queue.bound
+}
+
+// IsFull returns a bool indicating if the Queue is bounded and full.
+// An unbounded Queue is never full.
+func (queue *Queue[T]) IsFull() bool {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+	return queue.isFull()
+}
+
+
 // IsEmpty returns a bool indicating whether or not the Queue is empty.
 func (queue *Queue[T]) IsEmpty() bool {
 	queue.mutex.Lock()
@@ -84,29 +260,96 @@ func (queue *Queue[T]) IsEmpty() bool {
 // Dequeue removes and returns the item at the front of the Queue.
 // It returns an error if the Queue is empty.
 func (queue *Queue[T]) Dequeue() (T, error) {
+	item, ok := queue.TryDequeue()
+	if !ok {
+		return item, fmt.Errorf("Cannot dequeue from an empty Queue.")
+	}
+	return item, nil
+}
+
+// TryDequeue removes and returns the item at the front of the Queue and true,
+// or returns false if the Queue is empty. Unlike Dequeue, it never allocates an error.
+func (queue *Queue[T]) TryDequeue() (T, bool) {
 	queue.mutex.Lock()
 	defer queue.mutex.Unlock()
 	if queue.size == 0 {
 		var zeroValue T
-		return zeroValue, fmt.Errorf("Cannot dequeue from an empty Queue.")
+		return zeroValue, false
+	}
+	return queue.dequeue(), true
+}
+
+// DequeueN removes and returns the first n items of the Queue, front first, under a single lock.
+// An error is returned, and nothing is removed, if n is negative or greater than the size of the Queue.
+func (queue *Queue[T]) DequeueN(n int) ([]T, error) {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+	if n < 0 || n > queue.size {
+		return nil, fmt.Errorf("Cannot dequeue %d items from a Queue of size %d.", n, queue.size)
 	}
+	dequeued := make([]T, n)
+	if n == 0 {
+		return dequeued, nil
+	}
+	queue.read(dequeued)
+	queue.front = (queue.front + n) % len(queue.items)
+	queue.size -= n
+	broadcast(&queue.dequeued)
+	return dequeued, nil
+}
+
+// dequeue removes and returns the item at the front of the non-empty Queue,
+// and wakes up everyone waiting for room.
+func (queue *Queue[T]) dequeue() T {
 	first := queue.items[queue.front]
 	queue.front = (queue.front + 1) % len(queue.items)
 	queue.size--
-	return first, nil
+	broadcast(&queue.dequeued)
+	return first
 }
 
 // Peek returns the item at the front of the Queue.
 // It returns an error if the Queue is empty.
 func (queue *Queue[T]) Peek() (T, error) {
+	item, ok := queue.TryPeek()
+	if !ok {
+		return item, fmt.Errorf("Cannot peak an empty Queue.")
+	}
+	return item, nil
+}
+
+// TryPeek returns the item at the front of the Queue and true,
+// or returns false if the Queue is empty.
+func (queue *Queue[T]) TryPeek() (T, bool) {
 	queue.mutex.Lock()
 	defer queue.mutex.Unlock()
 	var zeroValue T
 	if queue.size == 0 {
-		return zeroValue, fmt.Errorf("Cannot peak an empty Queue.")
+		return zeroValue, false
 	}
-	first := queue.items[queue.front]
-	return first, nil
+	return queue.items[queue.front], true
+}
+
+// PeekBack returns the item at the rear of the Queue, i.e. the one enqueued last.
+// It returns an error if the Queue is empty.
+func (queue *Queue[T]) PeekBack() (T, error) {
+	item, ok := queue.TryPeekBack()
+	if !ok {
+		return item, fmt.Errorf("Cannot peek the back of an empty Queue.")
+	}
+	return item, nil
+}
+
+// TryPeekBack returns the item at the rear of the Queue and true,
+// or returns false if the Queue is empty.
+func (queue *Queue[T]) TryPeekBack() (T, bool) {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+	var zeroValue T
+	if queue.size == 0 {
+		return zeroValue, false
+	}
+	return queue.items[(queue.rear - 1 + len(queue.items)) % len(queue.items)], true
 }
 
 // Size returns the number of items in the Queue.
@@ -116,6 +359,34 @@ func (queue *Queue[T]) Size() int {
 	return queue.size
 }
 
+// Cap returns the number of items the Queue can hold before its circular slice must grow.
+func (queue *Queue[T]) Cap() int {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+	return len(queue.items)
+}
+
+// Capacity returns the number of items the Queue can hold before its circular slice must grow.
+// It is the same as Cap.
+func (queue *Queue[T]) Capacity() int {
+	return queue.Cap()
+}
+
+// Shrink reduces the circular slice of the Queue to fit its items (but no less than room for 4),
+// releasing the memory left behind after the Queue was much larger, e.g. after a burst.
+// Enqueueing afterwards grows the slice again as needed.
+func (queue *Queue[T]) Shrink() {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+	capacity := max(queue.size, defaultCapacity)
+	if queue.bound > 0 {
+		capacity = min(capacity, queue.bound)
+	}
+	if capacity < len(queue.items) {
+		queue.resize(capacity)
+	}
+}
+
 // Clear removes all items from the Queue.
 func (queue *Queue[T]) Clear() {
 	queue.mutex.Lock()
@@ -123,19 +394,38 @@ func (queue *Queue[T]) Clear() {
 	queue.front = 0
 	queue.rear = 0
 	queue.size = 0
+	broadcast(&queue.dequeued)
 }
 
 // Find returns a nonnegative int indicating the position of the item in the Queue.
 // It returns -1 if the item is not in the Queue.
+// It panics if the Queue has no comparator, since it could not tell whether the item is there;
+// use FindFunc instead.
 func (queue *Queue[T]) Find(item T) int {
+	if queue.comparator == nil {
+		panic("Cannot find an item in a Queue without a comparator; use FindFunc.")
+	}
+	return queue.FindFunc(func(other T) bool {
+		return queue.comparator(other, item) == 0
+	})
+}
+
+// Contains returns a bool indicating whether or not the item is in the Queue.
+// Like Find, it panics if the Queue has no comparator.
+func (queue *Queue[T]) Contains(item T) bool {
+	return queue.Find(item) != -1
+}
+
+// FindFunc returns a nonnegative int indicating the position of the first item
+// in the Queue for which pred returns true, or -1 if there is none.
+// It does not need a comparator.
+func (queue *Queue[T]) FindFunc(pred func(T) bool) int {
 	queue.mutex.Lock()
 	defer queue.mutex.Unlock()
-	traversed := 0
-	for i := queue.front; traversed != queue.size; i = (i + 1) % len(queue.items) {
-		if queue.comparator(queue.items[i], item) == 0 {
-			return traversed
+	for i := 0; i < queue.size; i++ {
+		if pred(queue.items[(queue.front + i) % len(queue.items)]) {
+			return i
 		}
-		traversed++
 	}
 	return -1
 }
@@ -152,6 +442,25 @@ func (queue *Queue[T]) Copy() *Queue[T] {
 		rear: queue.rear,
 		size: queue.size,
 		comparator: queue.comparator,
+		bound: queue.bound,
+		stringLimit: queue.stringLimit,
+	}
+}
+
+// CopyCompact returns a pointer to a copy of the Queue whose circular slice holds exactly
+// its items, unlike Copy, which keeps the capacity. It suits long-lived snapshots of a
+// Queue that was transiently huge. Enqueueing into the copy grows its slice as needed.
+func (queue *Queue[T]) CopyCompact() *Queue[T] {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+	copiedSlice := make([]T, queue.size)
+	queue.read(copiedSlice)
+	return &Queue[T]{
+		items: copiedSlice,
+		size: queue.size,
+		comparator: queue.comparator,
+		bound: queue.bound,
+		stringLimit: queue.stringLimit,
 	}
 }
 
@@ -169,16 +478,76 @@ func (queue *Queue[T]) ToSlice() []T {
 	return copiedSlice
 }
 
+// All returns an iterator over the items of the Queue from front to rear.
+// It iterates over a snapshot taken when iteration starts, so the loop body
+// may call methods on the Queue (including ones that modify it).
+func (queue *Queue[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, item := range queue.ToSlice() {
+			if !yield(item) {
+				return
+			}
+		}
+	}
+}
+
 // String returns the string representation of the Queue.
+// If a limit was set with SetStringLimit, only that many items are printed.
 func (queue *Queue[T]) String() string {
 	queue.mutex.Lock()
 	defer queue.mutex.Unlock()
-	if queue.size == 0 {
-		return "[]"
+	if queue.stringLimit > 0 {
+		return queue.stringN(queue.stringLimit)
 	}
-	if queue.front < queue.rear {
-        return fmt.Sprintf("%v", queue.items[queue.front:queue.rear])
+	return queue.stringN(-1)
+}
+
+// StringN returns the string representation of the first max items of the Queue
+// (starting from the front), followed by "… (+N more)" if items were left out.
+// If max is negative, all items are printed.
+func (queue *Queue[T]) StringN(max int) string {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+	return queue.stringN(max)
+}
+
+// stringN returns the string representation of the first max items of the Queue.
+func (queue *Queue[T]) stringN(max int) string {
+	limit := format.Limit(queue.size, max)
+	items := make([]T, limit)
+	for i := 0; i < limit; i++ {
+		items[i] = queue.items[(queue.front + i) % len(queue.items)]
+	}
+	return format.Truncated(items, queue.size - limit)
+}
+
+// SetStringLimit limits String to printing the first max items of the Queue.
+// A max of 0 or less removes the limit.
+func (queue *Queue[T]) SetStringLimit(max int) {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+	queue.stringLimit = max
+}
+
+// MemUsage returns an approximation of the number of bytes used by the Queue,
+// including its whole backing slice. Memory referenced by the items themselves
+// (e.g. the contents of strings or slices) is not counted; use MemUsageFunc for that.
+func (queue *Queue[T]) MemUsage() int64 {
+	return queue.MemUsageFunc(nil)
+}
+
+// MemUsageFunc is like MemUsage, but also adds the result of calling size on every item.
+// The size function should return the bytes referenced by an item beyond its own fixed size.
+// If size is nil, it behaves exactly like MemUsage.
+func (queue *Queue[T]) MemUsageFunc(size func(T) int64) int64 {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+	var zeroValue T
+	total := int64(unsafe.Sizeof(*queue)) + int64(cap(queue.items)) * int64(unsafe.Sizeof(zeroValue))
+	if size != nil {
+		for i := 0; i < queue.size; i++ {
+			total += size(queue.items[(queue.front + i) % len(queue.items)])
+		}
 	}
-	concatenated := append(queue.items[queue.front:], queue.items[:queue.rear]...)
-	return fmt.Sprintf("%v", concatenated)
+	return total
 }
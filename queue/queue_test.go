@@ -1,8 +1,12 @@
 package queue
 
 import (
+	"context"
+	"errors"
+	"slices"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/davidpogosian/ds/comparators"
 	"github.com/davidpogosian/ds/testutils"
@@ -14,6 +18,80 @@ func TestNewEmpty(t *testing.T) {
 	testutils.Assert(t, "q.String()", "[]", q.String())
 }
 
+func TestNew(t *testing.T) {
+	q := New[int]()
+	testutils.Assert(t, "q.Size()", 0, q.Size())
+	for i := 0; i < 10; i++ {
+		q.Enqueue(i)
+	}
+	testutils.Assert(t, "q.Find(3)", 3, q.Find(3))
+	testutils.Assert(t, "q.Find(10)", -1, q.Find(10))
+	testutils.Assert(t, "q.FindFunc()", 3, q.FindFunc(func(item int) bool { return item == 3 }))
+	testutils.Assert(t, "q.Copy().Find(3)", 3, q.Copy().Find(3))
+	bounded, err := NewBounded[string](2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bounded.Enqueue("a")
+	bounded.Enqueue("b")
+	testutils.Assert(t, "bounded.Find(\"b\")", 1, bounded.Find("b"))
+}
+
+func TestFindWithoutComparator(t *testing.T) {
+	q := New[job]()
+	q.Enqueue(job{1, "a"})
+	testutils.Assert(t, "q.FindFunc()", 0, q.FindFunc(func(j job) bool { return j.id == 1 }))
+	defer func() {
+		if recover() == nil {
+			t.Fatal("Expected Find to panic on a Queue without a comparator.")
+		}
+	}()
+	q.Find(job{1, "a"})
+}
+
+func TestNewBounded(t *testing.T) {
+	t.Run("InvalidCapacity", func(t *testing.T) {
+		_, err := NewBounded[int](-1)
+		if err == nil {
+			t.Fatal("Created a Queue with a capacity of -1.")
+		}
+	})
+
+	t.Run("Sequential", func(t *testing.T) {
+		q, err := NewBounded[int](3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < 3; i++ {
+			if err := q.Enqueue(i); err != nil {
+				t.Fatal(err)
+			}
+		}
+		testutils.Assert(t, "q.IsFull()", true, q.IsFull())
+		if err := q.Enqueue(3); !errors.Is(err, ErrFull) {
+			t.Fatalf("Expected ErrFull, instead got: %v", err)
+		}
+		testutils.Assert(t, "q.TryEnqueue(3)", false, q.TryEnqueue(3))
+		testutils.Assert(t, "q.Copy().TryEnqueue(3)", false, q.Copy().TryEnqueue(3))
+		q.Dequeue()
+		testutils.Assert(t, "q.TryEnqueue(3)", true, q.TryEnqueue(3))
+		testutils.AssertSlices(t, []int{1, 2, 3}, q.ToSlice())
+		testutils.Assert(t, "q.Cap()", 3, q.Cap())
+	})
+
+	t.Run("Concurrent", func(t *testing.T) {
+		q, err := NewBounded[int](500)
+		if err != nil {
+			t.Fatal(err)
+		}
+		testutils.ConcurrentOperations(t, 10, 100, func() error {
+			q.TryEnqueue(1)
+			return nil
+		})
+		testutils.Assert(t, "q.Size()", 500, q.Size())
+	})
+}
+
 func TestNewFromSlice(t *testing.T) {
 	t.Run("InitializedSlice", func(t *testing.T) {
 		slice := []int{1, 2, 3}
@@ -167,6 +245,37 @@ func TestFind(t *testing.T) {
 	})
 }
 
+func TestContains(t *testing.T) {
+	q := NewFromSlice([]int{1, 2, 3}, comparators.ComparatorInt)
+	testutils.Assert(t, "q.Contains(2)", true, q.Contains(2))
+	testutils.Assert(t, "q.Contains(4)", false, q.Contains(4))
+	q = New[int]()
+	q.Enqueue(2)
+	testutils.Assert(t, "q.Contains(2)", true, q.Contains(2))
+	testutils.Assert(t, "q.Contains(3)", false, q.Contains(3))
+	bounded, _ := NewBounded[int](1)
+	bounded.Enqueue(2)
+	testutils.Assert(t, "bounded.Contains(2)", true, bounded.Contains(2))
+	withoutComparator := New[job]()
+	withoutComparator.Enqueue(job{1, "a"})
+	defer func() {
+		if recover() == nil {
+			t.Fatal("Expected Contains to panic on a Queue without a comparator.")
+		}
+	}()
+	withoutComparator.Contains(job{1, "a"})
+}
+
+func TestFindFunc(t *testing.T) {
+	q := NewFromSlice([]int{1, 2, 3, 4}, comparators.ComparatorInt)
+	q.Dequeue()
+	q.Enqueue(5)
+	even := func(item int) bool { return item % 2 == 0 }
+	testutils.Assert(t, "q.FindFunc(even)", 0, q.FindFunc(even))
+	testutils.Assert(t, "q.FindFunc()", 3, q.FindFunc(func(item int) bool { return item == 5 }))
+	testutils.Assert(t, "q.FindFunc()", -1, q.FindFunc(func(item int) bool { return item > 5 }))
+}
+
 func TestCopy(t *testing.T) {
 	q1 := NewFromSlice([]int{1, 2, 3}, comparators.ComparatorInt)
 	q2 := q1.Copy()
@@ -202,3 +311,289 @@ func TestString(t *testing.T) {
 	q := NewFromSlice([]int{1, 2, 3}, comparators.ComparatorInt)
 	testutils.Assert(t, "q.String()", "[1 2 3]", q.String())
 }
+
+func TestStringN(t *testing.T) {
+	q := NewEmpty[int](comparators.ComparatorInt)
+	for i := 0; i < 6; i++ {
+		q.Enqueue(i)
+	}
+	q.Dequeue()
+	testutils.Assert(t, "q.StringN(2)", "[1 2 … (+3 more)]", q.StringN(2))
+	testutils.Assert(t, "q.StringN(-1)", "[1 2 3 4 5]", q.StringN(-1))
+	q.SetStringLimit(3)
+	testutils.Assert(t, "q.String()", "[1 2 3 … (+2 more)]", q.String())
+}
+
+func TestMemUsage(t *testing.T) {
+	q := NewEmpty[int64](comparators.ComparatorInt64)
+	base := q.MemUsage()
+	for i := 0; i < 5; i++ {
+		q.Enqueue(int64(i))
+	}
+	// The backing slice grew from 4 to 8 items.
+	testutils.Assert(t, "q.MemUsage()", base + 4 * 8, q.MemUsage())
+	testutils.Assert(t, "q.MemUsageFunc(...)", base + 4 * 8 + 5, q.MemUsageFunc(func(int64) int64 { return 1 }))
+}
+
+func TestAll(t *testing.T) {
+	q := NewEmpty[int](comparators.ComparatorInt)
+	for i := 0; i < 6; i++ {
+		q.Enqueue(i)
+	}
+	q.Dequeue()
+	q.Dequeue()
+	q.Enqueue(6)
+	testutils.AssertSlices(t, []int{2, 3, 4, 5, 6}, slices.Collect(q.All()))
+	for range q.All() {
+		q.Dequeue()
+	}
+	testutils.Assert(t, "q.Size()", 0, q.Size())
+}
+
+func TestCap(t *testing.T) {
+	q := NewEmpty[int](comparators.ComparatorInt)
+	testutils.Assert(t, "q.Cap()", 4, q.Cap())
+	for i := 0; i < 5; i++ {
+		q.Enqueue(i)
+	}
+	testutils.Assert(t, "q.Cap()", 8, q.Cap())
+	testutils.Assert(t, "q.Capacity()", 8, q.Capacity())
+}
+
+func TestDequeueWait(t *testing.T) {
+	t.Run("Blocking", func(t *testing.T) {
+		q := New[int]()
+		go func() {
+			time.Sleep(10 * time.Millisecond)
+			q.Enqueue(1)
+		}()
+		item, err := q.DequeueWait(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		testutils.Assert(t, "item", 1, item)
+	})
+
+	t.Run("Canceled", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10 * time.Millisecond)
+		defer cancel()
+		_, err := New[int]().DequeueWait(ctx)
+		testutils.Assert(t, "err", context.DeadlineExceeded, err)
+	})
+
+	t.Run("Concurrent", func(t *testing.T) {
+		q, err := NewBounded[int](10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		go func() {
+			for i := 0; i < 1000; i++ {
+				q.EnqueueWait(context.Background(), i)
+			}
+		}()
+		testutils.ConcurrentOperations(t, 10, 100, func() error {
+			_, err := q.DequeueWait(context.Background())
+			return err
+		})
+		testutils.Assert(t, "q.Size()", 0, q.Size())
+	})
+}
+
+func TestEnqueueWait(t *testing.T) {
+	t.Run("Blocking", func(t *testing.T) {
+		q, err := NewBounded[int](1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		q.Enqueue(1)
+		go func() {
+			time.Sleep(10 * time.Millisecond)
+			q.Dequeue()
+		}()
+		if err := q.EnqueueWait(context.Background(), 2); err != nil {
+			t.Fatal(err)
+		}
+		testutils.AssertSlices(t, []int{2}, q.ToSlice())
+	})
+
+	t.Run("Canceled", func(t *testing.T) {
+		q, err := NewBounded[int](1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		q.Enqueue(1)
+		ctx, cancel := context.WithTimeout(context.Background(), 10 * time.Millisecond)
+		defer cancel()
+		testutils.Assert(t, "err", context.DeadlineExceeded, q.EnqueueWait(ctx, 2))
+		testutils.AssertSlices(t, []int{1}, q.ToSlice())
+	})
+}
+
+func TestEnqueueAll(t *testing.T) {
+	t.Run("Sequential", func(t *testing.T) {
+		q := NewFromSlice([]int{1, 2, 3, 4}, comparators.ComparatorInt)
+		q.Dequeue()
+		q.Dequeue()
+		// The new items wrap around the end of the circular slice.
+		if err := q.EnqueueAll([]int{5, 6}); err != nil {
+			t.Fatal(err)
+		}
+		testutils.AssertSlices(t, []int{3, 4, 5, 6}, q.ToSlice())
+		if err := q.EnqueueAll([]int{7, 8, 9, 10, 11, 12, 13, 14, 15}); err != nil {
+			t.Fatal(err)
+		}
+		testutils.AssertSlices(t, []int{3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}, q.ToSlice())
+		q.Enqueue(16)
+		testutils.Assert(t, "q.Size()", 14, q.Size())
+	})
+
+	t.Run("Bounded", func(t *testing.T) {
+		q, err := NewBounded[int](2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := q.EnqueueAll([]int{1, 2, 3}); !errors.Is(err, ErrFull) {
+			t.Fatalf("Expected ErrFull, instead got: %v", err)
+		}
+		testutils.Assert(t, "q.Size()", 0, q.Size())
+	})
+
+	t.Run("Concurrent", func(t *testing.T) {
+		q := New[int]()
+		testutils.ConcurrentOperations(t, 10, 100, func() error {
+			return q.EnqueueAll([]int{1, 2})
+		})
+		testutils.Assert(t, "q.Size()", 2000, q.Size())
+	})
+}
+
+func TestDequeueN(t *testing.T) {
+	t.Run("Sequential", func(t *testing.T) {
+		q := NewFromSlice([]int{1, 2, 3, 4}, comparators.ComparatorInt)
+		q.Dequeue()
+		q.Enqueue(5)
+		dequeued, err := q.DequeueN(3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		testutils.AssertSlices(t, []int{2, 3, 4}, dequeued)
+		testutils.AssertSlices(t, []int{5}, q.ToSlice())
+	})
+
+	t.Run("TooMany", func(t *testing.T) {
+		q := NewFromSlice([]int{1, 2}, comparators.ComparatorInt)
+		if _, err := q.DequeueN(3); err == nil {
+			t.Fatal("Dequeued 3 items from a Queue of size 2.")
+		}
+		testutils.Assert(t, "q.Size()", 2, q.Size())
+	})
+
+	t.Run("Concurrent", func(t *testing.T) {
+		q := New[int]()
+		for i := 0; i < 2000; i++ {
+			q.Enqueue(i)
+		}
+		testutils.ConcurrentOperations(t, 10, 100, func() error {
+			_, err := q.DequeueN(2)
+			return err
+		})
+		testutils.Assert(t, "q.Size()", 0, q.Size())
+	})
+}
+
+func TestTryDequeueAndPeek(t *testing.T) {
+	q := NewFromSlice([]int{1, 2}, comparators.ComparatorInt)
+	front, ok := q.TryPeek()
+	testutils.Assert(t, "ok", true, ok)
+	testutils.Assert(t, "front", 1, front)
+	back, ok := q.TryPeekBack()
+	testutils.Assert(t, "ok", true, ok)
+	testutils.Assert(t, "back", 2, back)
+	for _, expected := range []int{1, 2} {
+		item, ok := q.TryDequeue()
+		testutils.Assert(t, "ok", true, ok)
+		testutils.Assert(t, "item", expected, item)
+	}
+	if _, ok := q.TryDequeue(); ok {
+		t.Fatal("Dequeued from an empty Queue.")
+	}
+	if _, ok := q.TryPeek(); ok {
+		t.Fatal("Peeked an empty Queue.")
+	}
+	if _, ok := q.TryPeekBack(); ok {
+		t.Fatal("Peeked the back of an empty Queue.")
+	}
+}
+
+func TestPeekBack(t *testing.T) {
+	q := NewEmpty[int](comparators.ComparatorInt)
+	if _, err := q.PeekBack(); err == nil {
+		t.Fatal("Peeked the back of an empty Queue.")
+	}
+	for i := 1; i <= 5; i++ {
+		q.Enqueue(i)
+		back, err := q.PeekBack()
+		if err != nil {
+			t.Fatal(err)
+		}
+		testutils.Assert(t, "back", i, back)
+	}
+}
+
+func TestShrink(t *testing.T) {
+	t.Run("Unbounded", func(t *testing.T) {
+		q := NewEmpty[int](comparators.ComparatorInt, 100)
+		testutils.Assert(t, "q.Cap()", 100, q.Cap())
+		for i := 0; i < 150; i++ {
+			q.Enqueue(i)
+		}
+		q.DequeueN(145)
+		q.Shrink()
+		testutils.Assert(t, "q.Cap()", 5, q.Cap())
+		testutils.AssertSlices(t, []int{145, 146, 147, 148, 149}, q.ToSlice())
+		q.Enqueue(150)
+		testutils.AssertSlices(t, []int{145, 146, 147, 148, 149, 150}, q.ToSlice())
+		q.Clear()
+		q.Shrink()
+		testutils.Assert(t, "q.Cap()", 4, q.Cap())
+	})
+
+	t.Run("Bounded", func(t *testing.T) {
+		q, err := NewBounded[int](10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		q.Shrink()
+		testutils.Assert(t, "q.Cap()", 4, q.Cap())
+		for i := 0; i < 10; i++ {
+			if err := q.Enqueue(i); err != nil {
+				t.Fatal(err)
+			}
+		}
+		testutils.Assert(t, "q.Cap()", 10, q.Cap())
+		testutils.Assert(t, "q.TryEnqueue(10)", false, q.TryEnqueue(10))
+	})
+}
+
+func TestNilSlice(t *testing.T) {
+	q := NewFromSlice[int](nil, comparators.ComparatorInt)
+	q.Enqueue(1)
+	q.Enqueue(2)
+	testutils.AssertSlices(t, []int{1, 2}, q.ToSlice())
+}
+
+func TestCopyCompact(t *testing.T) {
+	q := NewEmpty[int](comparators.ComparatorInt, 1000)
+	for i := 0; i < 10; i++ {
+		q.Enqueue(i)
+	}
+	q.DequeueN(7)
+	c := q.CopyCompact()
+	testutils.Assert(t, "c.Cap()", 3, c.Cap())
+	testutils.AssertSlices(t, []int{7, 8, 9}, c.ToSlice())
+	c.Enqueue(10)
+	testutils.AssertSlices(t, []int{7, 8, 9, 10}, c.ToSlice())
+	testutils.AssertSlices(t, []int{7, 8, 9}, q.ToSlice())
+	testutils.Assert(t, "q.Cap()", 1000, q.Cap())
+	testutils.Assert(t, "New[int]().CopyCompact().Size()", 0, New[int]().CopyCompact().Size())
+}
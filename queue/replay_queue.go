@@ -0,0 +1,173 @@
+package queue
+
+import (
+	"fmt"
+	"sync"
+)
+
+// ReplayQueue is a struct representing a queue with multiple named consumers.
+// Every consumer has its own read offset over the same retained buffer, so each
+// consumer sees every item, and items are only released once all consumers
+// have read past them. Offsets are absolute: the first item ever enqueued has
+// offset 0. It contains a slice of retained items, the index of the first live
+// item in that slice, the offset of that item, the offset of every consumer,
+// and a mutex for thread-safety.
+type ReplayQueue[T any] struct {
+	items []T
+	head int
+	base int64
+	cursors map[string]int64
+	mutex sync.Mutex
+}
+
+// NewReplayQueue creates a new empty ReplayQueue without consumers and returns a pointer to it.
+// Items enqueued while there are no consumers are retained until the next consumer reads them.
+func NewReplayQueue[T any]() *ReplayQueue[T] {
+	return &ReplayQueue[T]{cursors: make(map[string]int64)}
+}
+
+// tail returns the offset one past the last enqueued item.
+func (rq *ReplayQueue[T]) tail() int64 {
+	return rq.base + int64(len(rq.items) - rq.head)
+}
+
+// release drops the items that every consumer has already read.
+func (rq *ReplayQueue[T]) release() {
+	if len(rq.cursors) == 0 {
+		return
+	}
+	min := rq.tail()
+	for _, offset := range rq.cursors {
+		if offset < min {
+			min = offset
+		}
+	}
+	released := int(min - rq.base)
+	var zeroValue T
+	for i := rq.head; i < rq.head + released; i++ {
+		rq.items[i] = zeroValue
+	}
+	rq.head += released
+	rq.base = min
+	if rq.head > len(rq.items) / 2 {
+		rq.items = append([]T(nil), rq.items[rq.head:]...)
+		rq.head = 0
+	}
+}
+
+// Enqueue adds an item to the rear of the ReplayQueue.
+func (rq *ReplayQueue[T]) Enqueue(newItem T) {
+	rq.mutex.Lock()
+	defer rq.mutex.Unlock()
+	rq.items = append(rq.items, newItem)
+}
+
+// Subscribe adds a consumer with the given name, starting at the oldest retained item.
+// If a consumer with that name already exists, an error is returned.
+func (rq *ReplayQueue[T]) Subscribe(name string) error {
+	rq.mutex.Lock()
+	defer rq.mutex.Unlock()
+	if _, exists := rq.cursors[name]; exists {
+		return fmt.Errorf("Consumer '%s' is already subscribed to the ReplayQueue.", name)
+	}
+	rq.cursors[name] = rq.base
+	return nil
+}
+
+// Unsubscribe removes the consumer with the given name, releasing items only it was holding.
+// If no consumer has that name, nothing happens.
+func (rq *ReplayQueue[T]) Unsubscribe(name string) {
+	rq.mutex.Lock()
+	defer rq.mutex.Unlock()
+	delete(rq.cursors, name)
+	rq.release()
+}
+
+// cursor returns the offset of the named consumer.
+// If no consumer has that name, an error is returned.
+func (rq *ReplayQueue[T]) cursor(name string) (int64, error) {
+	offset, exists := rq.cursors[name]
+	if !exists {
+		return 0, fmt.Errorf("Consumer '%s' is not subscribed to the ReplayQueue.", name)
+	}
+	return offset, nil
+}
+
+// Next returns the next item for the named consumer and advances its offset.
+// It returns an error if the consumer does not exist or has read every item.
+func (rq *ReplayQueue[T]) Next(name string) (T, error) {
+	rq.mutex.Lock()
+	defer rq.mutex.Unlock()
+	var zeroValue T
+	offset, err := rq.cursor(name)
+	if err != nil {
+		return zeroValue, err
+	}
+	if offset == rq.tail() {
+		return zeroValue, fmt.Errorf("Consumer '%s' has no items left in the ReplayQueue.", name)
+	}
+	item := rq.items[rq.head + int(offset - rq.base)]
+	rq.cursors[name] = offset + 1
+	rq.release()
+	return item, nil
+}
+
+// Peek returns the next item for the named consumer without advancing its offset.
+// It returns an error if the consumer does not exist or has read every item.
+func (rq *ReplayQueue[T]) Peek(name string) (T, error) {
+	rq.mutex.Lock()
+	defer rq.mutex.Unlock()
+	var zeroValue T
+	offset, err := rq.cursor(name)
+	if err != nil {
+		return zeroValue, err
+	}
+	if offset == rq.tail() {
+		return zeroValue, fmt.Errorf("Consumer '%s' has no items left in the ReplayQueue.", name)
+	}
+	return rq.items[rq.head + int(offset - rq.base)], nil
+}
+
+// Seek moves the offset of the named consumer, e.g. back to replay retained items.
+// It returns an error if the consumer does not exist or the offset is not between
+// the oldest retained item and the end of the ReplayQueue.
+func (rq *ReplayQueue[T]) Seek(name string, offset int64) error {
+	rq.mutex.Lock()
+	defer rq.mutex.Unlock()
+	if _, err := rq.cursor(name); err != nil {
+		return err
+	}
+	if offset < rq.base || offset > rq.tail() {
+		return fmt.Errorf("Cannot seek to offset %d, retained offsets are [%d, %d].", offset, rq.base, rq.tail())
+	}
+	rq.cursors[name] = offset
+	rq.release()
+	return nil
+}
+
+// Offset returns the offset of the next item the named consumer will read.
+// If no consumer has that name, an error is returned.
+func (rq *ReplayQueue[T]) Offset(name string) (int64, error) {
+	rq.mutex.Lock()
+	defer rq.mutex.Unlock()
+	return rq.cursor(name)
+}
+
+// Pending returns the number of items the named consumer has not read yet.
+// If no consumer has that name, an error is returned.
+func (rq *ReplayQueue[T]) Pending(name string) (int, error) {
+	rq.mutex.Lock()
+	defer rq.mutex.Unlock()
+	offset, err := rq.cursor(name)
+	if err != nil {
+		return 0, err
+	}
+	return int(rq.tail() - offset), nil
+}
+
+// Size returns the number of items retained by the ReplayQueue.
+func (rq *ReplayQueue[T]) Size() int {
+	rq.mutex.Lock()
+	defer rq.mutex.Unlock()
+	return len(rq.items) - rq.head
+}
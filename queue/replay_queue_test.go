@@ -0,0 +1,109 @@
+package queue
+
+import (
+	"testing"
+
+	"github.com/davidpogosian/ds/testutils"
+)
+
+func TestReplayQueueSubscribe(t *testing.T) {
+	rq := NewReplayQueue[int]()
+	if err := rq.Subscribe("a"); err != nil {
+		t.Fatal(err)
+	}
+	if err := rq.Subscribe("a"); err == nil {
+		t.Fatal("Subscribed the same consumer twice.")
+	}
+	if _, err := rq.Next("b"); err == nil {
+		t.Fatal("Read from a consumer that is not subscribed.")
+	}
+}
+
+func TestReplayQueueNext(t *testing.T) {
+	t.Run("IndependentConsumers", func(t *testing.T) {
+		rq := NewReplayQueue[int]()
+		rq.Subscribe("a")
+		rq.Subscribe("b")
+		rq.Enqueue(1)
+		rq.Enqueue(2)
+		for _, expected := range []int{1, 2} {
+			item, err := rq.Next("a")
+			if err != nil {
+				t.Fatal(err)
+			}
+			testutils.Assert(t, "item", expected, item)
+		}
+		if _, err := rq.Next("a"); err == nil {
+			t.Fatal("Read past the end of the ReplayQueue.")
+		}
+		// b has not read anything, so both items are retained.
+		testutils.Assert(t, "rq.Size()", 2, rq.Size())
+		item, err := rq.Next("b")
+		if err != nil {
+			t.Fatal(err)
+		}
+		testutils.Assert(t, "item", 1, item)
+		testutils.Assert(t, "rq.Size()", 1, rq.Size())
+		rq.Unsubscribe("b")
+		testutils.Assert(t, "rq.Size()", 0, rq.Size())
+	})
+
+	t.Run("Concurrent", func(t *testing.T) {
+		rq := NewReplayQueue[int]()
+		rq.Subscribe("a")
+		rq.Subscribe("b")
+		for i := 0; i < 1000; i++ {
+			rq.Enqueue(i)
+		}
+		testutils.ConcurrentOperations(t, 10, 100, func() error {
+			if _, err := rq.Next("a"); err != nil {
+				return err
+			}
+			_, err := rq.Next("b")
+			return err
+		})
+		testutils.Assert(t, "rq.Size()", 0, rq.Size())
+	})
+}
+
+func TestReplayQueuePeek(t *testing.T) {
+	rq := NewReplayQueue[string]()
+	rq.Subscribe("a")
+	if _, err := rq.Peek("a"); err == nil {
+		t.Fatal("Peeked an empty ReplayQueue.")
+	}
+	rq.Enqueue("x")
+	item, err := rq.Peek("a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutils.Assert(t, "item", "x", item)
+	pending, _ := rq.Pending("a")
+	testutils.Assert(t, "pending", 1, pending)
+}
+
+func TestReplayQueueSeek(t *testing.T) {
+	rq := NewReplayQueue[int]()
+	for i := 0; i < 5; i++ {
+		rq.Enqueue(i)
+	}
+	rq.Subscribe("a")
+	rq.Subscribe("b")
+	rq.Next("a")
+	rq.Next("a")
+	rq.Next("b")
+	// Offset 0 was released once both consumers read it.
+	if err := rq.Seek("a", 0); err == nil {
+		t.Fatal("Seeked to a released offset.")
+	}
+	if err := rq.Seek("a", 1); err != nil {
+		t.Fatal(err)
+	}
+	item, _ := rq.Next("a")
+	testutils.Assert(t, "item", 1, item)
+	offset, _ := rq.Offset("a")
+	testutils.Assert(t, "offset", int64(2), offset)
+	if err := rq.Seek("a", 6); err == nil {
+		t.Fatal("Seeked past the end of the ReplayQueue.")
+	}
+}
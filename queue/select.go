@@ -0,0 +1,52 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"reflect"
+)
+
+// Select dequeues an item from whichever of the queues has one, and returns the index
+// of that Queue among the arguments together with the item. If all queues are empty,
+// Select blocks until an item is enqueued into any of them or ctx is done, in which
+// case ctx.Err() is returned.
+// When several queues have items, the one to dequeue from is picked by starting at a
+// random Queue and going round-robin from there, so no Queue is starved.
+// An error is returned if no queues are given.
+func Select[T any](ctx context.Context, queues ...*Queue[T]) (int, T, error) {
+	var zeroValue T
+	if len(queues) == 0 {
+		return -1, zeroValue, fmt.Errorf("Cannot select from zero queues.")
+	}
+	cases := make([]reflect.SelectCase, len(queues) + 1)
+	cases[0] = reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ctx.Done())}
+	for {
+		start := rand.IntN(len(queues))
+		for i := range queues {
+			index := (start + i) % len(queues)
+			item, channel, ok := queues[index].dequeueOrWait()
+			if ok {
+				return index, item, nil
+			}
+			cases[index + 1] = reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(channel)}
+		}
+		if chosen, _, _ := reflect.Select(cases); chosen == 0 {
+			return -1, zeroValue, ctx.Err()
+		}
+	}
+}
+
+// dequeueOrWait removes and returns the item at the front of the Queue and true.
+// If the Queue is empty, it instead returns a channel that is closed the next time
+// an item is enqueued, and false. Checking and subscribing under the same lock
+// ensures no Enqueue can slip in between.
+func (queue *Queue[T]) dequeueOrWait() (T, <-chan struct{}, bool) {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+	if queue.size == 0 {
+		var zeroValue T
+		return zeroValue, subscribe(&queue.enqueued), false
+	}
+	return queue.dequeue(), nil, true
+}
@@ -0,0 +1,94 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/davidpogosian/ds/testutils"
+)
+
+func TestSelect(t *testing.T) {
+	t.Run("NoQueues", func(t *testing.T) {
+		_, _, err := Select[int](context.Background())
+		if err == nil {
+			t.Fatal("Selected from zero queues.")
+		}
+	})
+
+	t.Run("Ready", func(t *testing.T) {
+		q1 := New[int]()
+		q2 := New[int]()
+		q2.Enqueue(7)
+		index, item, err := Select(context.Background(), q1, q2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		testutils.Assert(t, "index", 1, index)
+		testutils.Assert(t, "item", 7, item)
+	})
+
+	t.Run("Fair", func(t *testing.T) {
+		q1 := New[int]()
+		q2 := New[int]()
+		for i := 0; i < 100; i++ {
+			q1.Enqueue(i)
+			q2.Enqueue(i)
+		}
+		for i := 0; i < 100; i++ {
+			if _, _, err := Select(context.Background(), q1, q2); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if q1.Size() == 0 || q2.Size() == 0 {
+			t.Fatalf("Expected both queues to be selected, instead got sizes %d and %d.", q1.Size(), q2.Size())
+		}
+	})
+
+	t.Run("Blocking", func(t *testing.T) {
+		q1 := New[int]()
+		q2 := New[int]()
+		go func() {
+			time.Sleep(10 * time.Millisecond)
+			q1.Enqueue(3)
+		}()
+		index, item, err := Select(context.Background(), q1, q2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		testutils.Assert(t, "index", 0, index)
+		testutils.Assert(t, "item", 3, item)
+	})
+
+	t.Run("Canceled", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10 * time.Millisecond)
+		defer cancel()
+		_, _, err := Select(ctx, New[int](), New[int]())
+		testutils.Assert(t, "err", context.DeadlineExceeded, err)
+	})
+
+	t.Run("Concurrent", func(t *testing.T) {
+		q1 := New[int]()
+		q2 := New[int]()
+		var waitGroup sync.WaitGroup
+		waitGroup.Add(1)
+		go func() {
+			defer waitGroup.Done()
+			for i := 0; i < 500; i++ {
+				q1.Enqueue(i)
+				q2.Enqueue(i)
+			}
+		}()
+		testutils.ConcurrentOperations(t, 10, 100, func() error {
+			_, _, err := Select(context.Background(), q1, q2)
+			if err != nil {
+				return fmt.Errorf("Select failed: %w", err)
+			}
+			return nil
+		})
+		waitGroup.Wait()
+		testutils.Assert(t, "q1.Size() + q2.Size()", 0, q1.Size() + q2.Size())
+	})
+}
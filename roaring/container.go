@@ -0,0 +1,295 @@
+package roaring
+
+import (
+	"iter"
+	"math/bits"
+	"slices"
+)
+
+// arrayMaxSize is the largest cardinality stored in an array container.
+// Above it a bitmap container (8 KiB) is smaller than an array (2 bytes per value).
+const arrayMaxSize = 4096
+
+// bitmapWords is the number of 64-bit words in a bitmap container.
+const bitmapWords = 1 << 16 / 64
+
+// kind identifies the representation of a container.
+type kind uint8
+
+const (
+	arrayKind kind = iota
+	bitmapKind
+	runKind
+)
+
+// interval represents the values from start to last, both inclusive.
+type interval struct {
+	start uint16
+	last uint16
+}
+
+// container struct represents the values of a Bitmap that share their upper 16 bits.
+// Depending on kind, the lower 16 bits are stored in a sorted array, in a bitmap
+// of 65536 bits, or as sorted, non-adjacent runs of consecutive values.
+// It also keeps track of its cardinality.
+type container struct {
+	kind kind
+	array []uint16
+	bitmap []uint64
+	runs []interval
+	cardinality int
+}
+
+// newArrayContainer returns a container holding the sorted, distinct values.
+func newArrayContainer(values []uint16) *container {
+	return &container{kind: arrayKind, array: values, cardinality: len(values)}
+}
+
+// fromWords returns a container holding the bits set in words, as an array if
+// that is smaller, otherwise as a bitmap. It returns nil if no bit is set.
+func fromWords(words []uint64) *container {
+	cardinality := 0
+	for _, word := range words {
+		cardinality += bits.OnesCount64(word)
+	}
+	if cardinality == 0 {
+		return nil
+	}
+	if cardinality > arrayMaxSize {
+		return &container{kind: bitmapKind, bitmap: words, cardinality: cardinality}
+	}
+	array := make([]uint16, 0, cardinality)
+	for i, word := range words {
+		for word != 0 {
+			array = append(array, uint16(i * 64 + bits.TrailingZeros64(word)))
+			word &= word - 1
+		}
+	}
+	return newArrayContainer(array)
+}
+
+// words returns the container as a newly allocated bitmap.
+func (c *container) words() []uint64 {
+	words := make([]uint64, bitmapWords)
+	switch c.kind {
+	case bitmapKind:
+		copy(words, c.bitmap)
+	case arrayKind:
+		for _, v := range c.array {
+			words[v / 64] |= 1 << (v % 64)
+		}
+	case runKind:
+		for _, r := range c.runs {
+			for v := int(r.start); v <= int(r.last); v++ {
+				words[v / 64] |= 1 << (v % 64)
+			}
+		}
+	}
+	return words
+}
+
+// materialize converts a run container back into an array or bitmap container,
+// so that it can be modified.
+func (c *container) materialize() {
+	if c.kind != runKind {
+		return
+	}
+	*c = *fromWords(c.words())
+}
+
+// contains returns true if the container holds the value.
+func (c *container) contains(v uint16) bool {
+	switch c.kind {
+	case arrayKind:
+		_, found := slices.BinarySearch(c.array, v)
+		return found
+	case bitmapKind:
+		return c.bitmap[v / 64] & (1 << (v % 64)) != 0
+	default:
+		i, _ := slices.BinarySearchFunc(c.runs, v, func(r interval, v uint16) int {
+			if r.last < v {
+				return -1
+			} else if r.start > v {
+				return 1
+			}
+			return 0
+		})
+		return i < len(c.runs) && c.runs[i].start <= v && v <= c.runs[i].last
+	}
+}
+
+// add adds the value to the container and returns true if it was not there yet.
+func (c *container) add(v uint16) bool {
+	c.materialize()
+	if c.kind == bitmapKind {
+		mask := uint64(1) << (v % 64)
+		if c.bitmap[v / 64] & mask != 0 {
+			return false
+		}
+		c.bitmap[v / 64] |= mask
+		c.cardinality++
+		return true
+	}
+	i, found := slices.BinarySearch(c.array, v)
+	if found {
+		return false
+	}
+	c.array = slices.Insert(c.array, i, v)
+	c.cardinality++
+	if c.cardinality > arrayMaxSize {
+		*c = container{kind: bitmapKind, bitmap: c.words(), cardinality: c.cardinality}
+	}
+	return true
+}
+
+// remove removes the value from the container and returns true if it was there.
+func (c *container) remove(v uint16) bool {
+	c.materialize()
+	if c.kind == bitmapKind {
+		mask := uint64(1) << (v % 64)
+		if c.bitmap[v / 64] & mask == 0 {
+			return false
+		}
+		c.bitmap[v / 64] &^= mask
+		c.cardinality--
+		if c.cardinality <= arrayMaxSize {
+			*c = *fromWords(c.bitmap)
+		}
+		return true
+	}
+	i, found := slices.BinarySearch(c.array, v)
+	if !found {
+		return false
+	}
+	c.array = slices.Delete(c.array, i, i + 1)
+	c.cardinality--
+	return true
+}
+
+// all returns an iterator over the values of the container in increasing order.
+func (c *container) all() iter.Seq[uint16] {
+	return func(yield func(uint16) bool) {
+		switch c.kind {
+		case arrayKind:
+			for _, v := range c.array {
+				if !yield(v) {
+					return
+				}
+			}
+		case bitmapKind:
+			for i, word := range c.bitmap {
+				for word != 0 {
+					if !yield(uint16(i * 64 + bits.TrailingZeros64(word))) {
+						return
+					}
+					word &= word - 1
+				}
+			}
+		case runKind:
+			for _, r := range c.runs {
+				for v := int(r.start); v <= int(r.last); v++ {
+					if !yield(uint16(v)) {
+						return
+					}
+				}
+			}
+		}
+	}
+}
+
+// clone returns a deep copy of the container.
+func (c *container) clone() *container {
+	return &container{
+		kind: c.kind,
+		array: slices.Clone(c.array),
+		bitmap: slices.Clone(c.bitmap),
+		runs: slices.Clone(c.runs),
+		cardinality: c.cardinality,
+	}
+}
+
+// and returns the intersection of two containers, or nil if it is empty.
+func and(a, b *container) *container {
+	if a.kind == arrayKind || b.kind == arrayKind {
+		if b.kind == arrayKind {
+			a, b = b, a
+		}
+		// a is an array: keep the values that b contains.
+		var result []uint16
+		for _, v := range a.array {
+			if b.contains(v) {
+				result = append(result, v)
+			}
+		}
+		if len(result) == 0 {
+			return nil
+		}
+		return newArrayContainer(result)
+	}
+	words := a.words()
+	other := b.words()
+	for i := range words {
+		words[i] &= other[i]
+	}
+	return fromWords(words)
+}
+
+// or returns the union of two containers.
+func or(a, b *container) *container {
+	words := a.words()
+	other := b.words()
+	for i := range words {
+		words[i] |= other[i]
+	}
+	return fromWords(words)
+}
+
+// andNot returns the values of a that are not in b, or nil if there are none.
+func andNot(a, b *container) *container {
+	if a.kind == arrayKind {
+		var result []uint16
+		for _, v := range a.array {
+			if !b.contains(v) {
+				result = append(result, v)
+			}
+		}
+		if len(result) == 0 {
+			return nil
+		}
+		return newArrayContainer(result)
+	}
+	words := a.words()
+	other := b.words()
+	for i := range words {
+		words[i] &^= other[i]
+	}
+	return fromWords(words)
+}
+
+// runOptimize converts the container into a run container if that is smaller,
+// or back into an array or bitmap container if it is not.
+func (c *container) runOptimize() {
+	var runs []interval
+	for v := range c.all() {
+		if len(runs) > 0 && int(runs[len(runs) - 1].last) + 1 == int(v) {
+			runs[len(runs) - 1].last = v
+		} else {
+			runs = append(runs, interval{start: v, last: v})
+		}
+	}
+	runBytes := 4 * len(runs)
+	otherBytes := 2 * c.cardinality
+	if c.cardinality > arrayMaxSize {
+		otherBytes = 8 * bitmapWords
+	}
+	if runBytes < otherBytes {
+		*c = container{kind: runKind, runs: runs, cardinality: c.cardinality}
+	} else {
+		c.materialize()
+	}
+}
+
+// memUsage returns an approximation of the number of bytes used by the container.
+func (c *container) memUsage() int64 {
+	return int64(2 * cap(c.array) + 8 * cap(c.bitmap) + 4 * cap(c.runs))
+}
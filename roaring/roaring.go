@@ -0,0 +1,444 @@
+// Package roaring provides thread-safe, compressed sets of uint32 values (Bitmap, a roaring bitmap)
+// and of uint64 values (Bitmap64, a sorted slice of Bitmaps keyed by the upper 32 bits).
+package roaring
+
+import (
+	"encoding/binary"
+	"fmt"
+	"iter"
+	"slices"
+	"sync"
+	"unsafe"
+
+	"github.com/davidpogosian/ds/internal/format"
+)
+
+// Bitmap struct represents a compressed set of uint32 values.
+// Values are grouped by their upper 16 bits, and each group is stored in the smallest
+// of three container kinds: a sorted array, a bitmap, or runs of consecutive values
+// (see RunOptimize). Sets of millions of IDs typically take a few bits per value.
+// Bitmap has a sorted slice of the upper 16 bits of every group, the matching containers,
+// a limit on the number of items printed by String (0 means no limit), and a mutex
+// for thread-safety.
+type Bitmap struct {
+	keys []uint16
+	containers []*container
+	stringLimit int
+	mu sync.Mutex
+}
+
+// NewEmpty returns a pointer to a new empty Bitmap.
+func NewEmpty() *Bitmap {
+	return &Bitmap{}
+}
+
+// NewFromSlice returns a pointer to a new Bitmap initialized with a slice.
+func NewFromSlice(slice []uint32) *Bitmap {
+	b := NewEmpty()
+	for _, v := range slice {
+		b.add(v)
+	}
+	return b
+}
+
+// split returns the upper and lower 16 bits of v.
+func split(v uint32) (uint16, uint16) {
+	return uint16(v >> 16), uint16(v)
+}
+
+// find returns the index of the container for the upper 16 bits
+// and whether it exists.
+func (b *Bitmap) find(high uint16) (int, bool) {
+	return slices.BinarySearch(b.keys, high)
+}
+
+// add adds v to the Bitmap and returns true if it was not there yet.
+func (b *Bitmap) add(v uint32) bool {
+	high, low := split(v)
+	i, found := b.find(high)
+	if !found {
+		b.keys = slices.Insert(b.keys, i, high)
+		b.containers = slices.Insert(b.containers, i, newArrayContainer(nil))
+	}
+	return b.containers[i].add(low)
+}
+
+// Add adds a value to the Bitmap.
+// If the value is already in the Bitmap, nothing happens.
+func (b *Bitmap) Add(v uint32) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.add(v)
+}
+
+// Remove removes a value from the Bitmap.
+// If the value is not in the Bitmap, nothing happens.
+func (b *Bitmap) Remove(v uint32) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	high, low := split(v)
+	i, found := b.find(high)
+	if !found || !b.containers[i].remove(low) {
+		return
+	}
+	if b.containers[i].cardinality == 0 {
+		b.keys = slices.Delete(b.keys, i, i + 1)
+		b.containers = slices.Delete(b.containers, i, i + 1)
+	}
+}
+
+// Contains returns true if the value is in the Bitmap.
+func (b *Bitmap) Contains(v uint32) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	high, low := split(v)
+	i, found := b.find(high)
+	return found && b.containers[i].contains(low)
+}
+
+// size returns the cardinality of the Bitmap. It must be called with the mutex held.
+func (b *Bitmap) size() int {
+	size := 0
+	for _, c := range b.containers {
+		size += c.cardinality
+	}
+	return size
+}
+
+// Size returns the number of values in the Bitmap (its cardinality).
+func (b *Bitmap) Size() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.size()
+}
+
+// IsEmpty returns a bool indicating the emptiness of the Bitmap.
+func (b *Bitmap) IsEmpty() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.containers) == 0
+}
+
+// Clear removes all values from the Bitmap.
+func (b *Bitmap) Clear() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.keys = nil
+	b.containers = nil
+}
+
+// clone returns a deep copy of the Bitmap. It must be called with the mutex held.
+func (b *Bitmap) clone() *Bitmap {
+	containers := make([]*container, len(b.containers))
+	for i, c := range b.containers {
+		containers[i] = c.clone()
+	}
+	return &Bitmap{keys: slices.Clone(b.keys), containers: containers, stringLimit: b.stringLimit}
+}
+
+// Copy returns a pointer to a copy of the Bitmap.
+func (b *Bitmap) Copy() *Bitmap {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.clone()
+}
+
+// combine builds a new Bitmap by applying op to the containers of a and b that share
+// their upper 16 bits. Containers present in only one Bitmap are kept if keepA or keepB is set.
+// Binary operations run on copies, so the two Bitmaps are never locked at once.
+func combine(a, b *Bitmap, op func(x, y *container) *container, keepA bool, keepB bool) *Bitmap {
+	result := NewEmpty()
+	appendContainer := func(key uint16, c *container) {
+		if c != nil && c.cardinality > 0 {
+			result.keys = append(result.keys, key)
+			result.containers = append(result.containers, c)
+		}
+	}
+	i, j := 0, 0
+	for i < len(a.keys) || j < len(b.keys) {
+		if j == len(b.keys) || (i < len(a.keys) && a.keys[i] < b.keys[j]) {
+			if keepA {
+				appendContainer(a.keys[i], a.containers[i])
+			}
+			i++
+		} else if i == len(a.keys) || b.keys[j] < a.keys[i] {
+			if keepB {
+				appendContainer(b.keys[j], b.containers[j])
+			}
+			j++
+		} else {
+			appendContainer(a.keys[i], op(a.containers[i], b.containers[j]))
+			i++
+			j++
+		}
+	}
+	return result
+}
+
+// And returns a pointer to a new Bitmap holding the values in both Bitmaps.
+func (b1 *Bitmap) And(b2 *Bitmap) *Bitmap {
+	a, b := b1.Copy(), b2.Copy()
+	return combine(a, b, and, false, false)
+}
+
+// Or returns a pointer to a new Bitmap holding the values in either Bitmap.
+func (b1 *Bitmap) Or(b2 *Bitmap) *Bitmap {
+	a, b := b1.Copy(), b2.Copy()
+	return combine(a, b, or, true, true)
+}
+
+// AndNot returns a pointer to a new Bitmap holding the values of this Bitmap
+// that are not in the Bitmap provided as an argument.
+func (b1 *Bitmap) AndNot(b2 *Bitmap) *Bitmap {
+	a, b := b1.Copy(), b2.Copy()
+	return combine(a, b, andNot, true, false)
+}
+
+// RunOptimize converts every container that is smaller as runs of consecutive
+// values into a run container. This pays off for dense ranges of IDs.
+// Containers are converted back when they are next modified.
+func (b *Bitmap) RunOptimize() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, c := range b.containers {
+		c.runOptimize()
+	}
+}
+
+// All returns an iterator over the values of the Bitmap in increasing order.
+// It iterates over a snapshot taken when iteration starts, so the loop body
+// may call methods on the Bitmap (including ones that modify it).
+func (b *Bitmap) All() iter.Seq[uint32] {
+	return func(yield func(uint32) bool) {
+		snapshot := b.Copy()
+		for i, c := range snapshot.containers {
+			high := uint32(snapshot.keys[i]) << 16
+			for low := range c.all() {
+				if !yield(high | uint32(low)) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// toSlice returns the values of the Bitmap in increasing order.
+// It must be called with the mutex held.
+func (b *Bitmap) toSlice() []uint32 {
+	slice := make([]uint32, 0, b.size())
+	for i, c := range b.containers {
+		high := uint32(b.keys[i]) << 16
+		for low := range c.all() {
+			slice = append(slice, high | uint32(low))
+		}
+	}
+	return slice
+}
+
+// ToSlice returns the values of the Bitmap as a slice in increasing order.
+func (b *Bitmap) ToSlice() []uint32 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.toSlice()
+}
+
+// String returns the string representation of the Bitmap.
+// If a limit was set with SetStringLimit, only that many values are printed.
+func (b *Bitmap) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.stringLimit > 0 {
+		return b.stringN(b.stringLimit)
+	}
+	return b.stringN(-1)
+}
+
+// StringN returns the string representation of the first max values of the Bitmap,
+// followed by "… (+N more)" if values were left out.
+// If max is negative, all values are printed.
+func (b *Bitmap) StringN(max int) string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.stringN(max)
+}
+
+// stringN returns the string representation of the first max values of the Bitmap.
+func (b *Bitmap) stringN(max int) string {
+	size := b.size()
+	limit := format.Limit(size, max)
+	values := make([]uint32, 0, limit)
+	for i, c := range b.containers {
+		for low := range c.all() {
+			if len(values) == limit {
+				break
+			}
+			values = append(values, uint32(b.keys[i]) << 16 | uint32(low))
+		}
+	}
+	return format.Truncated(values, size - limit)
+}
+
+// SetStringLimit limits String to printing the first max values of the Bitmap.
+// A max of 0 or less removes the limit.
+func (b *Bitmap) SetStringLimit(max int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.stringLimit = max
+}
+
+// MemUsage returns an approximation of the number of bytes used by the Bitmap,
+// including its containers.
+func (b *Bitmap) MemUsage() int64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var c container
+	total := int64(unsafe.Sizeof(*b)) + int64(cap(b.keys)) * 2 + int64(cap(b.containers)) * 8
+	for _, cursor := range b.containers {
+		total += int64(unsafe.Sizeof(c)) + cursor.memUsage()
+	}
+	return total
+}
+
+// MarshalBinary encodes the Bitmap, keeping the kind of every container.
+// The encoding is little-endian: the number of containers, then for each container
+// its upper 16 bits, its kind and its contents.
+func (b *Bitmap) MarshalBinary() ([]byte, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	data := binary.LittleEndian.AppendUint32(nil, uint32(len(b.containers)))
+	for i, c := range b.containers {
+		data = binary.LittleEndian.AppendUint16(data, b.keys[i])
+		data = append(data, byte(c.kind))
+		switch c.kind {
+		case arrayKind:
+			data = binary.LittleEndian.AppendUint32(data, uint32(len(c.array)))
+			for _, v := range c.array {
+				data = binary.LittleEndian.AppendUint16(data, v)
+			}
+		case bitmapKind:
+			for _, word := range c.bitmap {
+				data = binary.LittleEndian.AppendUint64(data, word)
+			}
+		case runKind:
+			data = binary.LittleEndian.AppendUint32(data, uint32(len(c.runs)))
+			for _, r := range c.runs {
+				data = binary.LittleEndian.AppendUint16(data, r.start)
+				data = binary.LittleEndian.AppendUint16(data, r.last)
+			}
+		}
+	}
+	return data, nil
+}
+
+// decoder reads little-endian values from data and remembers the first error.
+type decoder struct {
+	data []byte
+	err error
+}
+
+// take returns the next n bytes, or nil if there are not enough left.
+func (d *decoder) take(n int) []byte {
+	if d.err != nil {
+		return nil
+	}
+	if n < 0 || len(d.data) < n {
+		d.err = fmt.Errorf("Cannot decode a Bitmap: unexpected end of data.")
+		return nil
+	}
+	chunk := d.data[:n]
+	d.data = d.data[n:]
+	return chunk
+}
+
+func (d *decoder) uint16() uint16 {
+	if chunk := d.take(2); chunk != nil {
+		return binary.LittleEndian.Uint16(chunk)
+	}
+	return 0
+}
+
+func (d *decoder) uint32() uint32 {
+	if chunk := d.take(4); chunk != nil {
+		return binary.LittleEndian.Uint32(chunk)
+	}
+	return 0
+}
+
+func (d *decoder) uint64() uint64 {
+	if chunk := d.take(8); chunk != nil {
+		return binary.LittleEndian.Uint64(chunk)
+	}
+	return 0
+}
+
+// UnmarshalBinary decodes a Bitmap encoded by MarshalBinary, replacing the contents of b.
+// If the data is not a valid encoding, an error is returned and b is left unchanged.
+func (b *Bitmap) UnmarshalBinary(data []byte) error {
+	d := &decoder{data: data}
+	count := int(d.uint32())
+	if d.err == nil && count > len(data) {
+		return fmt.Errorf("Cannot decode a Bitmap with %d containers from %d bytes.", count, len(data))
+	}
+	keys := make([]uint16, 0, count)
+	containers := make([]*container, 0, count)
+	for i := 0; i < count && d.err == nil; i++ {
+		key := d.uint16()
+		if i > 0 && key <= keys[i - 1] {
+			return fmt.Errorf("Cannot decode a Bitmap: container keys are not increasing.")
+		}
+		kindByte := d.take(1)
+		if kindByte == nil {
+			break
+		}
+		var words []uint64
+		switch kind(kindByte[0]) {
+		case arrayKind:
+			n := int(d.uint32())
+			if n > arrayMaxSize {
+				return fmt.Errorf("Cannot decode a Bitmap: array container of size %d.", n)
+			}
+			words = make([]uint64, bitmapWords)
+			for j := 0; j < n; j++ {
+				v := d.uint16()
+				words[v / 64] |= 1 << (v % 64)
+			}
+		case bitmapKind:
+			words = make([]uint64, bitmapWords)
+			for j := range words {
+				words[j] = d.uint64()
+			}
+		case runKind:
+			n := int(d.uint32())
+			if n > len(d.data) {
+				return fmt.Errorf("Cannot decode a Bitmap: run container of size %d.", n)
+			}
+			runs := make([]interval, n)
+			for j := range runs {
+				runs[j] = interval{start: d.uint16(), last: d.uint16()}
+				if runs[j].last < runs[j].start {
+					return fmt.Errorf("Cannot decode a Bitmap: run ends before it starts.")
+				}
+			}
+			words = (&container{kind: runKind, runs: runs}).words()
+		default:
+			return fmt.Errorf("Cannot decode a Bitmap: unknown container kind %d.", kindByte[0])
+		}
+		c := fromWords(words)
+		if c == nil {
+			return fmt.Errorf("Cannot decode a Bitmap: empty container.")
+		}
+		if kind(kindByte[0]) == runKind {
+			c.runOptimize()
+		}
+		keys = append(keys, key)
+		containers = append(containers, c)
+	}
+	if d.err != nil {
+		return d.err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.keys = keys
+	b.containers = containers
+	return nil
+}
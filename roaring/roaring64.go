@@ -0,0 +1,285 @@
+package roaring
+
+import (
+	"iter"
+	"slices"
+	"sync"
+	"unsafe"
+
+	"github.com/davidpogosian/ds/internal/format"
+)
+
+// Bitmap64 struct represents a compressed set of uint64 values.
+// Values are grouped by their upper 32 bits, and the lower 32 bits of each group are
+// stored in a Bitmap, so values that share their upper bits (e.g. IDs allocated from
+// the same range) compress as well as with a Bitmap.
+// Bitmap64 has a sorted slice of the upper 32 bits of every group, the matching Bitmaps,
+// a limit on the number of items printed by String (0 means no limit), and a mutex
+// for thread-safety. The Bitmaps are only reached through the Bitmap64.
+// Unlike Bitmap, Bitmap64 does not implement binary marshalling.
+type Bitmap64 struct {
+	keys []uint32
+	bitmaps []*Bitmap
+	stringLimit int
+	mu sync.Mutex
+}
+
+// NewEmpty64 returns a pointer to a new empty Bitmap64.
+func NewEmpty64() *Bitmap64 {
+	return &Bitmap64{}
+}
+
+// NewFromSlice64 returns a pointer to a new Bitmap64 initialized with a slice.
+func NewFromSlice64(slice []uint64) *Bitmap64 {
+	b := NewEmpty64()
+	for _, v := range slice {
+		b.add(v)
+	}
+	return b
+}
+
+// split64 returns the upper and lower 32 bits of v.
+func split64(v uint64) (uint32, uint32) {
+	return uint32(v >> 32), uint32(v)
+}
+
+// find returns the index of the Bitmap for the upper 32 bits
+// and whether it exists.
+func (b *Bitmap64) find(high uint32) (int, bool) {
+	return slices.BinarySearch(b.keys, high)
+}
+
+// add adds v to the Bitmap64. It must be called with the mutex held.
+func (b *Bitmap64) add(v uint64) {
+	high, low := split64(v)
+	i, found := b.find(high)
+	if !found {
+		b.keys = slices.Insert(b.keys, i, high)
+		b.bitmaps = slices.Insert(b.bitmaps, i, NewEmpty())
+	}
+	b.bitmaps[i].Add(low)
+}
+
+// Add adds a value to the Bitmap64.
+// If the value is already in the Bitmap64, nothing happens.
+func (b *Bitmap64) Add(v uint64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.add(v)
+}
+
+// Remove removes a value from the Bitmap64.
+// If the value is not in the Bitmap64, nothing happens.
+func (b *Bitmap64) Remove(v uint64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	high, low := split64(v)
+	i, found := b.find(high)
+	if !found {
+		return
+	}
+	b.bitmaps[i].Remove(low)
+	if b.bitmaps[i].IsEmpty() {
+		b.keys = slices.Delete(b.keys, i, i + 1)
+		b.bitmaps = slices.Delete(b.bitmaps, i, i + 1)
+	}
+}
+
+// Contains returns true if the value is in the Bitmap64.
+func (b *Bitmap64) Contains(v uint64) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	high, low := split64(v)
+	i, found := b.find(high)
+	return found && b.bitmaps[i].Contains(low)
+}
+
+// size returns the cardinality of the Bitmap64. It must be called with the mutex held.
+func (b *Bitmap64) size() int {
+	size := 0
+	for _, bitmap := range b.bitmaps {
+		size += bitmap.Size()
+	}
+	return size
+}
+
+// Size returns the number of values in the Bitmap64 (its cardinality).
+func (b *Bitmap64) Size() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.size()
+}
+
+// IsEmpty returns a bool indicating the emptiness of the Bitmap64.
+func (b *Bitmap64) IsEmpty() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.bitmaps) == 0
+}
+
+// Clear removes all values from the Bitmap64.
+func (b *Bitmap64) Clear() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.keys = nil
+	b.bitmaps = nil
+}
+
+// Copy returns a pointer to a copy of the Bitmap64.
+func (b *Bitmap64) Copy() *Bitmap64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	bitmaps := make([]*Bitmap, len(b.bitmaps))
+	for i, bitmap := range b.bitmaps {
+		bitmaps[i] = bitmap.Copy()
+	}
+	return &Bitmap64{keys: slices.Clone(b.keys), bitmaps: bitmaps, stringLimit: b.stringLimit}
+}
+
+// combine64 builds a new Bitmap64 by applying op to the Bitmaps of a and b that share
+// their upper 32 bits. Bitmaps present in only one Bitmap64 are kept if keepA or keepB is set.
+// Binary operations run on copies, so the two Bitmap64s are never locked at once.
+func combine64(a, b *Bitmap64, op func(x, y *Bitmap) *Bitmap, keepA bool, keepB bool) *Bitmap64 {
+	result := NewEmpty64()
+	appendBitmap := func(key uint32, bitmap *Bitmap) {
+		if !bitmap.IsEmpty() {
+			result.keys = append(result.keys, key)
+			result.bitmaps = append(result.bitmaps, bitmap)
+		}
+	}
+	i, j := 0, 0
+	for i < len(a.keys) || j < len(b.keys) {
+		if j == len(b.keys) || (i < len(a.keys) && a.keys[i] < b.keys[j]) {
+			if keepA {
+				appendBitmap(a.keys[i], a.bitmaps[i])
+			}
+			i++
+		} else if i == len(a.keys) || b.keys[j] < a.keys[i] {
+			if keepB {
+				appendBitmap(b.keys[j], b.bitmaps[j])
+			}
+			j++
+		} else {
+			appendBitmap(a.keys[i], op(a.bitmaps[i], b.bitmaps[j]))
+			i++
+			j++
+		}
+	}
+	return result
+}
+
+// And returns a pointer to a new Bitmap64 holding the values in both Bitmap64s.
+func (b1 *Bitmap64) And(b2 *Bitmap64) *Bitmap64 {
+	a, b := b1.Copy(), b2.Copy()
+	return combine64(a, b, (*Bitmap).And, false, false)
+}
+
+// Or returns a pointer to a new Bitmap64 holding the values in either Bitmap64.
+func (b1 *Bitmap64) Or(b2 *Bitmap64) *Bitmap64 {
+	a, b := b1.Copy(), b2.Copy()
+	return combine64(a, b, (*Bitmap).Or, true, true)
+}
+
+// AndNot returns a pointer to a new Bitmap64 holding the values of this Bitmap64
+// that are not in the Bitmap64 provided as an argument.
+func (b1 *Bitmap64) AndNot(b2 *Bitmap64) *Bitmap64 {
+	a, b := b1.Copy(), b2.Copy()
+	return combine64(a, b, (*Bitmap).AndNot, true, false)
+}
+
+// RunOptimize converts the containers of every Bitmap that are smaller as runs
+// of consecutive values into run containers (see Bitmap.RunOptimize).
+func (b *Bitmap64) RunOptimize() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, bitmap := range b.bitmaps {
+		bitmap.RunOptimize()
+	}
+}
+
+// All returns an iterator over the values of the Bitmap64 in increasing order.
+// It iterates over a snapshot taken when iteration starts, so the loop body
+// may call methods on the Bitmap64 (including ones that modify it).
+func (b *Bitmap64) All() iter.Seq[uint64] {
+	return func(yield func(uint64) bool) {
+		snapshot := b.Copy()
+		for i, bitmap := range snapshot.bitmaps {
+			high := uint64(snapshot.keys[i]) << 32
+			for low := range bitmap.All() {
+				if !yield(high | uint64(low)) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// ToSlice returns the values of the Bitmap64 as a slice in increasing order.
+func (b *Bitmap64) ToSlice() []uint64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.firstN(b.size())
+}
+
+// firstN returns the first n values of the Bitmap64 in increasing order, n being at most the size.
+// It must be called with the mutex held.
+func (b *Bitmap64) firstN(n int) []uint64 {
+	values := make([]uint64, 0, n)
+	for i, bitmap := range b.bitmaps {
+		high := uint64(b.keys[i]) << 32
+		for _, low := range bitmap.ToSlice() {
+			if len(values) == n {
+				return values
+			}
+			values = append(values, high | uint64(low))
+		}
+	}
+	return values
+}
+
+// String returns the string representation of the Bitmap64.
+// If a limit was set with SetStringLimit, only that many values are printed.
+func (b *Bitmap64) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.stringLimit > 0 {
+		return b.stringN(b.stringLimit)
+	}
+	return b.stringN(-1)
+}
+
+// StringN returns the string representation of the first max values of the Bitmap64,
+// followed by "… (+N more)" if values were left out.
+// If max is negative, all values are printed.
+func (b *Bitmap64) StringN(max int) string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.stringN(max)
+}
+
+// stringN returns the string representation of the first max values of the Bitmap64.
+func (b *Bitmap64) stringN(max int) string {
+	size := b.size()
+	limit := format.Limit(size, max)
+	return format.Truncated(b.firstN(limit), size - limit)
+}
+
+// SetStringLimit limits String to printing the first max values of the Bitmap64.
+// A max of 0 or less removes the limit.
+func (b *Bitmap64) SetStringLimit(max int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.stringLimit = max
+}
+
+// MemUsage returns an approximation of the number of bytes used by the Bitmap64,
+// including its Bitmaps.
+func (b *Bitmap64) MemUsage() int64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	total := int64(unsafe.Sizeof(*b)) + int64(cap(b.keys)) * 4 + int64(cap(b.bitmaps)) * 8
+	for _, bitmap := range b.bitmaps {
+		total += bitmap.MemUsage()
+	}
+	return total
+}
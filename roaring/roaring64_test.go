@@ -0,0 +1,140 @@
+package roaring
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/davidpogosian/ds/set"
+	"github.com/davidpogosian/ds/testutils"
+)
+
+// randomValues64 returns n random values spread over a few upper 32-bit groups.
+func randomValues64(seed int64, n int) []uint64 {
+	rng := rand.New(rand.NewSource(seed))
+	values := make([]uint64, n)
+	for i := range values {
+		values[i] = uint64(rng.Intn(4)) << 40 | uint64(rng.Intn(3)) << 32 | uint64(rng.Intn(1 << 18))
+	}
+	return values
+}
+
+// sortedUnique64 returns the distinct values in increasing order.
+func sortedUnique64(values []uint64) []uint64 {
+	values = slices.Clone(values)
+	slices.Sort(values)
+	return slices.Compact(values)
+}
+
+func TestAdd64(t *testing.T) {
+	t.Run("Sequential", func(t *testing.T) {
+		values := append(randomValues64(1, 20000), 0, 1 << 63, 1 << 64 - 1)
+		b := NewFromSlice64(values)
+		expected := sortedUnique64(values)
+		testutils.Assert(t, "b.Size()", len(expected), b.Size())
+		testutils.AssertSlices(t, expected, b.ToSlice())
+		for _, v := range values {
+			testutils.Assert(t, "b.Contains(v)", true, b.Contains(v))
+		}
+		testutils.Assert(t, "b.Contains(1 << 62)", false, b.Contains(1 << 62))
+	})
+
+	t.Run("Concurrent", func(t *testing.T) {
+		b := NewEmpty64()
+		testutils.ConcurrentOperations(t, 10, 1000, func() error {
+			b.Add(uint64(rand.Intn(4)) << 32 | uint64(rand.Intn(20000)))
+			return nil
+		})
+		if b.Size() > 10000 || b.Size() == 0 {
+			t.Fatalf("Unexpected size: %d", b.Size())
+		}
+	})
+}
+
+func TestRemove64(t *testing.T) {
+	values := randomValues64(2, 20000)
+	b := NewFromSlice64(values)
+	reference := set.NewFromSlice(values)
+	for _, v := range values[:15000] {
+		b.Remove(v)
+		reference.Remove(v)
+	}
+	testutils.Assert(t, "b.Size()", reference.Size(), b.Size())
+	for _, v := range values {
+		testutils.Assert(t, "b.Contains(v)", reference.Contains(v), b.Contains(v))
+	}
+	for _, v := range values {
+		b.Remove(v)
+	}
+	testutils.Assert(t, "b.IsEmpty()", true, b.IsEmpty())
+	testutils.Assert(t, "len(b.keys)", 0, len(b.keys))
+}
+
+func TestBinaryOperations64(t *testing.T) {
+	valuesA := randomValues64(3, 30000)
+	valuesB := append(randomValues64(4, 300), valuesA[:1000]...)
+	valuesB = append(valuesB, 7 << 40)
+	a := NewFromSlice64(valuesA)
+	b := NewFromSlice64(valuesB)
+	setA := set.NewFromSlice(valuesA)
+	setB := set.NewFromSlice(valuesB)
+	check := func(t *testing.T, got *Bitmap64, expected *set.Set[uint64]) {
+		testutils.AssertSlices(t, sortedUnique64(expected.ToSlice()), got.ToSlice())
+	}
+	t.Run("And", func(t *testing.T) {
+		check(t, a.And(b), setA.Intersection(setB))
+		check(t, b.And(a), setA.Intersection(setB))
+	})
+	t.Run("Or", func(t *testing.T) {
+		check(t, a.Or(b), setA.Union(setB))
+	})
+	t.Run("AndNot", func(t *testing.T) {
+		check(t, a.AndNot(b), setA.Difference(setB))
+		check(t, b.AndNot(a), setB.Difference(setA))
+	})
+	t.Run("Self", func(t *testing.T) {
+		testutils.Assert(t, "a.And(a).Size()", a.Size(), a.And(a).Size())
+		testutils.Assert(t, "a.AndNot(a).IsEmpty()", true, a.AndNot(a).IsEmpty())
+	})
+}
+
+func TestAll64(t *testing.T) {
+	values := randomValues64(5, 1000)
+	b := NewFromSlice64(values)
+	testutils.AssertSlices(t, sortedUnique64(values), slices.Collect(b.All()))
+	for v := range b.All() {
+		b.Remove(v)
+	}
+	testutils.Assert(t, "b.Size()", 0, b.Size())
+}
+
+func TestCopy64(t *testing.T) {
+	b := NewFromSlice64([]uint64{1, 1 << 40})
+	c := b.Copy()
+	c.Add(2)
+	b.Clear()
+	testutils.Assert(t, "b.IsEmpty()", true, b.IsEmpty())
+	testutils.AssertSlices(t, []uint64{1, 2, 1 << 40}, c.ToSlice())
+}
+
+func TestString64(t *testing.T) {
+	b := NewFromSlice64([]uint64{3, 1, 1 << 40, 2})
+	testutils.Assert(t, "b.String()", "[1 2 3 1099511627776]", b.String())
+	testutils.Assert(t, "b.StringN(2)", "[1 2 … (+2 more)]", b.StringN(2))
+	b.SetStringLimit(3)
+	testutils.Assert(t, "b.String()", "[1 2 3 … (+1 more)]", b.String())
+}
+
+func TestRunOptimize64(t *testing.T) {
+	b := NewEmpty64()
+	for v := uint64(1 << 33); v < 1 << 33 + 60000; v++ {
+		b.Add(v)
+	}
+	before := b.MemUsage()
+	b.RunOptimize()
+	if b.MemUsage() >= before {
+		t.Fatalf("Expected RunOptimize to shrink a dense range, instead got: %d >= %d", b.MemUsage(), before)
+	}
+	testutils.Assert(t, "b.Size()", 60000, b.Size())
+	testutils.Assert(t, "b.Contains(1 << 33)", true, b.Contains(1 << 33))
+}
@@ -0,0 +1,169 @@
+package roaring
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/davidpogosian/ds/set"
+	"github.com/davidpogosian/ds/testutils"
+)
+
+// randomValues returns n random values spread over a few containers, some dense enough
+// to become bitmap containers.
+func randomValues(seed int64, n int) []uint32 {
+	rng := rand.New(rand.NewSource(seed))
+	values := make([]uint32, n)
+	for i := range values {
+		values[i] = uint32(rng.Intn(4)) << 16 | uint32(rng.Intn(1 << 16))
+	}
+	return values
+}
+
+// sortedUnique returns the distinct values in increasing order.
+func sortedUnique(values []uint32) []uint32 {
+	values = slices.Clone(values)
+	slices.Sort(values)
+	return slices.Compact(values)
+}
+
+func TestAdd(t *testing.T) {
+	t.Run("Sequential", func(t *testing.T) {
+		for _, n := range []int{10, 5000, 50000} {
+			values := randomValues(int64(n), n)
+			b := NewFromSlice(values)
+			expected := sortedUnique(values)
+			testutils.Assert(t, "b.Size()", len(expected), b.Size())
+			testutils.AssertSlices(t, expected, b.ToSlice())
+			for _, v := range values {
+				testutils.Assert(t, "b.Contains(v)", true, b.Contains(v))
+			}
+		}
+	})
+
+	t.Run("Concurrent", func(t *testing.T) {
+		b := NewEmpty()
+		testutils.ConcurrentOperations(t, 10, 1000, func() error {
+			b.Add(uint32(rand.Intn(20000)))
+			return nil
+		})
+		if b.Size() > 10000 || b.Size() == 0 {
+			t.Fatalf("Unexpected size: %d", b.Size())
+		}
+	})
+}
+
+func TestRemove(t *testing.T) {
+	values := randomValues(1, 20000)
+	b := NewFromSlice(values)
+	reference := set.NewFromSlice(values)
+	for _, v := range values[:15000] {
+		b.Remove(v)
+		reference.Remove(v)
+	}
+	testutils.Assert(t, "b.Size()", reference.Size(), b.Size())
+	for _, v := range values {
+		testutils.Assert(t, "b.Contains(v)", reference.Contains(v), b.Contains(v))
+	}
+	for _, v := range values {
+		b.Remove(v)
+	}
+	testutils.Assert(t, "b.IsEmpty()", true, b.IsEmpty())
+}
+
+func TestBinaryOperations(t *testing.T) {
+	valuesA := randomValues(2, 30000)
+	valuesB := append(randomValues(3, 300), valuesA[:1000]...)
+	a := NewFromSlice(valuesA)
+	b := NewFromSlice(valuesB)
+	setA := set.NewFromSlice(valuesA)
+	setB := set.NewFromSlice(valuesB)
+	check := func(t *testing.T, got *Bitmap, expected *set.Set[uint32]) {
+		testutils.AssertSlices(t, sortedUnique(expected.ToSlice()), got.ToSlice())
+	}
+	t.Run("And", func(t *testing.T) {
+		check(t, a.And(b), setA.Intersection(setB))
+		check(t, b.And(a), setA.Intersection(setB))
+	})
+	t.Run("Or", func(t *testing.T) {
+		check(t, a.Or(b), setA.Union(setB))
+	})
+	t.Run("AndNot", func(t *testing.T) {
+		check(t, a.AndNot(b), setA.Difference(setB))
+		check(t, b.AndNot(a), setB.Difference(setA))
+	})
+	t.Run("Self", func(t *testing.T) {
+		testutils.Assert(t, "a.And(a).Size()", a.Size(), a.And(a).Size())
+		testutils.Assert(t, "a.AndNot(a).Size()", 0, a.AndNot(a).Size())
+	})
+}
+
+func TestRunOptimize(t *testing.T) {
+	b := NewEmpty()
+	for v := uint32(1000); v < 60000; v++ {
+		b.Add(v)
+	}
+	before := b.MemUsage()
+	b.RunOptimize()
+	if b.MemUsage() >= before {
+		t.Fatalf("Expected RunOptimize to shrink a dense range, instead got: %d >= %d", b.MemUsage(), before)
+	}
+	testutils.Assert(t, "b.Size()", 59000, b.Size())
+	testutils.Assert(t, "b.Contains(999)", false, b.Contains(999))
+	testutils.Assert(t, "b.Contains(1000)", true, b.Contains(1000))
+	testutils.Assert(t, "b.Contains(59999)", true, b.Contains(59999))
+	b.Remove(30000)
+	b.Add(70000)
+	testutils.Assert(t, "b.Size()", 59000, b.Size())
+	testutils.Assert(t, "b.Contains(30000)", false, b.Contains(30000))
+}
+
+func TestAll(t *testing.T) {
+	values := randomValues(4, 1000)
+	b := NewFromSlice(values)
+	testutils.AssertSlices(t, sortedUnique(values), slices.Collect(b.All()))
+	for v := range b.All() {
+		b.Remove(v)
+	}
+	testutils.Assert(t, "b.Size()", 0, b.Size())
+}
+
+func TestMarshalBinary(t *testing.T) {
+	values := randomValues(5, 30000)
+	b := NewFromSlice(values)
+	for v := uint32(10 << 16); v < 10 << 16 + 5000; v++ {
+		b.Add(v)
+	}
+	b.RunOptimize()
+	data, err := b.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	restored := NewEmpty()
+	if err := restored.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	testutils.AssertSlices(t, b.ToSlice(), restored.ToSlice())
+	if err := restored.UnmarshalBinary(data[:len(data) - 1]); err == nil {
+		t.Fatal("Decoded a truncated Bitmap.")
+	}
+	if err := restored.UnmarshalBinary([]byte{0xff, 0xff, 0xff, 0xff}); err == nil {
+		t.Fatal("Decoded a Bitmap with a bogus container count.")
+	}
+	testutils.Assert(t, "restored.Size()", b.Size(), restored.Size())
+}
+
+func TestString(t *testing.T) {
+	b := NewFromSlice([]uint32{3, 1, 70000, 2})
+	testutils.Assert(t, "b.String()", "[1 2 3 70000]", b.String())
+	testutils.Assert(t, "b.StringN(2)", "[1 2 … (+2 more)]", b.StringN(2))
+}
+
+func TestMemUsage(t *testing.T) {
+	values := randomValues(6, 200000)
+	b := NewFromSlice(values)
+	s := set.NewFromSlice(values)
+	if b.MemUsage() * 4 > s.MemUsage() {
+		t.Fatalf("Expected the Bitmap to be much smaller than a Set, instead got: %d vs %d", b.MemUsage(), s.MemUsage())
+	}
+}
@@ -2,33 +2,91 @@
 package set
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
-	"strings"
+	"iter"
+	"maps"
+	"slices"
 	"sync"
+	"unsafe"
+
+	"github.com/davidpogosian/ds/comparators"
+	"github.com/davidpogosian/ds/internal/format"
 )
 
 // Set struct represents a set.
 // Important: Set can only be used with types that have the comparable constraint.
 // Set stores items in a field of type map[T comparable]bool.
-// Set also has a field to keep track of its size, as well as a mutex for thread-safety.
+// Set also has a field to keep track of its size, a limit on the number of items
+// printed by String (0 means no limit), an optional comparator that makes iteration
+// sorted (see WithStableIteration), a flag selecting the JSON object form
+// (see WithJSONObject), as well as a mutex for thread-safety.
 type Set[T comparable] struct {
 	items map[T]bool
 	size int
+	stringLimit int
+	comparator comparators.Comparator[T]
+	jsonObject bool
 	mu sync.Mutex
 }
 
-// NewEmpty returns a pointer to a new empty Set.
-func NewEmpty[T comparable]() *Set[T] {
-	return &Set[T]{items: make(map[T]bool)}
+// Option configures a Set at construction.
+type Option[T comparable] func(*Set[T])
+
+// WithStableIteration makes String, StringN, ToSlice and All return the items of the Set
+// sorted by the comparator, e.g. for reproducible test golden files and stable diffs.
+// This costs a sort on every call. Without it, the order is arbitrary and may change
+// between calls. Sets created from this Set, e.g. by Copy or Union, keep the option.
+func WithStableIteration[T comparable](comparator comparators.Comparator[T]) Option[T] {
+	return func(s *Set[T]) {
+		s.comparator = comparator
+	}
+}
+
+// WithJSONObject makes MarshalJSON encode the Set as a JSON object mapping every item
+// to true, e.g. {"a":true,"b":true}, instead of the default array form ["a","b"].
+// Like map keys in encoding/json, the items must be strings, integers or implement
+// encoding.TextMarshaler. Sets created from this Set, e.g. by Copy or Union, keep the option.
+func WithJSONObject[T comparable]() Option[T] {
+	return func(s *Set[T]) {
+		s.jsonObject = true
+	}
 }
 
-// NewFromSlice returns a pointer to a new Set initialized with a slice.
-func NewFromSlice[T comparable](slice []T) *Set[T] {
-	s := Set[T]{items: make(map[T]bool)}
+// NewEmpty returns a pointer to a new empty Set configured with the options.
+func NewEmpty[T comparable](options ...Option[T]) *Set[T] {
+	s := &Set[T]{items: make(map[T]bool)}
+	for _, option := range options {
+		option(s)
+	}
+	return s
+}
+
+// NewFromSlice returns a pointer to a new Set initialized with a slice and configured with the options.
+func NewFromSlice[T comparable](slice []T, options ...Option[T]) *Set[T] {
+	s := NewEmpty(options...)
 	for i := 0; i < len(slice); i++ {
 		s.Add(slice[i])
 	}
-	return &s
+	return s
+}
+
+// derive returns a pointer to a new empty Set with the same iteration order as this Set.
+func (s *Set[T]) derive() *Set[T] {
+	return &Set[T]{items: make(map[T]bool), comparator: s.comparator, jsonObject: s.jsonObject}
+}
+
+// Collect returns a pointer to a new Set holding the items of seq.
+func Collect[T comparable](seq iter.Seq[T]) *Set[T] {
+	s := NewEmpty[T]()
+	for item := range seq {
+		if !s.items[item] {
+			s.items[item] = true
+			s.size++
+		}
+	}
+	return s
 }
 
 // Add adds an item to the Set.
@@ -36,11 +94,30 @@ func NewFromSlice[T comparable](slice []T) *Set[T] {
 func (s *Set[T]) Add(newItem T) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	_, exists := s.items[newItem]
-	if !exists {
-		s.items[newItem] = true
-		s.size++
+	s.add(newItem)
+}
+
+// add adds an item to the Set and returns a bool indicating whether it was not already in it.
+func (s *Set[T]) add(newItem T) bool {
+	if _, exists := s.items[newItem]; exists {
+		return false
+	}
+	s.items[newItem] = true
+	s.size++
+	return true
+}
+
+// AddAll adds the items to the Set under a single lock and returns how many were not already in it.
+func (s *Set[T]) AddAll(items ...T) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	added := 0
+	for _, item := range items {
+		if s.add(item) {
+			added++
+		}
 	}
+	return added
 }
 
 // Remove removes an item from the Set.
@@ -48,35 +125,88 @@ func (s *Set[T]) Add(newItem T) {
 func (s *Set[T]) Remove(item T) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	_, exists := s.items[item]
-	if exists {
-		delete(s.items, item)
-		s.size--
+	s.remove(item)
+}
+
+// remove removes an item from the Set and returns a bool indicating whether it was in it.
+func (s *Set[T]) remove(item T) bool {
+	if _, exists := s.items[item]; !exists {
+		return false
+	}
+	delete(s.items, item)
+	s.size--
+	return true
+}
+
+// RemoveAll removes the items from the Set under a single lock and returns how many were in it.
+func (s *Set[T]) RemoveAll(items ...T) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	removed := 0
+	for _, item := range items {
+		if s.remove(item) {
+			removed++
+		}
 	}
+	return removed
 }
 
 // String returns the string representation of the Set.
+// The order of the items is arbitrary, unless the Set was created WithStableIteration,
+// which sorts them, e.g. for test assertions.
+// If a limit was set with SetStringLimit, only that many items are printed.
 func (s *Set[T]) String() string {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	str := "["
-	for key := range s.items {
-		str += fmt.Sprintf("%v ", key)
+	if s.stringLimit > 0 {
+		return s.stringN(s.stringLimit)
+	}
+	return s.stringN(-1)
+}
+
+// StringN returns the string representation of max arbitrary items of the Set
+// (the first max in order with WithStableIteration),
+// followed by "… (+N more)" if items were left out.
+// If max is negative, all items are printed.
+func (s *Set[T]) StringN(max int) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.stringN(max)
+}
+
+// stringN returns the string representation of max arbitrary items of the Set.
+func (s *Set[T]) stringN(max int) string {
+	limit := format.Limit(s.size, max)
+	if s.comparator != nil {
+		return format.Truncated(s.toSlice()[:limit], s.size - limit)
 	}
-	if strings.HasSuffix(str, " ") {
-		str = strings.TrimSuffix(str, " ")
+	items := make([]T, 0, limit)
+	for key := range s.items {
+		if len(items) == limit {
+			break
+		}
+		items = append(items, key)
 	}
-	return str + "]"
+	return format.Truncated(items, s.size - limit)
+}
+
+// SetStringLimit limits String to printing max items of the Set.
+// A max of 0 or less removes the limit.
+func (s *Set[T]) SetStringLimit(max int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stringLimit = max
 }
 
 // Copy returns a pointer to a copy of the Set.
 func (s *Set[T]) Copy() *Set[T] {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	copy := NewEmpty[T]()
+	copy := s.derive()
 	for key := range s.items {
 		copy.Add(key)
 	}
+	copy.stringLimit = s.stringLimit
 	return copy
 }
 
@@ -88,6 +218,91 @@ func (s *Set[T]) Contains(item T) bool {
 	return exists
 }
 
+// Pop removes and returns an arbitrary item of the Set.
+// If the Set was created WithStableIteration, the first item in sorted order
+// is removed instead, in O(n).
+// An error is returned if the Set is empty.
+func (s *Set[T]) Pop() (T, error) {
+	item, ok := s.TryPop()
+	if !ok {
+		return item, fmt.Errorf("Cannot pop from an empty Set.")
+	}
+	return item, nil
+}
+
+// TryPop is like Pop, but returns false instead of an error if the Set is empty.
+func (s *Set[T]) TryPop() (T, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	item, ok := s.first()
+	if ok {
+		s.remove(item)
+	}
+	return item, ok
+}
+
+// GetAny returns an arbitrary item of the Set without removing it.
+// If the Set was created WithStableIteration, the first item in sorted order
+// is returned instead, in O(n).
+// An error is returned if the Set is empty.
+func (s *Set[T]) GetAny() (T, error) {
+	item, ok := s.TryGetAny()
+	if !ok {
+		return item, fmt.Errorf("Cannot get an item of an empty Set.")
+	}
+	return item, nil
+}
+
+// TryGetAny is like GetAny, but returns false instead of an error if the Set is empty.
+func (s *Set[T]) TryGetAny() (T, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.first()
+}
+
+// first returns the first item of the Set in iteration order, and false if the Set is empty.
+// Without a comparator, that is whichever item the map yields first.
+func (s *Set[T]) first() (T, bool) {
+	var first T
+	found := false
+	for item := range s.items {
+		if s.comparator == nil {
+			return item, true
+		}
+		if !found || s.comparator(item, first) < 0 {
+			first = item
+			found = true
+		}
+	}
+	return first, found
+}
+
+// ContainsAll returns a bool indicating whether all of the items are in the Set,
+// checked under a single lock. It returns true if no items are given.
+func (s *Set[T]) ContainsAll(items ...T) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, item := range items {
+		if _, exists := s.items[item]; !exists {
+			return false
+		}
+	}
+	return true
+}
+
+// ContainsAny returns a bool indicating whether any of the items is in the Set,
+// checked under a single lock. It returns false if no items are given.
+func (s *Set[T]) ContainsAny(items ...T) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, item := range items {
+		if _, exists := s.items[item]; exists {
+			return true
+		}
+	}
+	return false
+}
+
 // Size returns the number of items in the Set as an int.
 func (s *Set[T]) Size() int {
 	s.mu.Lock()
@@ -111,30 +326,121 @@ func (s *Set[T]) Clear() {
 }
 
 // ToSlice returns the Set as a slice.
+// The order is arbitrary, unless the Set was created WithStableIteration.
 func (s *Set[T]) ToSlice() []T {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	return s.toSlice()
+}
+
+// ToSortedSlice returns the items of the Set as a slice sorted by comparator,
+// e.g. for deterministic output from a Set created without WithStableIteration.
+func (s *Set[T]) ToSortedSlice(comparator comparators.Comparator[T]) []T {
+	slice := s.ToSlice()
+	slices.SortFunc(slice, comparator)
+	return slice
+}
+
+// toSlice returns the items of the Set as a new slice,
+// sorted if the Set has a comparator.
+func (s *Set[T]) toSlice() []T {
 	slice := make([]T, s.size)
 	i := 0
 	for key := range s.items {
 		slice[i] = key
 		i++
 	}
+	if s.comparator != nil {
+		slices.SortFunc(slice, s.comparator)
+	}
 	return slice
 }
 
+// All returns an iterator over the items of the Set in no particular order,
+// unless the Set was created WithStableIteration.
+// It iterates over a snapshot taken when iteration starts, so the loop body
+// may call methods on the Set (including ones that modify it).
+func (s *Set[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, item := range s.ToSlice() {
+			if !yield(item) {
+				return
+			}
+		}
+	}
+}
+
+// ForEach calls f on every item of the Set under the lock, stopping early if f returns false,
+// so no item is added or removed while it runs. Items are visited in no particular order,
+// unless the Set was created WithStableIteration. f must not call methods on the Set.
+// Use All to iterate without holding the lock.
+func (s *Set[T]) ForEach(f func(T) bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.comparator != nil {
+		for _, item := range s.toSlice() {
+			if !f(item) {
+				return
+			}
+		}
+		return
+	}
+	for item := range s.items {
+		if !f(item) {
+			return
+		}
+	}
+}
+
+// Filter returns a pointer to a new Set holding the items of this Set for which pred
+// returns true. pred is called under the lock, so it must not call methods on the Set.
+func (s *Set[T]) Filter(pred func(T) bool) *Set[T] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	filtered := s.derive()
+	for item := range s.items {
+		if pred(item) {
+			filtered.add(item)
+		}
+	}
+	return filtered
+}
+
+// Map returns a pointer to a new Set holding the results of calling f on every item of s.
+// Items mapped to the same result are merged, so the new Set may be smaller than s.
+// f is called under the lock of s, so it must not call methods on s.
+// The options of s are not carried over, since they apply to a different type.
+func Map[T, U comparable](s *Set[T], f func(T) U) *Set[U] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	mapped := NewEmpty[U]()
+	for item := range s.items {
+		mapped.add(f(item))
+	}
+	return mapped
+}
+
+// snapshot returns a copy of the items of the Set.
+// Binary operations compare one Set against a snapshot of the other, so they
+// never hold two locks at once: s1.Union(s2) and s2.Union(s1) can run
+// concurrently, and s.Union(s) does not deadlock.
+func (s *Set[T]) snapshot() map[T]bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return maps.Clone(s.items)
+}
+
 // Union returns a pointer to a new Set that is the union of this Set
 // and the Set provided as an argument.
 func (s1 *Set[T]) Union(s2 *Set[T]) *Set[T] {
+	other := s2.snapshot()
 	s1.mu.Lock()
 	defer s1.mu.Unlock()
-	s2.mu.Lock()
-	defer s2.mu.Unlock()
-	union := NewEmpty[T]()
+	union := s1.derive()
 	for key := range s1.items {
 		union.Add(key)
 	}
-	for key := range s2.items {
+	for key := range other {
 		union.Add(key)
 	}
 	return union
@@ -143,13 +449,12 @@ func (s1 *Set[T]) Union(s2 *Set[T]) *Set[T] {
 // Intersection returns a pointer to a new Set that is the intersection of
 // this Set and the Set provided as an argument.
 func (s1 *Set[T]) Intersection(s2 *Set[T]) *Set[T] {
+	other := s2.snapshot()
 	s1.mu.Lock()
 	defer s1.mu.Unlock()
-	s2.mu.Lock()
-	defer s2.mu.Unlock()
-	intersection := NewEmpty[T]()
+	intersection := s1.derive()
 	for key := range s1.items {
-		if _, exists := s2.items[key]; exists {
+		if _, exists := other[key]; exists {
 			intersection.Add(key)
 		}
 	}
@@ -159,28 +464,65 @@ func (s1 *Set[T]) Intersection(s2 *Set[T]) *Set[T] {
 // Difference returns a pointer to a new Set that is
 // the difference between this Set and the Set provided as an argument.
 func (s1 *Set[T]) Difference(s2 *Set[T]) *Set[T] {
+	other := s2.snapshot()
 	s1.mu.Lock()
 	defer s1.mu.Unlock()
-	s2.mu.Lock()
-	defer s2.mu.Unlock()
-	difference := NewEmpty[T]()
+	difference := s1.derive()
 	for key := range s1.items {
-		if _, exists := s2.items[key]; !exists {
+		if _, exists := other[key]; !exists {
 			difference.Add(key)
 		}
 	}
 	return difference
 }
 
+// SymmetricDifference returns a pointer to a new Set holding the items
+// that are in exactly one of this Set and the Set provided as an argument.
+func (s1 *Set[T]) SymmetricDifference(s2 *Set[T]) *Set[T] {
+	other := s2.snapshot()
+	s1.mu.Lock()
+	defer s1.mu.Unlock()
+	symmetricDifference := s1.derive()
+	for key := range s1.items {
+		if _, exists := other[key]; !exists {
+			symmetricDifference.Add(key)
+		}
+	}
+	for key := range other {
+		if _, exists := s1.items[key]; !exists {
+			symmetricDifference.Add(key)
+		}
+	}
+	return symmetricDifference
+}
+
+// IsDisjoint returns a bool that indicates if this Set and
+// the Set provided as an argument have no items in common.
+func (s1 *Set[T]) IsDisjoint(s2 *Set[T]) bool {
+	other := s2.snapshot()
+	s1.mu.Lock()
+	defer s1.mu.Unlock()
+	// Iterate over the smaller set.
+	small, large := s1.items, other
+	if len(small) > len(large) {
+		small, large = large, small
+	}
+	for key := range small {
+		if _, exists := large[key]; exists {
+			return false
+		}
+	}
+	return true
+}
+
 // IsSubset returns a bool that indicates if this Set is a
 // subset of the Set provided as an argument.
 func (s1 *Set[T]) IsSubset(s2 *Set[T]) bool {
+	other := s2.snapshot()
 	s1.mu.Lock()
 	defer s1.mu.Unlock()
-	s2.mu.Lock()
-	defer s2.mu.Unlock()
 	for key := range s1.items {
-		if _, exists := s2.items[key]; !exists {
+		if _, exists := other[key]; !exists {
 			return false
 		}
 	}
@@ -190,11 +532,10 @@ func (s1 *Set[T]) IsSubset(s2 *Set[T]) bool {
 // IsSuperset returns a bool that indicates if this Set is a
 // superset of the Set provided as an argument.
 func (s1 *Set[T]) IsSuperset(s2 *Set[T]) bool {
+	other := s2.snapshot()
 	s1.mu.Lock()
 	defer s1.mu.Unlock()
-	s2.mu.Lock()
-	defer s2.mu.Unlock()
-	for key := range s2.items {
+	for key := range other {
 		if _, exists := s1.items[key]; !exists {
 			return false
 		}
@@ -205,17 +546,90 @@ func (s1 *Set[T]) IsSuperset(s2 *Set[T]) bool {
 // Equals returns a bool that indicates if this Set is
 // equal to the Set provided as an argument.
 func (s1 *Set[T]) Equals(s2 *Set[T]) bool {
+	other := s2.snapshot()
 	s1.mu.Lock()
 	defer s1.mu.Unlock()
-	s2.mu.Lock()
-	defer s2.mu.Unlock()
-	if s1.size != s2.size {
+	if s1.size != len(other) {
 		return false
 	}
 	for key := range s1.items {
-		if _, exists := s2.items[key]; !exists {
+		if _, exists := other[key]; !exists {
 			return false
 		}
 	}
 	return true
 }
+
+// MarshalJSON returns the JSON encoding of the Set: an array of its items in the order
+// of ToSlice, or an object mapping every item to true if the Set was created WithJSONObject.
+func (s *Set[T]) MarshalJSON() ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.jsonObject {
+		return json.Marshal(s.items)
+	}
+	return json.Marshal(s.toSlice())
+}
+
+// UnmarshalJSON replaces the items of the Set with the ones decoded from data.
+// Both forms written by MarshalJSON are accepted whatever the options of the Set:
+// an array of items (duplicates are ignored), or an object whose keys are items,
+// where keys mapped to false or null are left out. JSON null leaves the Set unchanged.
+func (s *Set[T]) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	items := make(map[T]bool)
+	if len(data) > 0 && data[0] == '{' {
+		var object map[T]*bool
+		if err := json.Unmarshal(data, &object); err != nil {
+			return fmt.Errorf("Cannot unmarshal a Set from a JSON object: %w", err)
+		}
+		for item, value := range object {
+			if value != nil && *value {
+				items[item] = true
+			}
+		}
+	} else {
+		var array []T
+		if err := json.Unmarshal(data, &array); err != nil {
+			return fmt.Errorf("Cannot unmarshal a Set from a JSON array: %w", err)
+		}
+		for _, item := range array {
+			items[item] = true
+		}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.items = items
+	s.size = len(items)
+	return nil
+}
+
+// MemUsage returns an approximation of the number of bytes used by the Set.
+// The map is estimated from its entry size, average load factor and per-entry
+// bookkeeping, since Go does not expose the real bucket layout.
+// Memory referenced by the items themselves (e.g. the contents of strings)
+// is not counted; use MemUsageFunc for that.
+func (s *Set[T]) MemUsage() int64 {
+	return s.MemUsageFunc(nil)
+}
+
+// MemUsageFunc is like MemUsage, but also adds the result of calling size on every item.
+// The size function should return the bytes referenced by an item beyond its own fixed size.
+// If size is nil, it behaves exactly like MemUsage.
+func (s *Set[T]) MemUsageFunc(size func(T) int64) int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var zeroValue T
+	// Maps keep one control byte per slot and are at most 7/8 full.
+	entry := int64(unsafe.Sizeof(zeroValue)) + int64(unsafe.Sizeof(true)) + 1
+	total := int64(unsafe.Sizeof(*s)) + int64(s.size) * entry * 8 / 7
+	if size != nil {
+		for key := range s.items {
+			total += size(key)
+		}
+	}
+	return total
+}
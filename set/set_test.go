@@ -1,8 +1,14 @@
 package set
 
 import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/davidpogosian/ds/comparators"
 	"github.com/davidpogosian/ds/testutils"
 )
 
@@ -39,9 +45,15 @@ func TestString(t *testing.T) {
 	})
 
 	t.Run("NotEmpty", func(t *testing.T) {
-		s := NewFromSlice([]int{1, 2, 3})
+		// Without WithStableIteration the order is arbitrary, so it cannot be asserted.
+		s := NewFromSlice([]int{1, 2, 3}, WithStableIteration(comparators.ComparatorInt))
 		testutils.Assert(t, "s.String()", "[1 2 3]", s.String())
 	})
+
+	t.Run("Single", func(t *testing.T) {
+		s := NewFromSlice([]int{1})
+		testutils.Assert(t, "s.String()", "[1]", s.String())
+	})
 }
 
 func TestCopy(t *testing.T) {
@@ -106,6 +118,14 @@ func TestToSlice(t *testing.T) {
 	})
 }
 
+func TestToSortedSlice(t *testing.T) {
+	s := NewFromSlice([]int{3, 1, 2})
+	testutils.AssertSlices(t, []int{1, 2, 3}, s.ToSortedSlice(comparators.ComparatorInt))
+	descending := func(a, b int) int { return comparators.ComparatorInt(b, a) }
+	testutils.AssertSlices(t, []int{3, 2, 1}, s.ToSortedSlice(descending))
+	testutils.AssertSlices(t, []int{}, NewEmpty[int]().ToSortedSlice(comparators.ComparatorInt))
+}
+
 func TestAdd(t *testing.T) {
 	t.Run("Sequential", func(t *testing.T) {
 		s := NewEmpty[int]()
@@ -122,6 +142,134 @@ func TestRemove(t *testing.T) {
 	})
 }
 
+func TestAddAll(t *testing.T) {
+	t.Run("Sequential", func(t *testing.T) {
+		s := NewFromSlice([]int{1, 2})
+		testutils.Assert(t, "s.AddAll(2, 3, 4, 4)", 2, s.AddAll(2, 3, 4, 4))
+		testutils.Assert(t, "s.Size()", 4, s.Size())
+		testutils.Assert(t, "s.AddAll()", 0, s.AddAll())
+	})
+	t.Run("Concurrent", func(t *testing.T) {
+		s := NewEmpty[int]()
+		testutils.ConcurrentOperations(t, 10, 100, func() error {
+			s.AddAll(1, 2, 3)
+			return nil
+		})
+		testutils.Assert(t, "s.Size()", 3, s.Size())
+	})
+}
+
+func TestRemoveAll(t *testing.T) {
+	s := NewFromSlice([]int{1, 2, 3})
+	testutils.Assert(t, "s.RemoveAll(2, 3, 4, 3)", 2, s.RemoveAll(2, 3, 4, 3))
+	testutils.Assert(t, "s.Size()", 1, s.Size())
+	testutils.Assert(t, "s.Contains(1)", true, s.Contains(1))
+}
+
+func TestContainsAllAndAny(t *testing.T) {
+	s := NewFromSlice([]int{1, 2, 3})
+	testutils.Assert(t, "s.ContainsAll(1, 3)", true, s.ContainsAll(1, 3))
+	testutils.Assert(t, "s.ContainsAll(1, 4)", false, s.ContainsAll(1, 4))
+	testutils.Assert(t, "s.ContainsAll()", true, s.ContainsAll())
+	testutils.Assert(t, "s.ContainsAny(4, 3)", true, s.ContainsAny(4, 3))
+	testutils.Assert(t, "s.ContainsAny(4, 5)", false, s.ContainsAny(4, 5))
+	testutils.Assert(t, "s.ContainsAny()", false, s.ContainsAny())
+}
+
+func TestPop(t *testing.T) {
+	t.Run("Sequential", func(t *testing.T) {
+		s := NewFromSlice([]int{1, 2, 3})
+		popped := NewEmpty[int]()
+		for i := 0; i < 3; i++ {
+			item, err := s.Pop()
+			if err != nil {
+				t.Fatal(err)
+			}
+			popped.Add(item)
+		}
+		testutils.Assert(t, "popped.Equals(NewFromSlice([]int{1, 2, 3}))", true, popped.Equals(NewFromSlice([]int{1, 2, 3})))
+		testutils.Assert(t, "s.IsEmpty()", true, s.IsEmpty())
+		if _, err := s.Pop(); err == nil {
+			t.Fatal("Popped from an empty Set.")
+		}
+		if _, ok := s.TryPop(); ok {
+			t.Fatal("Popped from an empty Set.")
+		}
+	})
+	t.Run("Stable", func(t *testing.T) {
+		s := NewFromSlice([]int{3, 1, 2}, WithStableIteration(comparators.ComparatorInt))
+		for _, expected := range []int{1, 2, 3} {
+			item, ok := s.TryPop()
+			testutils.Assert(t, "ok", true, ok)
+			testutils.Assert(t, "item", expected, item)
+		}
+	})
+	t.Run("Concurrent", func(t *testing.T) {
+		s := NewEmpty[int]()
+		for i := 0; i < 1000; i++ {
+			s.Add(i)
+		}
+		popped := NewEmpty[int]()
+		testutils.ConcurrentOperations(t, 10, 100, func() error {
+			item, err := s.Pop()
+			if err != nil {
+				return err
+			}
+			popped.Add(item)
+			return nil
+		})
+		testutils.Assert(t, "popped.Size()", 1000, popped.Size())
+	})
+}
+
+func TestGetAny(t *testing.T) {
+	s := NewEmpty[string]()
+	if _, err := s.GetAny(); err == nil {
+		t.Fatal("Got an item of an empty Set.")
+	}
+	s.AddAll("a", "b")
+	item, err := s.GetAny()
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutils.Assert(t, "s.Contains(item)", true, s.Contains(item))
+	testutils.Assert(t, "s.Size()", 2, s.Size())
+	stable := NewFromSlice([]string{"b", "c", "a"}, WithStableIteration(comparators.ComparatorString))
+	item, ok := stable.TryGetAny()
+	testutils.Assert(t, "ok", true, ok)
+	testutils.Assert(t, "item", "a", item)
+}
+
+func TestForEach(t *testing.T) {
+	s := NewFromSlice([]int{3, 1, 2}, WithStableIteration(comparators.ComparatorInt))
+	visited := []int{}
+	s.ForEach(func(item int) bool {
+		visited = append(visited, item)
+		return true
+	})
+	testutils.AssertSlices(t, []int{1, 2, 3}, visited)
+	count := 0
+	NewFromSlice([]int{1, 2, 3}).ForEach(func(item int) bool {
+		count++
+		return count < 2
+	})
+	testutils.Assert(t, "count", 2, count)
+}
+
+func TestFilter(t *testing.T) {
+	s := NewFromSlice([]int{1, 2, 3, 4, 5}, WithStableIteration(comparators.ComparatorInt))
+	even := s.Filter(func(item int) bool { return item % 2 == 0 })
+	testutils.AssertSlices(t, []int{2, 4}, even.ToSlice())
+	testutils.Assert(t, "s.Size()", 5, s.Size())
+}
+
+func TestMap(t *testing.T) {
+	s := NewFromSlice([]int{-2, -1, 1, 3})
+	mapped := Map(s, func(item int) string { return fmt.Sprint(item * item) })
+	testutils.Assert(t, "mapped.Equals(NewFromSlice([]string{\"1\", \"4\", \"9\"}))", true, mapped.Equals(NewFromSlice([]string{"1", "4", "9"})))
+	testutils.Assert(t, "mapped.Size()", 3, mapped.Size())
+}
+
 func TestUnion(t *testing.T) {
 	t.Run("NoIntersection", func(t *testing.T) {
 		s1 := NewFromSlice([]int{1, 2, 3})
@@ -199,6 +347,36 @@ func TestDifference(t *testing.T) {
 	})
 }
 
+func TestSymmetricDifference(t *testing.T) {
+	t.Run("NoIntersection", func(t *testing.T) {
+		s1 := NewFromSlice([]int{1, 2, 3})
+		s2 := NewFromSlice([]int{4, 5, 6})
+		symmetricDifference := s1.SymmetricDifference(s2)
+		testutils.Assert(t, "symmetricDifference.Size()", 6, symmetricDifference.Size())
+	})
+
+	t.Run("SomeIntersection", func(t *testing.T) {
+		s1 := NewFromSlice([]int{1, 2, 3})
+		s2 := NewFromSlice([]int{3, 4, 5})
+		symmetricDifference := s1.SymmetricDifference(s2)
+		testutils.Assert(t, "symmetricDifference.Equals(NewFromSlice([]int{1, 2, 4, 5}))", true, symmetricDifference.Equals(NewFromSlice([]int{1, 2, 4, 5})))
+	})
+
+	t.Run("FullIntersection", func(t *testing.T) {
+		s1 := NewFromSlice([]int{1, 2, 3})
+		symmetricDifference := s1.SymmetricDifference(s1)
+		testutils.Assert(t, "symmetricDifference.Size()", 0, symmetricDifference.Size())
+	})
+}
+
+func TestIsDisjoint(t *testing.T) {
+	s1 := NewFromSlice([]int{1, 2, 3})
+	testutils.Assert(t, "s1.IsDisjoint(NewFromSlice([]int{4, 5, 6, 7}))", true, s1.IsDisjoint(NewFromSlice([]int{4, 5, 6, 7})))
+	testutils.Assert(t, "s1.IsDisjoint(NewFromSlice([]int{3, 4}))", false, s1.IsDisjoint(NewFromSlice([]int{3, 4})))
+	testutils.Assert(t, "s1.IsDisjoint(NewEmpty[int]())", true, s1.IsDisjoint(NewEmpty[int]()))
+	testutils.Assert(t, "s1.IsDisjoint(s1)", false, s1.IsDisjoint(s1))
+}
+
 func TestIsSubset(t *testing.T) {
 	t.Run("ProperSubset", func(t *testing.T) {
 		s := NewFromSlice([]int{1, 2, 3})
@@ -255,3 +433,239 @@ func TestEquals(t *testing.T) {
 		testutils.Assert(t, "equals", false, equals)
 	})
 }
+
+func TestStringN(t *testing.T) {
+	s := NewFromSlice([]int{1, 2, 3})
+	testutils.Assert(t, "len(s.StringN(0))", len("[… (+3 more)]"), len(s.StringN(0)))
+	testutils.Assert(t, "len(s.StringN(2))", len("[1 2 … (+1 more)]"), len(s.StringN(2)))
+	s.SetStringLimit(1)
+	testutils.Assert(t, "len(s.String())", len("[1 … (+2 more)]"), len(s.String()))
+}
+
+func TestMemUsage(t *testing.T) {
+	s := NewEmpty[string]()
+	empty := s.MemUsage()
+	s.Add("a")
+	s.Add("bb")
+	if s.MemUsage() <= empty {
+		t.Fatal("Expected MemUsage to grow after adding items.")
+	}
+	extra := s.MemUsageFunc(func(item string) int64 { return int64(len(item)) }) - s.MemUsage()
+	testutils.Assert(t, "extra", int64(3), extra)
+}
+
+func TestCollect(t *testing.T) {
+	s := Collect(slices.Values([]int{1, 2, 2, 3}))
+	testutils.Assert(t, "s.Size()", 3, s.Size())
+	testutils.Assert(t, "s.Contains(2)", true, s.Contains(2))
+}
+
+func TestAll(t *testing.T) {
+	s := NewFromSlice([]int{1, 2, 3})
+	items := slices.Sorted(s.All())
+	testutils.AssertSlices(t, []int{1, 2, 3}, items)
+	for item := range s.All() {
+		s.Remove(item)
+	}
+	testutils.Assert(t, "s.Size()", 0, s.Size())
+}
+
+func TestWithStableIteration(t *testing.T) {
+	s := NewFromSlice([]int{5, 3, 1, 4, 2}, WithStableIteration(comparators.ComparatorInt))
+	testutils.Assert(t, "s.String()", "[1 2 3 4 5]", s.String())
+	testutils.Assert(t, "s.StringN(2)", "[1 2 … (+3 more)]", s.StringN(2))
+	testutils.AssertSlices(t, []int{1, 2, 3, 4, 5}, s.ToSlice())
+	testutils.AssertSlices(t, []int{1, 2, 3, 4, 5}, slices.Collect(s.All()))
+
+	t.Run("Derived", func(t *testing.T) {
+		other := NewFromSlice([]int{9, 6, 1})
+		testutils.Assert(t, "s.Copy().String()", "[1 2 3 4 5]", s.Copy().String())
+		testutils.Assert(t, "s.Union(other).String()", "[1 2 3 4 5 6 9]", s.Union(other).String())
+		testutils.Assert(t, "s.Intersection(other).String()", "[1]", s.Intersection(other).String())
+		testutils.Assert(t, "s.Difference(other).String()", "[2 3 4 5]", s.Difference(other).String())
+	})
+}
+
+func TestBinaryOperationsConcurrent(t *testing.T) {
+	s1 := NewFromSlice([]int{1, 2, 3})
+	s2 := NewFromSlice([]int{2, 3, 4})
+	testutils.ConcurrentOperations(t, 10, 100, func() error {
+		if s1.Union(s2).Size() != 4 || s2.Union(s1).Size() != 4 {
+			return fmt.Errorf("Union has the wrong size.")
+		}
+		if s1.Equals(s2) || s2.IsSubset(s1) || s1.IsSuperset(s2) {
+			return fmt.Errorf("Sets compared equal.")
+		}
+		if s1.SymmetricDifference(s2).Size() != 2 || s2.IsDisjoint(s1) {
+			return fmt.Errorf("Sets have the wrong symmetric difference.")
+		}
+		return nil
+	})
+}
+
+func TestBinaryOperationsSelf(t *testing.T) {
+	s := NewFromSlice([]int{1, 2, 3})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		s.Union(s)
+		s.Intersection(s)
+		s.Difference(s)
+		s.SymmetricDifference(s)
+		s.IsDisjoint(s)
+		s.IsSubset(s)
+		s.IsSuperset(s)
+		s.Equals(s)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Binary operations of a Set with itself deadlocked.")
+	}
+	testutils.Assert(t, "s.Union(s).Size()", 3, s.Union(s).Size())
+	testutils.Assert(t, "s.Intersection(s).Size()", 3, s.Intersection(s).Size())
+	testutils.Assert(t, "s.Difference(s).Size()", 0, s.Difference(s).Size())
+	testutils.Assert(t, "s.IsDisjoint(s)", false, s.IsDisjoint(s))
+	testutils.Assert(t, "s.IsSubset(s)", true, s.IsSubset(s))
+	testutils.Assert(t, "s.Equals(s)", true, s.Equals(s))
+}
+
+func TestBinaryOperationsOppositeOrder(t *testing.T) {
+	// a.Op(b) and b.Op(a) run concurrently while both Sets are written to,
+	// which deadlocks if the two Sets are ever locked in caller order.
+	a := NewFromSlice([]int{1, 2, 3})
+	b := NewFromSlice([]int{3, 4, 5})
+	var waitGroup sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		waitGroup.Add(1)
+		go func() {
+			defer waitGroup.Done()
+			for j := 0; j < 200; j++ {
+				a.Add(6)
+				b.Remove(6)
+				a.Union(b)
+				b.Union(a)
+				a.IsSubset(b)
+				b.IsSubset(a)
+				a.Equals(b)
+				b.Equals(a)
+				a.Intersection(b)
+				b.Difference(a)
+			}
+		}()
+	}
+	done := make(chan struct{})
+	go func() {
+		waitGroup.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("Binary operations on two Sets in opposite order deadlocked.")
+	}
+}
+
+func TestJSON(t *testing.T) {
+	t.Run("Array", func(t *testing.T) {
+		s := NewFromSlice([]string{"b", "a"}, WithStableIteration(comparators.ComparatorString))
+		encoded, err := json.Marshal(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		testutils.Assert(t, "string(encoded)", `["a","b"]`, string(encoded))
+		decoded := NewEmpty[string]()
+		if err := json.Unmarshal(encoded, decoded); err != nil {
+			t.Fatal(err)
+		}
+		testutils.Assert(t, "decoded.Equals(s)", true, decoded.Equals(s))
+	})
+
+	t.Run("Object", func(t *testing.T) {
+		s := NewFromSlice([]string{"b", "a"}, WithJSONObject[string]())
+		encoded, err := json.Marshal(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		testutils.Assert(t, "string(encoded)", `{"a":true,"b":true}`, string(encoded))
+		var decoded Set[string]
+		if err := json.Unmarshal(encoded, &decoded); err != nil {
+			t.Fatal(err)
+		}
+		testutils.Assert(t, "decoded.Equals(s)", true, decoded.Equals(s))
+		decoded.Add("c")
+		testutils.Assert(t, "decoded.Size()", 3, decoded.Size())
+	})
+
+	t.Run("Tolerant", func(t *testing.T) {
+		s := NewFromSlice([]int{9})
+		if err := json.Unmarshal([]byte(`{"1":true,"2":false,"3":null}`), s); err != nil {
+			t.Fatal(err)
+		}
+		testutils.AssertSlices(t, []int{1}, s.ToSlice())
+		if err := json.Unmarshal([]byte(`[1,1,2]`), s); err != nil {
+			t.Fatal(err)
+		}
+		testutils.Assert(t, "s.Size()", 2, s.Size())
+		if err := json.Unmarshal([]byte(`"1"`), s); err == nil {
+			t.Fatal("Unmarshaled a Set from a JSON string.")
+		}
+		testutils.Assert(t, "s.Size()", 2, s.Size())
+	})
+}
+
+func TestMixedWorkload(t *testing.T) {
+	t.Run("Concurrent", func(t *testing.T) {
+		s := NewEmpty[int]()
+		result := testutils.RunWorkload(t, testutils.Workload{
+			Threads: 10,
+			Repetitions: 100,
+			Seed: 1,
+			Operations: []testutils.Operation{
+				{Name: "Contains", Weight: 8, Function: func() error {
+					s.Contains(1)
+					return nil
+				}},
+				{Name: "Add", Weight: 2, Function: func() error {
+					s.Add(1)
+					return nil
+				}},
+			},
+		})
+		testutils.Assert(t, "total", 1000, result.Counts["Contains"] + result.Counts["Add"])
+		if result.Counts["Contains"] < result.Counts["Add"] {
+			t.Fatalf("Expected mostly Contains, instead got: %v", result.Counts)
+		}
+		testutils.Assert(t, "s.Size()", 1, s.Size())
+	})
+
+	t.Run("Deterministic", func(t *testing.T) {
+		run := func() ([]int, map[string]int) {
+			s := NewEmpty[int]()
+			next := 0
+			result := testutils.RunWorkload(t, testutils.Workload{
+				Threads: 4,
+				Repetitions: 50,
+				Seed: 42,
+				Deterministic: true,
+				Operations: []testutils.Operation{
+					{Name: "Add", Weight: 3, Function: func() error {
+						s.Add(next)
+						next++
+						return nil
+					}},
+					{Name: "Remove", Weight: 1, Function: func() error {
+						s.Remove(next - 1)
+						return nil
+					}},
+				},
+			})
+			return slices.Sorted(s.All()), result.Counts
+		}
+		itemsA, countsA := run()
+		itemsB, countsB := run()
+		testutils.AssertSlices(t, itemsA, itemsB)
+		testutils.Assert(t, "countsB[\"Add\"]", countsA["Add"], countsB["Add"])
+		testutils.Assert(t, "countsB[\"Remove\"]", countsA["Remove"], countsB["Remove"])
+	})
+}
@@ -0,0 +1,64 @@
+package stack
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrDepthExceeded is returned by Guard.Enter when the depth limit has been reached.
+var ErrDepthExceeded = errors.New("Cannot enter beyond the depth limit of the Guard.")
+
+// Guard tracks recursion or nesting depth, e.g. in a recursive descent parser,
+// and fails once a limit is reached instead of letting the recursion run away.
+// Every successful Enter must be matched by an Exit. Each level records a frame
+// of type T (e.g. the name of the construct being parsed), which can be used
+// to explain where the limit was hit.
+// Guard is a thin wrapper around a bounded Stack, so it is thread-safe.
+type Guard[T any] struct {
+	frames *Stack[T]
+}
+
+// NewGuard returns a pointer to a new Guard that allows at most limit nested levels.
+// An error is returned if limit is not positive.
+func NewGuard[T any](limit int) (*Guard[T], error) {
+	// Guard never searches its frames, so it needs no comparator.
+	frames, err := newBounded[T](limit, nil)
+	if err != nil {
+		return nil, fmt.Errorf("Cannot create a Guard with a limit of %d.", limit)
+	}
+	return &Guard[T]{frames: frames}, nil
+}
+
+// Enter records a new level of nesting with the given frame.
+// ErrDepthExceeded is returned, and nothing is recorded, if the limit has been reached.
+func (g *Guard[T]) Enter(frame T) error {
+	if err := g.frames.Push(frame); err != nil {
+		return ErrDepthExceeded
+	}
+	return nil
+}
+
+// Exit leaves the innermost level of nesting and returns its frame.
+// An error is returned if no level has been entered.
+func (g *Guard[T]) Exit() (T, error) {
+	frame, err := g.frames.Pop()
+	if err != nil {
+		return frame, fmt.Errorf("Cannot exit a Guard that has not been entered.")
+	}
+	return frame, nil
+}
+
+// Depth returns the current number of nested levels.
+func (g *Guard[T]) Depth() int {
+	return g.frames.Size()
+}
+
+// Limit returns the maximum number of nested levels.
+func (g *Guard[T]) Limit() int {
+	return g.frames.bound
+}
+
+// Frames returns the frames of the current levels, from the outermost to the innermost.
+func (g *Guard[T]) Frames() []T {
+	return g.frames.ToSlice()
+}
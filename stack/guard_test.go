@@ -0,0 +1,85 @@
+package stack
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/davidpogosian/ds/testutils"
+)
+
+func TestNewGuard(t *testing.T) {
+	_, err := NewGuard[string](0)
+	if err == nil {
+		t.Fatal("Created a Guard with a limit of 0.")
+	}
+	g, err := NewGuard[string](3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutils.Assert(t, "g.Limit()", 3, g.Limit())
+	testutils.Assert(t, "g.Depth()", 0, g.Depth())
+}
+
+func TestGuard(t *testing.T) {
+	t.Run("Sequential", func(t *testing.T) {
+		g, err := NewGuard[string](2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := g.Enter("array"); err != nil {
+			t.Fatal(err)
+		}
+		if err := g.Enter("object"); err != nil {
+			t.Fatal(err)
+		}
+		if err := g.Enter("array"); !errors.Is(err, ErrDepthExceeded) {
+			t.Fatalf("Expected ErrDepthExceeded, instead got: %v", err)
+		}
+		testutils.AssertSlices(t, []string{"array", "object"}, g.Frames())
+		frame, err := g.Exit()
+		if err != nil {
+			t.Fatal(err)
+		}
+		testutils.Assert(t, "frame", "object", frame)
+		testutils.Assert(t, "g.Depth()", 1, g.Depth())
+		g.Exit()
+		if _, err := g.Exit(); err == nil {
+			t.Fatal("Exited a Guard that was not entered.")
+		}
+	})
+
+	t.Run("Recursion", func(t *testing.T) {
+		g, err := NewGuard[int](10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var descend func(level int) error
+		descend = func(level int) error {
+			if err := g.Enter(level); err != nil {
+				return err
+			}
+			defer g.Exit()
+			return descend(level + 1)
+		}
+		if err := descend(0); !errors.Is(err, ErrDepthExceeded) {
+			t.Fatalf("Expected ErrDepthExceeded, instead got: %v", err)
+		}
+		testutils.Assert(t, "g.Depth()", 0, g.Depth())
+	})
+
+	t.Run("Concurrent", func(t *testing.T) {
+		g, err := NewGuard[int](10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		testutils.ConcurrentOperations(t, 10, 100, func() error {
+			if err := g.Enter(1); err != nil {
+				return fmt.Errorf("Enter failed: %w", err)
+			}
+			_, err := g.Exit()
+			return err
+		})
+		testutils.Assert(t, "g.Depth()", 0, g.Depth())
+	})
+}
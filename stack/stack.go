@@ -2,17 +2,28 @@
 package stack
 
 import (
+	"errors"
 	"fmt"
+	"iter"
 	"sync"
+	"unsafe"
 
 	"github.com/davidpogosian/ds/comparators"
+	"github.com/davidpogosian/ds/internal/format"
 )
 
+// ErrFull is returned by Push when a bounded Stack already holds as many items as it can.
+var ErrFull = errors.New("Cannot push onto a full Stack.")
+
 // Stack is a struct representing a stack. It contains a slice to store items, a comparator function
-// that is used to compare elements for advanced methods such as Find, and a mutex for thread-safety.
+// that is used to compare elements for advanced methods such as Find, the maximum number of items
+// (0 means unbounded), a limit on the number of items printed by String (0 means no limit),
+// and a mutex for thread-safety.
 type Stack[T any] struct {
 	items []T
 	comparator comparators.Comparator[T]
+	bound int
+	stringLimit int
 	mutex sync.Mutex
 }
 
@@ -40,37 +51,183 @@ func NewFromSlice[T any](slice []T, comparator comparators.Comparator[T]) *Stack
 	}
 }
 
+// NewBounded creates a new empty Stack that holds at most capacity items and returns a pointer to it.
+// Once the Stack is full, Push returns ErrFull and TryPush returns false.
+// An error is returned if capacity is not positive or if comparator is nil.
+func NewBounded[T any](capacity int, comparator comparators.Comparator[T]) (*Stack[T], error) {
+	if comparator == nil {
+		return nil, fmt.Errorf("Cannot create a Stack without a comparator.")
+	}
+	return newBounded(capacity, comparator)
+}
+
+// newBounded creates a new empty bounded Stack like NewBounded, but accepts a nil comparator
+// for internal uses that never call Find or Contains.
+func newBounded[T any](capacity int, comparator comparators.Comparator[T]) (*Stack[T], error) {
+	if capacity <= 0 {
+		return nil, fmt.Errorf("Cannot create a Stack with a capacity of %d.", capacity)
+	}
+	return &Stack[T]{
+		items: make([]T, 0, capacity),
+		comparator: comparator,
+		bound: capacity,
+	}, nil
+}
+
 // Pop removes and returns the top item off of the Stack.
 // An error is returned if the Stack is empty.
 func (stack *Stack[T]) Pop() (T, error) {
+	item, ok := stack.TryPop()
+	if !ok {
+		return item, fmt.Errorf("Cannot pop from an empty Stack.")
+	}
+	return item, nil
+}
+
+// TryPop removes and returns the top item off of the Stack and true,
+// or returns false if the Stack is empty. Unlike Pop, it never allocates an error.
+func (stack *Stack[T]) TryPop() (T, bool) {
 	stack.mutex.Lock()
 	defer stack.mutex.Unlock()
 	var zeroValue T
 	if len(stack.items) == 0 {
-		return zeroValue, fmt.Errorf("Cannot pop from an empty Stack.")
+		return zeroValue, false
 	}
 	last := stack.items[len(stack.items) - 1]
+	stack.items[len(stack.items) - 1] = zeroValue
 	stack.items = stack.items[:len(stack.items) - 1]
-	return last, nil
+	return last, true
+}
+
+// PopN removes and returns the top n items off of the Stack, in the order Pop would return them.
+// An error is returned, and nothing is removed, if n is negative or greater than the size of the Stack.
+func (stack *Stack[T]) PopN(n int) ([]T, error) {
+	stack.mutex.Lock()
+	defer stack.mutex.Unlock()
+	if n < 0 || n > len(stack.items) {
+		return nil, fmt.Errorf("Cannot pop %d items from a Stack of size %d.", n, len(stack.items))
+	}
+	return stack.popN(n), nil
+}
+
+// Drain removes and returns all items off of the Stack, in the order Pop would return them.
+func (stack *Stack[T]) Drain() []T {
+	stack.mutex.Lock()
+	defer stack.mutex.Unlock()
+	return stack.popN(len(stack.items))
+}
+
+// popN removes and returns the top n items off of the Stack, top first.
+func (stack *Stack[T]) popN(n int) []T {
+	popped := make([]T, n)
+	for i := range popped {
+		popped[i] = stack.items[len(stack.items) - 1 - i]
+	}
+	clear(stack.items[len(stack.items) - n:])
+	stack.items = stack.items[:len(stack.items) - n]
+	return popped
 }
 
 // Push adds a new item to the top of the Stack.
-func (stack *Stack[T]) Push(newItem T) {
+// ErrFull is returned if the Stack is bounded and full.
+func (stack *Stack[T]) Push(newItem T) error {
 	stack.mutex.Lock()
 	defer stack.mutex.Unlock()
+	if stack.isFull() {
+		return ErrFull
+	}
 	stack.items = append(stack.items, newItem)
+	return nil
+}
+
+// PushAll adds the items to the top of the Stack in order, so the last item ends up on top.
+// If the Stack is bounded and the items do not all fit, ErrFull is returned and nothing is added.
+func (stack *Stack[T]) PushAll(items ...T) error {
+	stack.mutex.Lock()
+	defer stack.mutex.Unlock()
+	if stack.bound > 0 && len(stack.items) + len(items) > stack.bound {
+		return ErrFull
+	}
+	stack.items = append(stack.items, items...)
+	return nil
+}
+
+// TryPush adds a new item to the top of the Stack and returns true,
+// or returns false if the Stack is bounded and full.
+func (stack *Stack[T]) TryPush(newItem T) bool {
+	return stack.Push(newItem) == nil
+}
+
+// isFull returns a bool indicating if the Stack is bounded and full.
+func (stack *Stack[T]) isFull() bool {
+	return stack.bound > 0 && len(stack.items) >= stack.bound
 }
 
 // Peek returns the top item from the Stack.
 // It returns an error if the Stack is empty.
 func (stack *Stack[T]) Peek() (T, error) {
+	item, ok := stack.TryPeek()
+	if !ok {
+		return item, fmt.Errorf("Cannot peek an empty Stack.")
+	}
+	return item, nil
+}
+
+// TryPeek returns the top item from the Stack and true,
+// or returns false if the Stack is empty.
+func (stack *Stack[T]) TryPeek() (T, bool) {
+	stack.mutex.Lock()
+	defer stack.mutex.Unlock()
+	return stack.at(0)
+}
+
+// PeekAt returns the item depth positions below the top of the Stack,
+// so PeekAt(0) returns the same item as Peek.
+// It returns an error if depth is negative or not less than the size of the Stack.
+func (stack *Stack[T]) PeekAt(depth int) (T, error) {
 	stack.mutex.Lock()
 	defer stack.mutex.Unlock()
+	item, ok := stack.at(depth)
+	if !ok {
+		return item, fmt.Errorf("Cannot peek at depth %d of a Stack of size %d.", depth, len(stack.items))
+	}
+	return item, nil
+}
+
+// TryPeekAt returns the item depth positions below the top of the Stack and true,
+// or returns false if there is no such item.
+func (stack *Stack[T]) TryPeekAt(depth int) (T, bool) {
+	stack.mutex.Lock()
+	defer stack.mutex.Unlock()
+	return stack.at(depth)
+}
+
+// at returns the item depth positions below the top of the Stack,
+// and false if there is no such item.
+func (stack *Stack[T]) at(depth int) (T, bool) {
 	var zeroValue T
-	if len(stack.items) == 0 {
-		return zeroValue, fmt.Errorf("Cannot peek an empty Stack.")
+	if depth < 0 || depth >= len(stack.items) {
+		return zeroValue, false
+	}
+	return stack.items[len(stack.items) - 1 - depth], true
+}
+
+// All returns an iterator over the items of the Stack from top to bottom.
+// No copy of the Stack is made: each step looks up the next depth under the lock,
+// which is released while the loop body runs. If the Stack is modified during
+// iteration, items may therefore be skipped or seen twice, but the loop body
+// may safely call methods on the Stack.
+func (stack *Stack[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for depth := 0; ; depth++ {
+			stack.mutex.Lock()
+			item, ok := stack.at(depth)
+			stack.mutex.Unlock()
+			if !ok || !yield(item) {
+				return
+			}
+		}
 	}
-	return stack.items[len(stack.items) - 1], nil
 }
 
 // IsEmpty returns a bool indicating if the Stack is empty.
@@ -87,6 +244,35 @@ func (stack *Stack[T]) Size() int {
 	return len(stack.items)
 }
 
+// IsFull returns a bool indicating if the Stack is bounded and full.
+// An unbounded Stack is never full.
+func (stack *Stack[T]) IsFull() bool {
+	stack.mutex.Lock()
+	defer stack.mutex.Unlock()
+	return stack.isFull()
+}
+
+// Cap returns the number of items the Stack can hold before its backing slice must grow.
+func (stack *Stack[T]) Cap() int {
+	stack.mutex.Lock()
+	defer stack.mutex.Unlock()
+	return cap(stack.items)
+}
+
+// Compact shrinks the backing slice of the Stack to fit its items exactly,
+// releasing the memory left behind by earlier pushes, e.g. after popping most
+// of a large Stack. Pushing afterwards grows the slice again as needed.
+func (stack *Stack[T]) Compact() {
+	stack.mutex.Lock()
+	defer stack.mutex.Unlock()
+	if cap(stack.items) == len(stack.items) {
+		return
+	}
+	compacted := make([]T, len(stack.items))
+	copy(compacted, stack.items)
+	stack.items = compacted
+}
+
 // Clear removes all items from the Stack.
 func (stack *Stack[T]) Clear() {
 	stack.mutex.Lock()
@@ -96,17 +282,36 @@ func (stack *Stack[T]) Clear() {
 
 // Find returns nonnegative int indicating the poistion of the item in the Stack.
 // Returns -1 if the item is not in the Stack.
+// It panics if the Stack was created with a nil comparator; use FindFunc instead.
 func (stack *Stack[T]) Find(item T) int {
+	if stack.comparator == nil {
+		panic("Cannot find an item in a Stack without a comparator; use FindFunc.")
+	}
+	return stack.FindFunc(func(other T) bool {
+		return stack.comparator(other, item) == 0
+	})
+}
+
+// FindFunc returns a nonnegative int indicating the position of the first item
+// in the Stack (counting from the bottom, like Find) for which pred returns true,
+// or -1 if there is none. It does not need a comparator.
+func (stack *Stack[T]) FindFunc(pred func(T) bool) int {
 	stack.mutex.Lock()
 	defer stack.mutex.Unlock()
 	for i := range stack.items {
-		if stack.comparator(stack.items[i], item) == 0 {
+		if pred(stack.items[i]) {
 			return i
 		}
 	}
 	return -1
 }
 
+// Contains returns a bool indicating whether or not the item is in the Stack.
+// Like Find, it panics if the Stack was created with a nil comparator.
+func (stack *Stack[T]) Contains(item T) bool {
+	return stack.Find(item) != -1
+}
+
 // ToSlice returns the Stack as a slice.
 func (stack *Stack[T]) ToSlice() []T {
 	stack.mutex.Lock()
@@ -125,12 +330,71 @@ func (stack *Stack[T]) Copy() *Stack[T] {
 	return &Stack[T]{
 		items: copiedSlice,
 		comparator: stack.comparator,
+		bound: stack.bound,
+		stringLimit: stack.stringLimit,
 	}
 }
 
+// CopyCompact returns a pointer to a copy of the Stack whose backing slice holds exactly
+// its items. Copy already trims the capacity the same way; CopyCompact exists so that every
+// slice-backed structure offers it.
+func (stack *Stack[T]) CopyCompact() *Stack[T] {
+	return stack.Copy()
+}
+
 // String returns the string representation of the Stack.
+// If a limit was set with SetStringLimit, only that many items are printed.
 func (stack *Stack[T]) String() string {
 	stack.mutex.Lock()
 	defer stack.mutex.Unlock()
-	return fmt.Sprintf("%v", stack.items)
+	if stack.stringLimit > 0 {
+		return stack.stringN(stack.stringLimit)
+	}
+	return stack.stringN(-1)
+}
+
+// StringN returns the string representation of the first max items of the Stack
+// (starting from the bottom, like String), followed by "… (+N more)" if items were left out.
+// If max is negative, all items are printed.
+func (stack *Stack[T]) StringN(max int) string {
+	stack.mutex.Lock()
+	defer stack.mutex.Unlock()
+	return stack.stringN(max)
+}
+
+// stringN returns the string representation of the first max items of the Stack.
+func (stack *Stack[T]) stringN(max int) string {
+	limit := format.Limit(len(stack.items), max)
+	return format.Truncated(stack.items[:limit], len(stack.items) - limit)
+}
+
+// SetStringLimit limits String to printing the first max items of the Stack.
+// A max of 0 or less removes the limit.
+func (stack *Stack[T]) SetStringLimit(max int) {
+	stack.mutex.Lock()
+	defer stack.mutex.Unlock()
+	stack.stringLimit = max
+}
+
+// MemUsage returns an approximation of the number of bytes used by the Stack,
+// including its whole backing slice. Memory referenced by the items themselves
+// (e.g. the contents of strings or slices) is not counted; use MemUsageFunc for that.
+func (stack *Stack[T]) MemUsage() int64 {
+	return stack.MemUsageFunc(nil)
+}
+
+// MemUsageFunc is like MemUsage, but also adds the result of calling size on every item.
+// The size function should return the bytes referenced by an item beyond its own fixed size.
+// If size is nil, it behaves exactly like MemUsage.
+func (stack *Stack[T]) MemUsageFunc(size func(T) int64) int64 {
+	stack.mutex.Lock()
+	defer stack.mutex.Unlock()
+	var zeroValue T
+	total := int64(unsafe.Sizeof(*stack)) + int64(cap(stack.items)) * int64(unsafe.Sizeof(zeroValue))
+	if size != nil {
+		for _, item := range stack.items {
+			total += size(item)
+		}
+	}
+	return total
 }
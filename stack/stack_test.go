@@ -1,6 +1,8 @@
 package stack
 
 import (
+	"errors"
+	"slices"
 	"sync"
 	"testing"
 
@@ -137,6 +139,115 @@ func TestPush(t *testing.T) {
 	})
 }
 
+func TestNewBounded(t *testing.T) {
+	t.Run("InvalidCapacity", func(t *testing.T) {
+		_, err := NewBounded[int](0, comparators.ComparatorInt)
+		if err == nil {
+			t.Fatal("Created a Stack with a capacity of 0.")
+		}
+	})
+
+	t.Run("Sequential", func(t *testing.T) {
+		s, err := NewBounded[int](2, comparators.ComparatorInt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		testutils.Assert(t, "s.TryPush(1)", true, s.TryPush(1))
+		if err := s.Push(2); err != nil {
+			t.Fatal(err)
+		}
+		testutils.Assert(t, "s.IsFull()", true, s.IsFull())
+		if err := s.Push(3); !errors.Is(err, ErrFull) {
+			t.Fatalf("Expected ErrFull, instead got: %v", err)
+		}
+		testutils.Assert(t, "s.TryPush(3)", false, s.TryPush(3))
+		testutils.Assert(t, "s.Copy().TryPush(3)", false, s.Copy().TryPush(3))
+		s.Pop()
+		testutils.Assert(t, "s.TryPush(3)", true, s.TryPush(3))
+		testutils.AssertSlices(t, []int{1, 3}, s.ToSlice())
+	})
+
+	t.Run("Concurrent", func(t *testing.T) {
+		s, err := NewBounded[int](500, comparators.ComparatorInt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		testutils.ConcurrentOperations(t, 10, 100, func() error {
+			s.TryPush(1)
+			return nil
+		})
+		testutils.Assert(t, "s.Size()", 500, s.Size())
+	})
+}
+
+func TestPushAll(t *testing.T) {
+	t.Run("Sequential", func(t *testing.T) {
+		s := NewFromSlice([]int{1}, comparators.ComparatorInt)
+		if err := s.PushAll(2, 3); err != nil {
+			t.Fatal(err)
+		}
+		testutils.AssertSlices(t, []int{1, 2, 3}, s.ToSlice())
+	})
+
+	t.Run("Bounded", func(t *testing.T) {
+		s, err := NewBounded[int](2, comparators.ComparatorInt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := s.PushAll(1, 2, 3); !errors.Is(err, ErrFull) {
+			t.Fatalf("Expected ErrFull, instead got: %v", err)
+		}
+		testutils.Assert(t, "s.Size()", 0, s.Size())
+	})
+
+	t.Run("Concurrent", func(t *testing.T) {
+		s := NewEmpty[int](comparators.ComparatorInt)
+		testutils.ConcurrentOperations(t, 10, 100, func() error {
+			return s.PushAll(1, 2)
+		})
+		testutils.Assert(t, "s.Size()", 2000, s.Size())
+	})
+}
+
+func TestPopN(t *testing.T) {
+	t.Run("Sequential", func(t *testing.T) {
+		s := NewFromSlice([]int{1, 2, 3}, comparators.ComparatorInt)
+		popped, err := s.PopN(2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		testutils.AssertSlices(t, []int{3, 2}, popped)
+		testutils.AssertSlices(t, []int{1}, s.ToSlice())
+	})
+
+	t.Run("TooMany", func(t *testing.T) {
+		s := NewFromSlice([]int{1, 2, 3}, comparators.ComparatorInt)
+		if _, err := s.PopN(4); err == nil {
+			t.Fatal("Popped 4 items from a Stack of size 3.")
+		}
+		testutils.Assert(t, "s.Size()", 3, s.Size())
+	})
+
+	t.Run("Concurrent", func(t *testing.T) {
+		s := NewEmpty[int](comparators.ComparatorInt)
+		for i := 0; i < 2000; i++ {
+			s.Push(i)
+		}
+		testutils.ConcurrentOperations(t, 10, 100, func() error {
+			_, err := s.PopN(2)
+			return err
+		})
+		testutils.Assert(t, "s.Size()", 0, s.Size())
+	})
+}
+
+func TestDrain(t *testing.T) {
+	s := NewFromSlice([]int{1, 2, 3}, comparators.ComparatorInt)
+	testutils.AssertSlices(t, []int{3, 2, 1}, s.Drain())
+	testutils.Assert(t, "s.Size()", 0, s.Size())
+	testutils.AssertSlices(t, []int{}, s.Drain())
+}
+
 func TestPeek(t *testing.T) {
 	t.Run("Empty", func(t *testing.T) {
 		s := NewEmpty[int](comparators.ComparatorInt)
@@ -157,6 +268,60 @@ func TestPeek(t *testing.T) {
 	})
 }
 
+func TestTryPopAndPeek(t *testing.T) {
+	s := NewFromSlice([]int{1, 2}, comparators.ComparatorInt)
+	top, ok := s.TryPeek()
+	testutils.Assert(t, "ok", true, ok)
+	testutils.Assert(t, "top", 2, top)
+	below, ok := s.TryPeekAt(1)
+	testutils.Assert(t, "ok", true, ok)
+	testutils.Assert(t, "below", 1, below)
+	if _, ok := s.TryPeekAt(2); ok {
+		t.Fatal("Peeked below the bottom of the Stack.")
+	}
+	for _, expected := range []int{2, 1} {
+		item, ok := s.TryPop()
+		testutils.Assert(t, "ok", true, ok)
+		testutils.Assert(t, "item", expected, item)
+	}
+	if _, ok := s.TryPop(); ok {
+		t.Fatal("Popped from an empty Stack.")
+	}
+	if _, ok := s.TryPeek(); ok {
+		t.Fatal("Peeked an empty Stack.")
+	}
+}
+
+func TestPeekAt(t *testing.T) {
+	s := NewFromSlice([]int{1, 2, 3}, comparators.ComparatorInt)
+	for depth, expected := range []int{3, 2, 1} {
+		item, err := s.PeekAt(depth)
+		if err != nil {
+			t.Fatal(err)
+		}
+		testutils.Assert(t, "item", expected, item)
+	}
+	for _, depth := range []int{-1, 3} {
+		if _, err := s.PeekAt(depth); err == nil {
+			t.Fatalf("Peeked at depth %d of a Stack of size 3.", depth)
+		}
+	}
+}
+
+func TestAll(t *testing.T) {
+	s := NewFromSlice([]int{1, 2, 3}, comparators.ComparatorInt)
+	testutils.AssertSlices(t, []int{3, 2, 1}, slices.Collect(s.All()))
+	for item := range s.All() {
+		if item == 2 {
+			break
+		}
+	}
+	for range s.All() {
+		s.Pop()
+	}
+	testutils.Assert(t, "s.Size()", 1, s.Size())
+}
+
 func TestIsEmpty(t *testing.T) {
 	t.Run("Empty", func(t *testing.T) {
 		s := NewEmpty[int](comparators.ComparatorInt)
@@ -207,6 +372,33 @@ func TestFind(t *testing.T) {
 		negativeOne := s.Find(1099)
 		testutils.Assert(t, "negativeOne", -1, negativeOne)
 	})
+
+	t.Run("NoComparator", func(t *testing.T) {
+		if _, err := NewBounded[int](3, nil); err == nil {
+			t.Fatal("Created a bounded Stack without a comparator.")
+		}
+		s := NewEmpty[int](nil)
+		s.Push(1)
+		testutils.Assert(t, "s.FindFunc()", 0, s.FindFunc(func(item int) bool { return item == 1 }))
+		defer func() {
+			if recover() == nil {
+				t.Fatal("Expected Find to panic on a Stack without a comparator.")
+			}
+		}()
+		s.Find(1)
+	})
+}
+
+func TestFindFunc(t *testing.T) {
+	s := NewFromSlice([]int{1, 2, 3, 4}, comparators.ComparatorInt)
+	testutils.Assert(t, "s.FindFunc()", 1, s.FindFunc(func(item int) bool { return item % 2 == 0 }))
+	testutils.Assert(t, "s.FindFunc()", -1, s.FindFunc(func(item int) bool { return item > 4 }))
+}
+
+func TestContains(t *testing.T) {
+	s := NewFromSlice([]int{1, 2, 3}, comparators.ComparatorInt)
+	testutils.Assert(t, "s.Contains(3)", true, s.Contains(3))
+	testutils.Assert(t, "s.Contains(4)", false, s.Contains(4))
 }
 
 func TestToSlice(t *testing.T) {
@@ -244,3 +436,57 @@ func TestString(t *testing.T) {
 	s := NewFromSlice([]int{1, 2, 3}, comparators.ComparatorInt)
 	testutils.Assert(t, "s.String()", "[1 2 3]", s.String())
 }
+
+func TestStringN(t *testing.T) {
+	s := NewFromSlice([]int{1, 2, 3}, comparators.ComparatorInt)
+	testutils.Assert(t, "s.StringN(1)", "[1 … (+2 more)]", s.StringN(1))
+	testutils.Assert(t, "s.StringN(3)", "[1 2 3]", s.StringN(3))
+	s.SetStringLimit(2)
+	testutils.Assert(t, "s.String()", "[1 2 … (+1 more)]", s.String())
+}
+
+func TestMemUsage(t *testing.T) {
+	s := NewFromSlice([]int64{1, 2}, comparators.ComparatorInt64)
+	withItems := s.MemUsage()
+	s.Clear()
+	testutils.Assert(t, "withItems - s.MemUsage()", int64(16), withItems - s.MemUsage())
+	s.Push(1)
+	testutils.Assert(t, "s.MemUsageFunc(...) - s.MemUsage()", int64(3), s.MemUsageFunc(func(int64) int64 { return 3 }) - s.MemUsage())
+}
+
+func TestCap(t *testing.T) {
+	s := NewFromSlice([]int{1, 2, 3}, comparators.ComparatorInt)
+	testutils.Assert(t, "s.Cap()", 3, s.Cap())
+	s.Push(4)
+	if s.Cap() < 4 {
+		t.Fatalf("Expected 's.Cap()' to be at least 4, instead got: %d", s.Cap())
+	}
+}
+
+func TestCompact(t *testing.T) {
+	s := NewEmpty[int](comparators.ComparatorInt)
+	for i := 0; i < 1000; i++ {
+		s.Push(i)
+	}
+	s.PopN(998)
+	before := s.MemUsage()
+	s.Compact()
+	testutils.Assert(t, "s.Cap()", 2, s.Cap())
+	if s.MemUsage() >= before {
+		t.Fatal("Expected MemUsage to shrink after compacting.")
+	}
+	testutils.AssertSlices(t, []int{0, 1}, s.ToSlice())
+	s.Push(2)
+	testutils.AssertSlices(t, []int{0, 1, 2}, s.ToSlice())
+}
+
+func TestCopyCompact(t *testing.T) {
+	s := NewEmpty[int](comparators.ComparatorInt)
+	for i := 0; i < 100; i++ {
+		s.Push(i)
+	}
+	s.PopN(97)
+	c := s.CopyCompact()
+	testutils.Assert(t, "c.Cap()", 3, c.Cap())
+	testutils.AssertSlices(t, []int{0, 1, 2}, c.ToSlice())
+}
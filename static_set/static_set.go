@@ -0,0 +1,193 @@
+// Package static_set provides a read-only, generic set backed by a sorted slice.
+package static_set
+
+import (
+	"fmt"
+	"iter"
+	"sort"
+	"unsafe"
+
+	"github.com/davidpogosian/ds/comparators"
+	"github.com/davidpogosian/ds/internal/format"
+)
+
+// StaticSet struct represents a frozen set.
+// It stores its items in a sorted slice without duplicates and answers
+// membership queries using binary search. It can optionally keep a bloom
+// filter in front of the binary search to reject most absent items early.
+// A StaticSet cannot be modified after construction, so it is safe for
+// concurrent use without locking.
+type StaticSet[T any] struct {
+	items []T
+	comparator comparators.Comparator[T]
+	bloom []uint64
+	bloomHashes int
+	hash func(T) uint64
+}
+
+// NewFromSlice returns a pointer to a new StaticSet holding the items of the slice.
+// The slice is copied, sorted and deduplicated prior to being handed over to the StaticSet.
+// NewFromSlice requires a comparator function to compare elements.
+// For built-in types, the comparators package provides ready-made comparators
+// (e.g., comparators.CompareInt for int).
+// Custom types will require a user-defined comparator.
+func NewFromSlice[T any](slice []T, comparator comparators.Comparator[T]) *StaticSet[T] {
+	copiedSlice := make([]T, len(slice))
+	copy(copiedSlice, slice)
+	sort.SliceStable(copiedSlice, func(i, j int) bool {
+		return comparator(copiedSlice[i], copiedSlice[j]) < 0
+	})
+	unique := copiedSlice[:0]
+	for i, item := range copiedSlice {
+		if i == 0 || comparator(unique[len(unique) - 1], item) != 0 {
+			unique = append(unique, item)
+		}
+	}
+	items := make([]T, len(unique))
+	copy(items, unique)
+	return &StaticSet[T]{items: items, comparator: comparator}
+}
+
+// NewFromSliceWithBloom is like NewFromSlice, but also builds a bloom filter
+// with bitsPerItem bits per item using the provided hash function.
+// Lookups of absent items are then usually answered without a binary search.
+// If bitsPerItem is not positive, an error is returned.
+func NewFromSliceWithBloom[T any](slice []T, comparator comparators.Comparator[T], hash func(T) uint64, bitsPerItem int) (*StaticSet[T], error) {
+	if bitsPerItem <= 0 {
+		return nil, fmt.Errorf("Cannot build a bloom filter with %d bits per item.", bitsPerItem)
+	}
+	s := NewFromSlice(slice, comparator)
+	bits := len(s.items) * bitsPerItem
+	if bits < 64 {
+		bits = 64
+	}
+	s.bloom = make([]uint64, (bits + 63) / 64)
+	// ln(2) * bits per item is the optimal number of hash functions.
+	s.bloomHashes = bitsPerItem * 69 / 100
+	if s.bloomHashes < 1 {
+		s.bloomHashes = 1
+	}
+	s.hash = hash
+	for _, item := range s.items {
+		h1, h2 := s.bloomHash(item)
+		for i := 0; i < s.bloomHashes; i++ {
+			bit := (h1 + uint64(i) * h2) % uint64(len(s.bloom) * 64)
+			s.bloom[bit / 64] |= 1 << (bit % 64)
+		}
+	}
+	return s, nil
+}
+
+// bloomHash derives the two base hashes used for double hashing in the bloom filter.
+func (s *StaticSet[T]) bloomHash(item T) (uint64, uint64) {
+	h := s.hash(item)
+	// splitmix64 finalizer to spread weak user hashes.
+	h2 := h + 0x9e3779b97f4a7c15
+	h2 = (h2 ^ (h2 >> 30)) * 0xbf58476d1ce4e5b9
+	h2 = (h2 ^ (h2 >> 27)) * 0x94d049bb133111eb
+	h2 ^= h2 >> 31
+	return h, h2 | 1
+}
+
+// mayContain reports whether the bloom filter allows the item to be in the StaticSet.
+func (s *StaticSet[T]) mayContain(item T) bool {
+	if s.bloom == nil {
+		return true
+	}
+	h1, h2 := s.bloomHash(item)
+	for i := 0; i < s.bloomHashes; i++ {
+		bit := (h1 + uint64(i) * h2) % uint64(len(s.bloom) * 64)
+		if s.bloom[bit / 64] & (1 << (bit % 64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Contains returns a bool indicating whether or not the item is in the StaticSet.
+func (s *StaticSet[T]) Contains(item T) bool {
+	if !s.mayContain(item) {
+		return false
+	}
+	return s.Find(item) != -1
+}
+
+// Find returns the index of the item in the sorted order of the StaticSet.
+// If the item is not in the StaticSet, -1 is returned.
+func (s *StaticSet[T]) Find(item T) int {
+	low, high := 0, len(s.items) - 1
+	for low <= high {
+		middle := int(uint(low + high) >> 1)
+		comparison := s.comparator(s.items[middle], item)
+		if comparison == 0 {
+			return middle
+		} else if comparison < 0 {
+			low = middle + 1
+		} else {
+			high = middle - 1
+		}
+	}
+	return -1
+}
+
+// Size returns the number of items in the StaticSet.
+func (s *StaticSet[T]) Size() int {
+	return len(s.items)
+}
+
+// IsEmpty returns a bool indicating the emptiness of the StaticSet.
+func (s *StaticSet[T]) IsEmpty() bool {
+	return len(s.items) == 0
+}
+
+// ToSlice returns the StaticSet as a sorted slice.
+func (s *StaticSet[T]) ToSlice() []T {
+	copiedSlice := make([]T, len(s.items))
+	copy(copiedSlice, s.items)
+	return copiedSlice
+}
+
+// All returns an iterator over the items of the StaticSet in increasing order.
+func (s *StaticSet[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, item := range s.items {
+			if !yield(item) {
+				return
+			}
+		}
+	}
+}
+
+// String returns the string representation of the StaticSet.
+func (s *StaticSet[T]) String() string {
+	return fmt.Sprintf("%v", s.items)
+}
+
+// StringN returns the string representation of the first max items of the StaticSet,
+// followed by "… (+N more)" if items were left out.
+// If max is negative, all items are printed.
+func (s *StaticSet[T]) StringN(max int) string {
+	limit := format.Limit(len(s.items), max)
+	return format.Truncated(s.items[:limit], len(s.items) - limit)
+}
+
+// MemUsage returns an approximation of the number of bytes used by the StaticSet,
+// including its sorted slice and bloom filter. Memory referenced by the items themselves
+// (e.g. the contents of strings) is not counted; use MemUsageFunc for that.
+func (s *StaticSet[T]) MemUsage() int64 {
+	return s.MemUsageFunc(nil)
+}
+
+// MemUsageFunc is like MemUsage, but also adds the result of calling size on every item.
+// The size function should return the bytes referenced by an item beyond its own fixed size.
+// If size is nil, it behaves exactly like MemUsage.
+func (s *StaticSet[T]) MemUsageFunc(size func(T) int64) int64 {
+	var zeroValue T
+	total := int64(unsafe.Sizeof(*s)) + int64(cap(s.items)) * int64(unsafe.Sizeof(zeroValue)) + int64(len(s.bloom)) * 8
+	if size != nil {
+		for _, item := range s.items {
+			total += size(item)
+		}
+	}
+	return total
+}
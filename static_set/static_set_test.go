@@ -0,0 +1,94 @@
+package static_set
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/davidpogosian/ds/comparators"
+	"github.com/davidpogosian/ds/testutils"
+)
+
+func TestNewFromSlice(t *testing.T) {
+	t.Run("NilSlice", func(t *testing.T) {
+		s := NewFromSlice[int](nil, comparators.ComparatorInt)
+		testutils.Assert(t, "s.Size()", 0, s.Size())
+		testutils.Assert(t, "s.IsEmpty()", true, s.IsEmpty())
+		testutils.Assert(t, "s.Contains(1)", false, s.Contains(1))
+	})
+
+	t.Run("Duplicates", func(t *testing.T) {
+		s := NewFromSlice([]int{3, 1, 2, 3, 1}, comparators.ComparatorInt)
+		testutils.Assert(t, "s.Size()", 3, s.Size())
+		testutils.AssertSlices(t, []int{1, 2, 3}, s.ToSlice())
+		testutils.Assert(t, "s.String()", "[1 2 3]", s.String())
+	})
+
+	t.Run("ModifySlice", func(t *testing.T) {
+		slice := []int{1, 2, 3}
+		s := NewFromSlice(slice, comparators.ComparatorInt)
+		slice[0] = 99
+		testutils.Assert(t, "s.Contains(1)", true, s.Contains(1))
+		testutils.Assert(t, "s.Contains(99)", false, s.Contains(99))
+	})
+}
+
+func TestNewFromSliceWithBloom(t *testing.T) {
+	t.Run("InvalidBits", func(t *testing.T) {
+		_, err := NewFromSliceWithBloom([]int{1}, comparators.ComparatorInt, func(i int) uint64 { return uint64(i) }, 0)
+		if err == nil {
+			t.Fatal("Built a bloom filter with 0 bits per item.")
+		}
+	})
+
+	t.Run("Contains", func(t *testing.T) {
+		slice := make([]int, 1000)
+		for i := range slice {
+			slice[i] = i * 2
+		}
+		s, err := NewFromSliceWithBloom(slice, comparators.ComparatorInt, func(i int) uint64 { return uint64(i) }, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < 2000; i++ {
+			testutils.Assert(t, "s.Contains(i)", i % 2 == 0, s.Contains(i))
+		}
+	})
+}
+
+func TestFind(t *testing.T) {
+	s := NewFromSlice([]string{"c", "a", "b"}, comparators.ComparatorString)
+	testutils.Assert(t, "s.Find(\"a\")", 0, s.Find("a"))
+	testutils.Assert(t, "s.Find(\"c\")", 2, s.Find("c"))
+	testutils.Assert(t, "s.Find(\"d\")", -1, s.Find("d"))
+}
+
+func TestConcurrentContains(t *testing.T) {
+	s := NewFromSlice([]int{1, 2, 3}, comparators.ComparatorInt)
+	testutils.ConcurrentOperations(t, 10, 100, func() error {
+		if !s.Contains(2) {
+			t.Error("Expected s.Contains(2) to be true.")
+		}
+		return nil
+	})
+}
+
+func TestStringN(t *testing.T) {
+	s := NewFromSlice([]int{3, 2, 1}, comparators.ComparatorInt)
+	testutils.Assert(t, "s.StringN(2)", "[1 2 … (+1 more)]", s.StringN(2))
+	testutils.Assert(t, "s.StringN(-1)", "[1 2 3]", s.StringN(-1))
+}
+
+func TestMemUsage(t *testing.T) {
+	plain := NewFromSlice([]int{1, 2, 3}, comparators.ComparatorInt)
+	bloom, err := NewFromSliceWithBloom([]int{1, 2, 3}, comparators.ComparatorInt, func(i int) uint64 { return uint64(i) }, 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutils.Assert(t, "bloom.MemUsage() - plain.MemUsage()", int64(8), bloom.MemUsage() - plain.MemUsage())
+	testutils.Assert(t, "plain.MemUsageFunc(...) - plain.MemUsage()", int64(3), plain.MemUsageFunc(func(int) int64 { return 1 }) - plain.MemUsage())
+}
+
+func TestAll(t *testing.T) {
+	s := NewFromSlice([]int{3, 1, 2, 1}, comparators.ComparatorInt)
+	testutils.AssertSlices(t, []int{1, 2, 3}, slices.Collect(s.All()))
+}
@@ -2,8 +2,11 @@ package testutils
 
 import (
 	"fmt"
+	"math/rand"
+	"sort"
 	"sync"
 	"testing"
+	"time"
 )
 
 func Assert[T comparable](t *testing.T, varname string, expected T, got T) {
@@ -56,3 +59,144 @@ func ConcurrentOperations(t *testing.T, threads int, repetitions int, function f
 		}
 	}
 }
+
+// Operation is one kind of operation in a Workload. Weight is relative to the
+// weights of the other operations, e.g. weights 8 and 2 give an 80/20 split.
+type Operation struct {
+	Name string
+	Weight int
+	Function func() error
+}
+
+// Workload describes a mixed concurrent workload for RunWorkload.
+// Every one of Threads workers performs Repetitions operations, each picked at random
+// by weight. The picks of every worker are derived from Seed, so the same Seed always
+// gives the same operations. If Deterministic is true, the workers are not run as
+// goroutines; instead their operations are interleaved one at a time in an order that
+// is also derived from Seed, which makes the whole schedule reproducible.
+type Workload struct {
+	Threads int
+	Repetitions int
+	Seed int64
+	Deterministic bool
+	Operations []Operation
+}
+
+// WorkloadResult holds how many times each operation ran and how long every run took,
+// keyed by operation name.
+type WorkloadResult struct {
+	Counts map[string]int
+	Latencies map[string][]time.Duration
+}
+
+// Percentile returns the latency of the named operation at percentile p (between 0 and 100).
+// It returns 0 if the operation never ran.
+func (r WorkloadResult) Percentile(name string, p float64) time.Duration {
+	latencies := append([]time.Duration(nil), r.Latencies[name]...)
+	if len(latencies) == 0 {
+		return 0
+	}
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	index := int(p / 100 * float64(len(latencies) - 1))
+	return latencies[index]
+}
+
+// workloadWorker holds the random source and the measurements of one Workload worker.
+type workloadWorker struct {
+	rng *rand.Rand
+	remaining int
+	latencies map[string][]time.Duration
+}
+
+// step picks an operation by weight, runs it and records its latency.
+func (w *workloadWorker) step(operations []Operation, totalWeight int) error {
+	pick := w.rng.Intn(totalWeight)
+	op := operations[len(operations) - 1]
+	for _, candidate := range operations {
+		if pick < candidate.Weight {
+			op = candidate
+			break
+		}
+		pick -= candidate.Weight
+	}
+	w.remaining--
+	start := time.Now()
+	err := op.Function()
+	w.latencies[op.Name] = append(w.latencies[op.Name], time.Since(start))
+	if err != nil {
+		return fmt.Errorf("%s: %w", op.Name, err)
+	}
+	return nil
+}
+
+// RunWorkload runs the Workload and returns per-operation counts and latencies.
+// Errors returned by operations fail the test after all workers are done.
+// Like ConcurrentOperations, operations must return errors rather than call t.Fatal.
+func RunWorkload(t *testing.T, workload Workload) WorkloadResult {
+	totalWeight := 0
+	for _, op := range workload.Operations {
+		if op.Weight < 0 {
+			t.Fatalf("Operation '%s' has a negative weight.", op.Name)
+		}
+		totalWeight += op.Weight
+	}
+	if totalWeight == 0 {
+		t.Fatal("Workload operations have a total weight of 0.")
+	}
+	workers := make([]*workloadWorker, workload.Threads)
+	for i := range workers {
+		workers[i] = &workloadWorker{
+			rng: rand.New(rand.NewSource(workload.Seed + int64(i))),
+			remaining: workload.Repetitions,
+			latencies: make(map[string][]time.Duration),
+		}
+	}
+	var errs []error
+	if workload.Deterministic {
+		schedule := rand.New(rand.NewSource(workload.Seed))
+		active := append([]*workloadWorker(nil), workers...)
+		for len(active) > 0 {
+			i := schedule.Intn(len(active))
+			if err := active[i].step(workload.Operations, totalWeight); err != nil {
+				errs = append(errs, err)
+			}
+			if active[i].remaining == 0 {
+				active = append(active[:i], active[i + 1:]...)
+			}
+		}
+	} else {
+		var waitGroup sync.WaitGroup
+		var mu sync.Mutex
+		for _, worker := range workers {
+			waitGroup.Add(1)
+			go func(worker *workloadWorker) {
+				defer waitGroup.Done()
+				for worker.remaining > 0 {
+					if err := worker.step(workload.Operations, totalWeight); err != nil {
+						mu.Lock()
+						errs = append(errs, err)
+						mu.Unlock()
+					}
+				}
+			}(worker)
+		}
+		waitGroup.Wait()
+	}
+	for _, err := range errs {
+		t.Error(err)
+	}
+	if len(errs) > 0 {
+		t.FailNow()
+	}
+	result := WorkloadResult{
+		Counts: make(map[string]int),
+		Latencies: make(map[string][]time.Duration),
+	}
+	for _, worker := range workers {
+		for name, latencies := range worker.latencies {
+			result.Counts[name] += len(latencies)
+			result.Latencies[name] = append(result.Latencies[name], latencies...)
+		}
+	}
+	return result
+}
@@ -0,0 +1,132 @@
+// Package timering provides a thread-safe, generic ring of time-bucketed containers.
+package timering
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Ring struct represents a ring of buckets, each holding a container of type C
+// (e.g. a *set.Set) that covers one interval of time. Writes go to the newest bucket.
+// Once an interval has passed, the ring rotates: the oldest bucket is dropped and a
+// new, empty one is created with the factory. This answers questions such as
+// "was this seen in the last N minutes?" without tracking an expiry time per entry:
+// an entry is kept for between (buckets - 1) and buckets intervals.
+// Rotation happens lazily, when the Ring is next used, so no goroutine is needed.
+// Ring has fields for the buckets, the index of the newest bucket, the time the newest
+// bucket started, the interval, the factory, a clock, and a mutex for thread-safety.
+// The Ring only guards rotation; the containers must be thread-safe themselves
+// if they are used concurrently.
+type Ring[C any] struct {
+	buckets []C
+	newest int
+	start time.Time
+	interval time.Duration
+	factory func() C
+	now func() time.Time
+	mu sync.Mutex
+}
+
+// New returns a pointer to a new Ring of the given number of buckets, each covering
+// interval, and filled with containers created by factory.
+// An error is returned if buckets or interval is not positive.
+func New[C any](buckets int, interval time.Duration, factory func() C) (*Ring[C], error) {
+	if buckets <= 0 {
+		return nil, fmt.Errorf("Cannot create a Ring with %d buckets.", buckets)
+	}
+	if interval <= 0 {
+		return nil, fmt.Errorf("Cannot create a Ring with an interval of %v.", interval)
+	}
+	r := &Ring[C]{
+		buckets: make([]C, buckets),
+		interval: interval,
+		factory: factory,
+		now: time.Now,
+	}
+	for i := range r.buckets {
+		r.buckets[i] = factory()
+	}
+	r.start = r.now()
+	return r, nil
+}
+
+// rotate drops every bucket whose interval has fully passed, replacing it with a new one.
+// It must be called with the mutex held.
+func (r *Ring[C]) rotate() {
+	steps := int(r.now().Sub(r.start) / r.interval)
+	if steps <= 0 {
+		return
+	}
+	for i := 0; i < min(steps, len(r.buckets)); i++ {
+		r.newest = (r.newest + 1) % len(r.buckets)
+		r.buckets[r.newest] = r.factory()
+	}
+	r.start = r.start.Add(time.Duration(steps) * r.interval)
+}
+
+// Current returns the container of the newest bucket, which new entries should be written to.
+func (r *Ring[C]) Current() C {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.rotate()
+	return r.buckets[r.newest]
+}
+
+// Buckets returns the containers of all buckets, from the newest to the oldest.
+func (r *Ring[C]) Buckets() []C {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.rotate()
+	buckets := make([]C, len(r.buckets))
+	for i := range buckets {
+		buckets[i] = r.buckets[(r.newest - i + len(r.buckets)) % len(r.buckets)]
+	}
+	return buckets
+}
+
+// Any returns a bool indicating whether pred returns true for the container of any bucket,
+// checking from the newest to the oldest, e.g. to look an entry up in every bucket.
+func (r *Ring[C]) Any(pred func(C) bool) bool {
+	for _, bucket := range r.Buckets() {
+		if pred(bucket) {
+			return true
+		}
+	}
+	return false
+}
+
+// Rotate drops the oldest bucket right away, replacing it with a new, empty newest bucket,
+// and restarts the current interval.
+func (r *Ring[C]) Rotate() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.rotate()
+	r.newest = (r.newest + 1) % len(r.buckets)
+	r.buckets[r.newest] = r.factory()
+	r.start = r.now()
+}
+
+// Clear replaces every bucket with a new, empty one and restarts the current interval.
+func (r *Ring[C]) Clear() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range r.buckets {
+		r.buckets[i] = r.factory()
+	}
+	r.start = r.now()
+}
+
+// Size returns the number of buckets in the Ring.
+func (r *Ring[C]) Size() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.buckets)
+}
+
+// Interval returns the length of time covered by each bucket.
+func (r *Ring[C]) Interval() time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.interval
+}
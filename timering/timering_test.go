@@ -0,0 +1,128 @@
+package timering
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/davidpogosian/ds/set"
+	"github.com/davidpogosian/ds/testutils"
+)
+
+// clock is a fake clock that only moves when advanced.
+type clock struct {
+	now time.Time
+	mu sync.Mutex
+}
+
+func (c *clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *clock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// newSetRing returns a Ring of Sets driven by a fake clock.
+func newSetRing(t *testing.T, buckets int) (*Ring[*set.Set[string]], *clock) {
+	r, err := New(buckets, time.Minute, func() *set.Set[string] { return set.NewEmpty[string]() })
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &clock{now: time.Unix(0, 0)}
+	r.now = c.Now
+	r.start = c.Now()
+	return r, c
+}
+
+// seen returns whether any bucket of the Ring contains item.
+func seen(r *Ring[*set.Set[string]], item string) bool {
+	return r.Any(func(s *set.Set[string]) bool { return s.Contains(item) })
+}
+
+func TestNew(t *testing.T) {
+	t.Run("Invalid", func(t *testing.T) {
+		factory := func() int { return 0 }
+		if _, err := New(0, time.Second, factory); err == nil {
+			t.Fatal("Created a Ring with 0 buckets.")
+		}
+		if _, err := New(1, 0, factory); err == nil {
+			t.Fatal("Created a Ring with an interval of 0.")
+		}
+	})
+
+	t.Run("Valid", func(t *testing.T) {
+		r, _ := newSetRing(t, 3)
+		testutils.Assert(t, "r.Size()", 3, r.Size())
+		testutils.Assert(t, "r.Interval()", time.Minute, r.Interval())
+		testutils.Assert(t, "r.Current().Size()", 0, r.Current().Size())
+	})
+}
+
+func TestExpiry(t *testing.T) {
+	r, c := newSetRing(t, 3)
+	r.Current().Add("a")
+	c.Advance(time.Minute)
+	r.Current().Add("b")
+	c.Advance(time.Minute)
+	testutils.Assert(t, "seen(\"a\")", true, seen(r, "a"))
+	c.Advance(time.Minute)
+	testutils.Assert(t, "seen(\"a\")", false, seen(r, "a"))
+	testutils.Assert(t, "seen(\"b\")", true, seen(r, "b"))
+	// Skipping more intervals than there are buckets empties the Ring.
+	c.Advance(10 * time.Minute)
+	testutils.Assert(t, "seen(\"b\")", false, seen(r, "b"))
+	// Rotation keeps to the original interval boundaries.
+	c.Advance(30 * time.Second)
+	r.Current().Add("c")
+	c.Advance(30 * time.Second)
+	testutils.Assert(t, "r.Current().Contains(\"c\")", false, r.Current().Contains("c"))
+}
+
+func TestBuckets(t *testing.T) {
+	r, c := newSetRing(t, 3)
+	for _, item := range []string{"a", "b", "c", "d"} {
+		r.Current().Add(item)
+		c.Advance(time.Minute)
+	}
+	r.Current().Add("e")
+	var newestFirst []string
+	for _, bucket := range r.Buckets() {
+		newestFirst = append(newestFirst, bucket.String())
+	}
+	testutils.AssertSlices(t, []string{"[e]", "[d]", "[c]"}, newestFirst)
+}
+
+func TestRotate(t *testing.T) {
+	r, _ := newSetRing(t, 2)
+	r.Current().Add("a")
+	r.Rotate()
+	testutils.Assert(t, "seen(\"a\")", true, seen(r, "a"))
+	r.Rotate()
+	testutils.Assert(t, "seen(\"a\")", false, seen(r, "a"))
+}
+
+func TestClear(t *testing.T) {
+	r, _ := newSetRing(t, 2)
+	r.Current().Add("a")
+	r.Clear()
+	testutils.Assert(t, "seen(\"a\")", false, seen(r, "a"))
+}
+
+func TestConcurrent(t *testing.T) {
+	r, c := newSetRing(t, 5)
+	testutils.ConcurrentOperations(t, 10, 100, func() error {
+		r.Current().Add("x")
+		c.Advance(time.Second)
+		seen(r, "x")
+		return nil
+	})
+	// 1000 seconds have passed, so only the last 4 to 5 minutes are remembered.
+	testutils.Assert(t, "seen(\"x\")", true, seen(r, "x"))
+	c.Advance(5 * time.Minute)
+	testutils.Assert(t, "seen(\"x\")", false, seen(r, "x"))
+}
@@ -0,0 +1,174 @@
+// Package timerwheel provides a thread-safe hashed timer wheel.
+package timerwheel
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/davidpogosian/ds/list"
+)
+
+// Timer struct represents a callback scheduled on a Wheel.
+// It is returned by Schedule and can be passed to Cancel.
+// Timer has fields for the tick it expires at, its deadline, the callback,
+// and the Wheel it was scheduled on.
+type Timer struct {
+	list.Hook[Timer]
+	expiry uint64
+	deadline time.Time
+	fn func()
+	wheel *Wheel
+}
+
+// Deadline returns the time the Timer was scheduled to fire at.
+// The callback is called by the first Advance at or after it, rounded up to a whole tick.
+func (t *Timer) Deadline() time.Time {
+	return t.deadline
+}
+
+// Wheel struct represents a hashed timer wheel: a ring of slots, each covering one tick,
+// holding the timers that expire at a tick that maps to it. Scheduling and cancelling
+// a timer cost O(1), instead of O(log n) for a heap, which suits millions of pending
+// timeouts that are mostly cancelled before they fire (e.g. request timeouts).
+// Timers further away than one turn of the wheel share a slot with nearer ones and are
+// skipped until their turn comes. Deadlines are rounded up to whole ticks.
+// The Wheel has no goroutine: time only moves when Advance is called.
+// Wheel has fields for the slots, the number of ticks processed, the time the current
+// tick started, the tick duration, the number of pending timers, and a mutex for thread-safety.
+type Wheel struct {
+	slots []*list.Intrusive[Timer, *Timer]
+	ticks uint64
+	current time.Time
+	tick time.Duration
+	size int
+	mu sync.Mutex
+}
+
+// New returns a pointer to a new Wheel of the given number of slots, each covering tick,
+// whose time starts at start.
+// An error is returned if slots or tick is not positive.
+func New(slots int, tick time.Duration, start time.Time) (*Wheel, error) {
+	if slots <= 0 {
+		return nil, fmt.Errorf("Cannot create a Wheel with %d slots.", slots)
+	}
+	if tick <= 0 {
+		return nil, fmt.Errorf("Cannot create a Wheel with a tick of %v.", tick)
+	}
+	w := &Wheel{
+		slots: make([]*list.Intrusive[Timer, *Timer], slots),
+		current: start,
+		tick: tick,
+	}
+	for i := range w.slots {
+		w.slots[i] = list.NewIntrusive[Timer]()
+	}
+	return w, nil
+}
+
+// Schedule schedules fn to be called by Advance once d has passed since the current time
+// of the Wheel, and returns the Timer, which can be passed to Cancel.
+// A d of 0 or less fires on the next tick.
+func (w *Wheel) Schedule(d time.Duration, fn func()) *Timer {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	ticks := uint64(1)
+	if d > 0 {
+		ticks = uint64((d + w.tick - 1) / w.tick)
+	}
+	t := &Timer{
+		expiry: w.ticks + ticks,
+		deadline: w.current.Add(max(d, 0)),
+		fn: fn,
+		wheel: w,
+	}
+	w.slots[t.expiry % uint64(len(w.slots))].PushBack(t)
+	w.size++
+	return t
+}
+
+// Cancel cancels t in O(1), and returns a bool indicating whether it was still pending.
+// Cancelling a Timer that already fired, was already cancelled, or belongs to another
+// Wheel returns false.
+func (w *Wheel) Cancel(t *Timer) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if t == nil || t.wheel != w {
+		return false
+	}
+	if w.slots[t.expiry % uint64(len(w.slots))].Remove(t) != nil {
+		return false
+	}
+	w.size--
+	return true
+}
+
+// Advance moves the time of the Wheel forward to now, calls the callbacks of every timer
+// that expired on the way, in the order they expired, and returns how many were called.
+// The callbacks are called after the Wheel is unlocked, so they may Schedule or Cancel timers.
+// If now is before the current time of the Wheel, nothing happens.
+func (w *Wheel) Advance(now time.Time) int {
+	fns := w.expire(now)
+	for _, fn := range fns {
+		if fn != nil {
+			fn()
+		}
+	}
+	return len(fns)
+}
+
+// expire processes every tick that ended at or before now, removing the timers that expired,
+// and returns their callbacks.
+func (w *Wheel) expire(now time.Time) []func() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	var fns []func()
+	for !now.Before(w.current.Add(w.tick)) {
+		if w.size == 0 {
+			// Nothing to fire, so jump straight to the tick containing now.
+			ticks := now.Sub(w.current) / w.tick
+			w.ticks += uint64(ticks)
+			w.current = w.current.Add(ticks * w.tick)
+			break
+		}
+		w.ticks++
+		w.current = w.current.Add(w.tick)
+		slot := w.slots[w.ticks % uint64(len(w.slots))]
+		for t := slot.Front(); t != nil; {
+			next := slot.Next(t)
+			if t.expiry <= w.ticks {
+				slot.Remove(t)
+				w.size--
+				fns = append(fns, t.fn)
+			}
+			t = next
+		}
+	}
+	return fns
+}
+
+// Size returns the number of pending timers in the Wheel.
+func (w *Wheel) Size() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.size
+}
+
+// IsEmpty returns a bool indicating whether the Wheel has no pending timers.
+func (w *Wheel) IsEmpty() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.size == 0
+}
+
+// Now returns the start of the current tick of the Wheel.
+func (w *Wheel) Now() time.Time {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.current
+}
+
+// Tick returns the duration of one tick of the Wheel.
+func (w *Wheel) Tick() time.Duration {
+	return w.tick
+}
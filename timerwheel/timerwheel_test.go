@@ -0,0 +1,119 @@
+package timerwheel
+
+import (
+	"testing"
+	"time"
+
+	"github.com/davidpogosian/ds/testutils"
+)
+
+var start = time.Unix(0, 0)
+
+// newWheel returns a Wheel of 8 slots of one second each, starting at start.
+func newWheel(t *testing.T) *Wheel {
+	w, err := New(8, time.Second, start)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return w
+}
+
+func TestNew(t *testing.T) {
+	w := newWheel(t)
+	testutils.Assert(t, "w.Size()", 0, w.Size())
+	testutils.Assert(t, "w.IsEmpty()", true, w.IsEmpty())
+	testutils.Assert(t, "w.Tick()", time.Second, w.Tick())
+	if _, err := New(0, time.Second, start); err == nil {
+		t.Fatal("Created a Wheel with 0 slots.")
+	}
+	if _, err := New(8, 0, start); err == nil {
+		t.Fatal("Created a Wheel with a tick of 0.")
+	}
+}
+
+func TestSchedule(t *testing.T) {
+	w := newWheel(t)
+	fired := []int{}
+	for _, seconds := range []int{3, 1, 20, 2, 0} {
+		w.Schedule(time.Duration(seconds) * time.Second, func() { fired = append(fired, seconds) })
+	}
+	testutils.Assert(t, "w.Size()", 5, w.Size())
+	testutils.Assert(t, "w.Advance(start.Add(500 * time.Millisecond))", 0, w.Advance(start.Add(500 * time.Millisecond)))
+	testutils.Assert(t, "w.Advance(start.Add(time.Second))", 2, w.Advance(start.Add(time.Second)))
+	testutils.AssertSlices(t, []int{1, 0}, fired)
+	testutils.Assert(t, "w.Advance(start.Add(10 * time.Second))", 2, w.Advance(start.Add(10 * time.Second)))
+	testutils.AssertSlices(t, []int{1, 0, 2, 3}, fired)
+	// 20 seconds is more than one turn of the wheel.
+	testutils.Assert(t, "w.Advance(start.Add(19 * time.Second))", 0, w.Advance(start.Add(19 * time.Second)))
+	testutils.Assert(t, "w.Advance(start.Add(20 * time.Second))", 1, w.Advance(start.Add(20 * time.Second)))
+	testutils.AssertSlices(t, []int{1, 0, 2, 3, 20}, fired)
+	testutils.Assert(t, "w.IsEmpty()", true, w.IsEmpty())
+}
+
+func TestScheduleRoundsUp(t *testing.T) {
+	w := newWheel(t)
+	timer := w.Schedule(1500 * time.Millisecond, nil)
+	testutils.Assert(t, "timer.Deadline()", start.Add(1500 * time.Millisecond), timer.Deadline())
+	testutils.Assert(t, "w.Advance(start.Add(1999 * time.Millisecond))", 0, w.Advance(start.Add(1999 * time.Millisecond)))
+	testutils.Assert(t, "w.Advance(start.Add(2 * time.Second))", 1, w.Advance(start.Add(2 * time.Second)))
+}
+
+func TestCancel(t *testing.T) {
+	w := newWheel(t)
+	fired := 0
+	a := w.Schedule(time.Second, func() { fired++ })
+	b := w.Schedule(time.Second, func() { fired++ })
+	testutils.Assert(t, "w.Cancel(a)", true, w.Cancel(a))
+	testutils.Assert(t, "w.Cancel(a)", false, w.Cancel(a))
+	testutils.Assert(t, "w.Size()", 1, w.Size())
+	w.Advance(start.Add(time.Second))
+	testutils.Assert(t, "fired", 1, fired)
+	testutils.Assert(t, "w.Cancel(b)", false, w.Cancel(b))
+	other := newWheel(t)
+	c := other.Schedule(time.Second, nil)
+	testutils.Assert(t, "w.Cancel(c)", false, w.Cancel(c))
+	testutils.Assert(t, "w.Cancel(nil)", false, w.Cancel(nil))
+}
+
+func TestAdvance(t *testing.T) {
+	t.Run("Backwards", func(t *testing.T) {
+		w := newWheel(t)
+		w.Advance(start.Add(5 * time.Second))
+		testutils.Assert(t, "w.Now()", start.Add(5 * time.Second), w.Now())
+		w.Advance(start)
+		testutils.Assert(t, "w.Now()", start.Add(5 * time.Second), w.Now())
+	})
+	t.Run("EmptyJump", func(t *testing.T) {
+		w := newWheel(t)
+		w.Advance(start.Add(1000 * time.Hour + 500 * time.Millisecond))
+		testutils.Assert(t, "w.Now()", start.Add(1000 * time.Hour), w.Now())
+		fired := false
+		w.Schedule(time.Second, func() { fired = true })
+		w.Advance(start.Add(1000 * time.Hour + time.Second))
+		testutils.Assert(t, "fired", true, fired)
+	})
+	t.Run("Reschedule", func(t *testing.T) {
+		w := newWheel(t)
+		count := 0
+		var tick func()
+		tick = func() {
+			count++
+			w.Schedule(time.Second, tick)
+		}
+		w.Schedule(time.Second, tick)
+		for i := 1; i <= 5; i++ {
+			w.Advance(start.Add(time.Duration(i) * time.Second))
+		}
+		testutils.Assert(t, "count", 5, count)
+		testutils.Assert(t, "w.Size()", 1, w.Size())
+	})
+	t.Run("Concurrent", func(t *testing.T) {
+		w := newWheel(t)
+		testutils.ConcurrentOperations(t, 10, 100, func() error {
+			w.Cancel(w.Schedule(time.Second, nil))
+			w.Schedule(time.Second, nil)
+			return nil
+		})
+		testutils.Assert(t, "w.Advance(start.Add(time.Second))", 1000, w.Advance(start.Add(time.Second)))
+	})
+}
@@ -0,0 +1,482 @@
+// Package unrolledlist provides a thread-safe, generic unrolled linked list implementation.
+package unrolledlist
+
+import (
+	"fmt"
+	"iter"
+	"slices"
+	"sync"
+	"unsafe"
+
+	"github.com/davidpogosian/ds/comparators"
+	"github.com/davidpogosian/ds/internal/format"
+)
+
+// DefaultBlockSize is the number of items per block used by NewEmpty and NewFromSlice.
+const DefaultBlockSize = 64
+
+// block struct represents a node of the List holding up to blockSize items.
+// It has a slice of items and pointers to the previous and the next block.
+type block[T any] struct {
+	items []T
+	next *block[T]
+	prev *block[T]
+}
+
+// List struct represents an unrolled linked list: a doubly-linked list of blocks,
+// each storing up to blockSize items in a contiguous slice. Compared to list.List
+// this means far fewer allocations and pointers to chase per item.
+// It has pointers to the front and the back block, a field to keep track of the
+// number of items, the block size, a comparator function to compare elements,
+// a limit on the number of items printed by String (0 means no limit),
+// and a mutex for thread-safety.
+type List[T any] struct {
+	front *block[T]
+	back *block[T]
+	size int
+	blockSize int
+	comparator comparators.Comparator[T]
+	stringLimit int
+	mu sync.Mutex
+}
+
+// NewEmpty returns a pointer to a new empty List with DefaultBlockSize.
+// NewEmpty requires a comparator function to compare elements.
+// For built-in types, the comparators package provides ready-made comparators
+// (e.g., comparators.CompareInt for int).
+// Custom types will require a user-defined comparator.
+func NewEmpty[T any](comparator comparators.Comparator[T]) *List[T] {
+	return &List[T]{blockSize: DefaultBlockSize, comparator: comparator}
+}
+
+// NewWithBlockSize returns a pointer to a new empty List storing up to blockSize items per block.
+// Larger blocks improve locality but make inserts and removals in the middle of a block slower.
+// If blockSize is less than 1, an error is returned.
+func NewWithBlockSize[T any](blockSize int, comparator comparators.Comparator[T]) (*List[T], error) {
+	if blockSize < 1 {
+		return nil, fmt.Errorf("Cannot create a List with a block size of %d.", blockSize)
+	}
+	return &List[T]{blockSize: blockSize, comparator: comparator}, nil
+}
+
+// NewFromSlice returns a pointer to a new List with DefaultBlockSize initialized with a slice.
+// NewFromSlice requires a comparator function to compare elements.
+// For built-in types, the comparators package provides ready-made comparators
+// (e.g., comparators.CompareInt for int).
+// Custom types will require a user-defined comparator.
+func NewFromSlice[T any](slice []T, comparator comparators.Comparator[T]) *List[T] {
+	l := NewEmpty(comparator)
+	for _, item := range slice {
+		l.insertBack(item)
+	}
+	return l
+}
+
+// newBlock returns a new empty block with room for blockSize items.
+func (l *List[T]) newBlock() *block[T] {
+	return &block[T]{items: make([]T, 0, l.blockSize)}
+}
+
+// linkAfter links block b into the List after mark.
+// If mark is nil, b becomes the front block.
+func (l *List[T]) linkAfter(mark *block[T], b *block[T]) {
+	b.prev = mark
+	if mark == nil {
+		b.next = l.front
+		l.front = b
+	} else {
+		b.next = mark.next
+		mark.next = b
+	}
+	if b.next == nil {
+		l.back = b
+	} else {
+		b.next.prev = b
+	}
+}
+
+// unlinkBlock removes block b from the List.
+// The pointers of b are left intact so that a live iterator positioned at b can move on.
+func (l *List[T]) unlinkBlock(b *block[T]) {
+	if b.prev == nil {
+		l.front = b.next
+	} else {
+		b.prev.next = b.next
+	}
+	if b.next == nil {
+		l.back = b.prev
+	} else {
+		b.next.prev = b.prev
+	}
+}
+
+// locate returns the block holding the item at the given valid, nonnegative index
+// and the offset of the item within that block.
+// It walks from whichever end of the List is closer.
+func (l *List[T]) locate(index int) (*block[T], int) {
+	if index < l.size / 2 {
+		b := l.front
+		for index >= len(b.items) {
+			index -= len(b.items)
+			b = b.next
+		}
+		return b, index
+	}
+	fromBack := l.size - 1 - index
+	b := l.back
+	for fromBack >= len(b.items) {
+		fromBack -= len(b.items)
+		b = b.prev
+	}
+	return b, len(b.items) - 1 - fromBack
+}
+
+// insertFront inserts new item at the front of the List.
+func (l *List[T]) insertFront(newItem T) {
+	if l.front == nil || len(l.front.items) == l.blockSize {
+		l.linkAfter(nil, l.newBlock())
+	}
+	l.front.items = slices.Insert(l.front.items, 0, newItem)
+	l.size++
+}
+
+// insertBack inserts new item at the back of the List.
+func (l *List[T]) insertBack(newItem T) {
+	if l.back == nil || len(l.back.items) == l.blockSize {
+		l.linkAfter(l.back, l.newBlock())
+	}
+	l.back.items = append(l.back.items, newItem)
+	l.size++
+}
+
+// insertAt inserts new item at the given valid, nonnegative index.
+// A full block is split in half to make room.
+func (l *List[T]) insertAt(index int, newItem T) {
+	if index == l.size {
+		l.insertBack(newItem)
+		return
+	}
+	b, offset := l.locate(index)
+	if len(b.items) == l.blockSize {
+		half := len(b.items) / 2
+		upper := l.newBlock()
+		upper.items = append(upper.items, b.items[half:]...)
+		clear(b.items[half:])
+		b.items = b.items[:half]
+		l.linkAfter(b, upper)
+		if offset > half {
+			b = upper
+			offset -= half
+		}
+	}
+	b.items = slices.Insert(b.items, offset, newItem)
+	l.size++
+}
+
+// removeAt removes and returns the item at the given valid, nonnegative index.
+// A block that drops below half full is merged with the next block if they fit in one.
+func (l *List[T]) removeAt(index int) T {
+	b, offset := l.locate(index)
+	item := b.items[offset]
+	last := len(b.items) - 1
+	copy(b.items[offset:], b.items[offset + 1:])
+	var zeroValue T
+	b.items[last] = zeroValue
+	b.items = b.items[:last]
+	l.size--
+	if len(b.items) == 0 {
+		l.unlinkBlock(b)
+	} else if len(b.items) < l.blockSize / 2 && b.next != nil && len(b.items) + len(b.next.items) <= l.blockSize {
+		next := b.next
+		b.items = append(b.items, next.items...)
+		l.unlinkBlock(next)
+	}
+	return item
+}
+
+// InsertFront inserts new item at the front of the List.
+func (l *List[T]) InsertFront(newItem T) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.insertFront(newItem)
+}
+
+// InsertBack inserts new item at the back of the List.
+func (l *List[T]) InsertBack(newItem T) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.insertBack(newItem)
+}
+
+// InsertPosition inserts new item at the specified position.
+// A negative position counts from the back, like in Python: -1 inserts before the last item.
+// If the position is invalid (aka if position < -List.size || position > List.size), an error is returned.
+func (l *List[T]) InsertPosition(newItem T, position int) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	index := position
+	if index < 0 {
+		index += l.size
+	}
+	if index < 0 || index > l.size {
+		return fmt.Errorf("Cannot insert into a List of size %d at index %d.", l.size, position)
+	}
+	l.insertAt(index, newItem)
+	return nil
+}
+
+// Get returns an item from the specified index of the List.
+// A negative index counts from the back, like in Python: -1 is the last item.
+// If the index is invalid (aka index < -List.size || index >= List.size), an error is returned.
+func (l *List[T]) Get(index int) (T, error) {
+	item, ok := l.TryGet(index)
+	if !ok {
+		return item, fmt.Errorf("Cannot access index %d in a List of size %d.", index, l.Size())
+	}
+	return item, nil
+}
+
+// TryGet returns an item from the specified index of the List and true,
+// or returns false if the index is invalid. Negative indices count from the back, like in Get.
+func (l *List[T]) TryGet(index int) (T, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	i := index
+	if i < 0 {
+		i += l.size
+	}
+	if i < 0 || i >= l.size {
+		var zeroValue T
+		return zeroValue, false
+	}
+	b, offset := l.locate(i)
+	return b.items[offset], true
+}
+
+// RemoveFront removes the item at the front of the List.
+// If the List is empty, an error is returned.
+func (l *List[T]) RemoveFront() (T, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.size == 0 {
+		var zeroValue T
+		return zeroValue, fmt.Errorf("Cannot remove the front item from an empty List.")
+	}
+	return l.removeAt(0), nil
+}
+
+// RemoveBack removes the item at the back of the List.
+// If the List is empty, an error is returned.
+func (l *List[T]) RemoveBack() (T, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.size == 0 {
+		var zeroValue T
+		return zeroValue, fmt.Errorf("Cannot remove the back item from an empty List.")
+	}
+	return l.removeAt(l.size - 1), nil
+}
+
+// RemovePosition removes the item at given index from the List.
+// A negative index counts from the back, like in Python: -1 is the last item.
+// If the index is invalid (aka index < -List.size || index >= List.size), an error is returned.
+func (l *List[T]) RemovePosition(index int) (T, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	i := index
+	if i < 0 {
+		i += l.size
+	}
+	if i < 0 || i >= l.size {
+		var zeroValue T
+		return zeroValue, fmt.Errorf("Cannot remove item at index %d in a List of size %d.", index, l.size)
+	}
+	return l.removeAt(i), nil
+}
+
+// Find returns the index of the first occurence of the given item in the List.
+// If the item is not found in the List, -1 is returned.
+func (l *List[T]) Find(item T) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	index := 0
+	for b := l.front; b != nil; b = b.next {
+		for _, candidate := range b.items {
+			if l.comparator(candidate, item) == 0 {
+				return index
+			}
+			index++
+		}
+	}
+	return -1
+}
+
+// Contains returns true if the item is in the List.
+func (l *List[T]) Contains(item T) bool {
+	return l.Find(item) != -1
+}
+
+// Size returns the number of items in the List.
+func (l *List[T]) Size() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.size
+}
+
+// IsEmpty returns a bool indicating whether or not the List is empty.
+func (l *List[T]) IsEmpty() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.size == 0
+}
+
+// BlockSize returns the maximum number of items stored per block.
+func (l *List[T]) BlockSize() int {
+	return l.blockSize
+}
+
+// Clear removes all items from the List.
+func (l *List[T]) Clear() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.front = nil
+	l.back = nil
+	l.size = 0
+}
+
+// toSlice returns the items of the List as a new slice.
+func (l *List[T]) toSlice() []T {
+	s := make([]T, 0, l.size)
+	for b := l.front; b != nil; b = b.next {
+		s = append(s, b.items...)
+	}
+	return s
+}
+
+// ToSlice returns the List as a slice.
+func (l *List[T]) ToSlice() []T {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.toSlice()
+}
+
+// Copy returns a pointer to a copy of the List.
+// The copy has the same block size, with every block but the last one full.
+func (l *List[T]) Copy() *List[T] {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	newList := &List[T]{blockSize: l.blockSize, comparator: l.comparator, stringLimit: l.stringLimit}
+	for b := l.front; b != nil; b = b.next {
+		for _, item := range b.items {
+			newList.insertBack(item)
+		}
+	}
+	return newList
+}
+
+// Reverse reverses the order of the items in the List.
+func (l *List[T]) Reverse() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for b := l.front; b != nil; b = b.prev {
+		slices.Reverse(b.items)
+		b.next, b.prev = b.prev, b.next
+	}
+	l.front, l.back = l.back, l.front
+}
+
+// All returns an iterator over the items of the List from front to back.
+//
+// The iterator is live rather than a snapshot: the lock is only held while
+// copying one block at a time, never while the loop body runs, and it then
+// moves on to whatever block follows that one at the time.
+// The loop body may therefore call methods on the List (including ones that
+// modify it) without deadlocking, but modifications made meanwhile, by the loop
+// body or by other goroutines, may disturb the iteration beyond new items being
+// seen or not: an insertion that splits the current block moves its upper half
+// to a new block after it, so those items are yielded again, and a removal that
+// merges the next block into the current one moves the next block's items
+// behind the iterator, so they are skipped.
+// For a consistent view, iterate over Copy() instead.
+func (l *List[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		l.mu.Lock()
+		b := l.front
+		for b != nil {
+			items := slices.Clone(b.items)
+			l.mu.Unlock()
+			for _, item := range items {
+				if !yield(item) {
+					return
+				}
+			}
+			l.mu.Lock()
+			b = b.next
+		}
+		l.mu.Unlock()
+	}
+}
+
+// String returns the string representation of the List.
+// If a limit was set with SetStringLimit, only that many items are printed.
+func (l *List[T]) String() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.stringLimit > 0 {
+		return l.stringN(l.stringLimit)
+	}
+	return l.stringN(-1)
+}
+
+// StringN returns the string representation of the first max items of the List,
+// followed by "… (+N more)" if items were left out.
+// If max is negative, all items are printed.
+func (l *List[T]) StringN(max int) string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.stringN(max)
+}
+
+// stringN returns the string representation of the first max items of the List.
+func (l *List[T]) stringN(max int) string {
+	limit := format.Limit(l.size, max)
+	items := make([]T, 0, limit)
+	for b := l.front; b != nil && len(items) < limit; b = b.next {
+		items = append(items, b.items[:min(len(b.items), limit - len(items))]...)
+	}
+	return format.Truncated(items, l.size - limit)
+}
+
+// SetStringLimit limits String to printing the first max items of the List.
+// A max of 0 or less removes the limit.
+func (l *List[T]) SetStringLimit(max int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.stringLimit = max
+}
+
+// MemUsage returns an approximation of the number of bytes used by the List,
+// including its blocks. Memory referenced by the items themselves
+// (e.g. the contents of strings) is not counted; use MemUsageFunc for that.
+func (l *List[T]) MemUsage() int64 {
+	return l.MemUsageFunc(nil)
+}
+
+// MemUsageFunc is like MemUsage, but also adds the result of calling size on every item.
+// It should return the bytes referenced by the item beyond its fixed size.
+func (l *List[T]) MemUsageFunc(size func(T) int64) int64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var b block[T]
+	var item T
+	total := int64(unsafe.Sizeof(*l))
+	for cursor := l.front; cursor != nil; cursor = cursor.next {
+		total += int64(unsafe.Sizeof(b)) + int64(cap(cursor.items)) * int64(unsafe.Sizeof(item))
+		if size != nil {
+			for _, item := range cursor.items {
+				total += size(item)
+			}
+		}
+	}
+	return total
+}
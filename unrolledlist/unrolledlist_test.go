@@ -0,0 +1,211 @@
+package unrolledlist
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/davidpogosian/ds/comparators"
+	"github.com/davidpogosian/ds/testutils"
+)
+
+func TestNewWithBlockSize(t *testing.T) {
+	if _, err := NewWithBlockSize[int](0, comparators.ComparatorInt); err == nil {
+		t.Fatal("Created a List with a block size of 0.")
+	}
+	l, err := NewWithBlockSize[int](8, comparators.ComparatorInt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutils.Assert(t, "l.BlockSize()", 8, l.BlockSize())
+	testutils.Assert(t, "l.Size()", 0, l.Size())
+}
+
+func TestInsert(t *testing.T) {
+	t.Run("Sequential", func(t *testing.T) {
+		l, _ := NewWithBlockSize[int](2, comparators.ComparatorInt)
+		l.InsertBack(2)
+		l.InsertBack(3)
+		l.InsertFront(1)
+		l.InsertFront(0)
+		if err := l.InsertPosition(9, 2); err != nil {
+			t.Fatal(err)
+		}
+		if err := l.InsertPosition(8, -1); err != nil {
+			t.Fatal(err)
+		}
+		if err := l.InsertPosition(7, 7); err == nil {
+			t.Fatal("Inserted at an invalid position.")
+		}
+		testutils.AssertSlices(t, []int{0, 1, 9, 2, 8, 3}, l.ToSlice())
+	})
+
+	t.Run("Concurrent", func(t *testing.T) {
+		l, _ := NewWithBlockSize[int](4, comparators.ComparatorInt)
+		testutils.ConcurrentOperations(t, 10, 100, func() error {
+			l.InsertBack(1)
+			return l.InsertPosition(2, l.Size() / 2)
+		})
+		testutils.Assert(t, "l.Size()", 2000, l.Size())
+	})
+}
+
+func TestRemove(t *testing.T) {
+	t.Run("Empty", func(t *testing.T) {
+		l := NewEmpty[int](comparators.ComparatorInt)
+		if _, err := l.RemoveFront(); err == nil {
+			t.Fatal("Removed the front of an empty List.")
+		}
+		if _, err := l.RemoveBack(); err == nil {
+			t.Fatal("Removed the back of an empty List.")
+		}
+		if _, err := l.RemovePosition(0); err == nil {
+			t.Fatal("Removed a position from an empty List.")
+		}
+	})
+
+	t.Run("NotEmpty", func(t *testing.T) {
+		l, _ := NewWithBlockSize[int](3, comparators.ComparatorInt)
+		for i := 0; i < 10; i++ {
+			l.InsertBack(i)
+		}
+		front, _ := l.RemoveFront()
+		back, _ := l.RemoveBack()
+		middle, _ := l.RemovePosition(-4)
+		testutils.Assert(t, "front", 0, front)
+		testutils.Assert(t, "back", 9, back)
+		testutils.Assert(t, "middle", 5, middle)
+		testutils.AssertSlices(t, []int{1, 2, 3, 4, 6, 7, 8}, l.ToSlice())
+	})
+
+	t.Run("Concurrent", func(t *testing.T) {
+		l := NewFromSlice(make([]int, 2000), comparators.ComparatorInt)
+		testutils.ConcurrentOperations(t, 10, 100, func() error {
+			if _, err := l.RemoveFront(); err != nil {
+				return err
+			}
+			_, err := l.RemoveBack()
+			return err
+		})
+		testutils.Assert(t, "l.Size()", 0, l.Size())
+	})
+}
+
+func TestRandomOperations(t *testing.T) {
+	for _, blockSize := range []int{1, 2, 5, 16} {
+		rng := rand.New(rand.NewSource(int64(blockSize)))
+		l, _ := NewWithBlockSize[int](blockSize, comparators.ComparatorInt)
+		var expected []int
+		for i := 0; i < 2000; i++ {
+			if len(expected) == 0 || rng.Intn(3) > 0 {
+				index := rng.Intn(len(expected) + 1)
+				l.InsertPosition(i, index)
+				expected = slices.Insert(expected, index, i)
+			} else {
+				index := rng.Intn(len(expected))
+				got, err := l.RemovePosition(index)
+				if err != nil {
+					t.Fatal(err)
+				}
+				testutils.Assert(t, "got", expected[index], got)
+				expected = slices.Delete(expected, index, index + 1)
+			}
+		}
+		testutils.AssertSlices(t, expected, l.ToSlice())
+		for i := range expected {
+			got, _ := l.Get(i)
+			testutils.Assert(t, "got", expected[i], got)
+		}
+	}
+}
+
+func TestTryGet(t *testing.T) {
+	l, _ := NewWithBlockSize[int](2, comparators.ComparatorInt)
+	for i := 0; i < 5; i++ {
+		l.InsertBack(i)
+	}
+	for index, expected := range map[int]int{0: 0, 3: 3, -1: 4, -5: 0} {
+		item, ok := l.TryGet(index)
+		testutils.Assert(t, "ok", true, ok)
+		testutils.Assert(t, "item", expected, item)
+	}
+	for _, index := range []int{5, -6} {
+		if _, ok := l.TryGet(index); ok {
+			t.Fatalf("Got index %d of a List of size 5.", index)
+		}
+	}
+}
+
+func TestFind(t *testing.T) {
+	l, _ := NewWithBlockSize[int](2, comparators.ComparatorInt)
+	for _, item := range []int{5, 6, 7, 6} {
+		l.InsertBack(item)
+	}
+	testutils.Assert(t, "l.Find(6)", 1, l.Find(6))
+	testutils.Assert(t, "l.Find(7)", 2, l.Find(7))
+	testutils.Assert(t, "l.Find(8)", -1, l.Find(8))
+	testutils.Assert(t, "l.Contains(5)", true, l.Contains(5))
+}
+
+func TestReverse(t *testing.T) {
+	l, _ := NewWithBlockSize[int](3, comparators.ComparatorInt)
+	for i := 0; i < 8; i++ {
+		l.InsertBack(i)
+	}
+	l.Reverse()
+	testutils.AssertSlices(t, []int{7, 6, 5, 4, 3, 2, 1, 0}, l.ToSlice())
+	l.InsertBack(-1)
+	testutils.AssertSlices(t, []int{7, 6, 5, 4, 3, 2, 1, 0, -1}, l.ToSlice())
+}
+
+func TestCopy(t *testing.T) {
+	l1 := NewFromSlice([]int{1, 2, 3}, comparators.ComparatorInt)
+	l2 := l1.Copy()
+	l1.InsertBack(4)
+	testutils.AssertSlices(t, []int{1, 2, 3}, l2.ToSlice())
+	testutils.Assert(t, "l2.BlockSize()", l1.BlockSize(), l2.BlockSize())
+}
+
+func TestClear(t *testing.T) {
+	l := NewFromSlice([]int{1, 2, 3}, comparators.ComparatorInt)
+	l.Clear()
+	testutils.Assert(t, "l.IsEmpty()", true, l.IsEmpty())
+	l.InsertFront(1)
+	testutils.AssertSlices(t, []int{1}, l.ToSlice())
+}
+
+func TestAll(t *testing.T) {
+	l, _ := NewWithBlockSize[int](2, comparators.ComparatorInt)
+	for i := 0; i < 5; i++ {
+		l.InsertBack(i)
+	}
+	testutils.AssertSlices(t, []int{0, 1, 2, 3, 4}, slices.Collect(l.All()))
+	for range l.All() {
+		l.RemoveFront()
+	}
+	testutils.Assert(t, "l.Size()", 0, l.Size())
+}
+
+func TestString(t *testing.T) {
+	l, _ := NewWithBlockSize[int](2, comparators.ComparatorInt)
+	testutils.Assert(t, "l.String()", "[]", l.String())
+	for i := 1; i <= 5; i++ {
+		l.InsertBack(i)
+	}
+	testutils.Assert(t, "l.String()", "[1 2 3 4 5]", l.String())
+	testutils.Assert(t, "l.StringN(3)", "[1 2 3 … (+2 more)]", l.StringN(3))
+	l.SetStringLimit(1)
+	testutils.Assert(t, "l.String()", "[1 … (+4 more)]", l.String())
+}
+
+func TestMemUsage(t *testing.T) {
+	l, _ := NewWithBlockSize[int](4, comparators.ComparatorInt)
+	empty := l.MemUsage()
+	l.InsertBack(1)
+	oneBlock := l.MemUsage()
+	if oneBlock <= empty {
+		t.Fatal("Expected MemUsage to grow after inserting.")
+	}
+	l.InsertBack(2)
+	testutils.Assert(t, "l.MemUsage()", oneBlock, l.MemUsage())
+}
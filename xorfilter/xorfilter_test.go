@@ -0,0 +1,143 @@
+package xorfilter
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/davidpogosian/ds/testutils"
+)
+
+func randomKeys(seed int64, n int) []uint64 {
+	rng := rand.New(rand.NewSource(seed))
+	keys := make([]uint64, n)
+	for i := range keys {
+		keys[i] = rng.Uint64()
+	}
+	return keys
+}
+
+func TestBuild(t *testing.T) {
+	t.Run("Empty", func(t *testing.T) {
+		f, err := Build(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		falsePositives := 0
+		for _, key := range randomKeys(1, 1000) {
+			if f.Contains(key) {
+				falsePositives++
+			}
+		}
+		if falsePositives > 50 {
+			t.Fatalf("Expected few false positives in an empty Filter, instead got: %d", falsePositives)
+		}
+	})
+
+	t.Run("Duplicates", func(t *testing.T) {
+		f, err := Build([]uint64{1, 2, 2, 3, 3, 3})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, key := range []uint64{1, 2, 3} {
+			testutils.Assert(t, "f.Contains(key)", true, f.Contains(key))
+		}
+	})
+
+	t.Run("Deterministic", func(t *testing.T) {
+		keys := randomKeys(2, 1000)
+		a, _ := Build(keys)
+		b, _ := Build(keys)
+		dataA, _ := a.MarshalBinary()
+		dataB, _ := b.MarshalBinary()
+		testutils.AssertSlices(t, dataA, dataB)
+	})
+}
+
+func TestContains(t *testing.T) {
+	keys := randomKeys(3, 100000)
+	f, err := Build(keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range keys {
+		if !f.Contains(key) {
+			t.Fatalf("False negative for key %d.", key)
+		}
+	}
+	falsePositives := 0
+	others := randomKeys(4, 100000)
+	for _, key := range others {
+		if f.Contains(key) {
+			falsePositives++
+		}
+	}
+	rate := float64(falsePositives) / float64(len(others))
+	if rate > 0.006 {
+		t.Fatalf("Expected a false positive rate of about 0.4%%, instead got: %f", rate)
+	}
+	bitsPerKey := float64(8 * len(f.fingerprints)) / float64(len(keys))
+	if bitsPerKey > 10 {
+		t.Fatalf("Expected under 10 bits per key, instead got: %f", bitsPerKey)
+	}
+}
+
+func TestMarshalBinary(t *testing.T) {
+	keys := randomKeys(5, 1000)
+	f, _ := Build(keys)
+	data, err := f.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var restored Filter
+	if err := restored.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range keys {
+		testutils.Assert(t, "restored.Contains(key)", true, restored.Contains(key))
+	}
+	if err := restored.UnmarshalBinary(data[:len(data) - 1]); err == nil {
+		t.Fatal("Decoded a truncated Filter.")
+	}
+	if err := restored.UnmarshalBinary(nil); err == nil {
+		t.Fatal("Decoded an empty Filter.")
+	}
+}
+
+func TestUnmarshalBinaryMalformed(t *testing.T) {
+	keys := randomKeys(8, 100)
+	f, _ := Build(keys)
+	data, _ := f.MarshalBinary()
+	zeroBlocks := slices.Clone(data[:headerSize])
+	clear(zeroBlocks[8:])
+	malformed := map[string][]byte{
+		"zero block length": zeroBlocks,
+		"header only": data[:headerSize],
+		"truncated": data[:len(data) - 1],
+		"too long": append(slices.Clone(data), 0),
+		"short": data[:headerSize - 1],
+	}
+	for name, input := range malformed {
+		restored, _ := Build(keys)
+		if err := restored.UnmarshalBinary(input); err == nil {
+			t.Fatalf("Decoded a %s Filter.", name)
+		}
+		// A failed decode leaves the Filter as it was.
+		for _, key := range keys {
+			testutils.Assert(t, "restored.Contains(key)", true, restored.Contains(key))
+		}
+		var zero Filter
+		if err := zero.UnmarshalBinary(input); err == nil {
+			t.Fatalf("Decoded a %s Filter.", name)
+		}
+		testutils.Assert(t, "zero.Contains(keys[0])", false, zero.Contains(keys[0]))
+	}
+}
+
+func TestMemUsage(t *testing.T) {
+	small, _ := Build(randomKeys(6, 10))
+	large, _ := Build(randomKeys(7, 10000))
+	if large.MemUsage() <= small.MemUsage() {
+		t.Fatal("Expected a larger Filter to use more memory.")
+	}
+}